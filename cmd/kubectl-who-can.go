@@ -16,6 +16,9 @@ func main() {
 		os.Exit(1)
 	}
 	if err := root.Execute(); err != nil {
+		if exitErr, ok := err.(*cmd.ExitError); ok {
+			os.Exit(exitErr.Code)
+		}
 		os.Exit(1)
 	}
 }
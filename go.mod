@@ -11,4 +11,5 @@ require (
 	k8s.io/apimachinery v0.0.0-20190612125636-6a5db36e93ad
 	k8s.io/cli-runtime v0.0.0-20190612131021-ced92c4c4749
 	k8s.io/client-go v0.0.0-20190612125919-5c45477a8ae7
+	sigs.k8s.io/yaml v1.1.0
 )
@@ -1,20 +1,45 @@
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
 	authz "k8s.io/api/authorization/v1"
 	clientauthz "k8s.io/client-go/kubernetes/typed/authorization/v1"
 )
 
 // AccessChecker wraps the IsAllowedTo method.
 //
-// IsAllowedTo checks whether the current user is allowed to perform the given action in the specified namespace.
-// Specifying "" as namespace performs check in all namespaces.
+// IsAllowedTo checks whether the current user, or the impersonated user if any, is allowed to perform the given action
+// on the resource of the API group in the specified namespace. Specifying "" as group refers to the core API group, and
+// specifying "" as namespace performs check in all namespaces.
 type AccessChecker interface {
-	IsAllowedTo(verb, resource, namespace string) (bool, error)
+	IsAllowedTo(verb, group, resource, namespace string) (bool, error)
 }
 
 type accessChecker struct {
 	client clientauthz.SelfSubjectAccessReviewInterface
+	// subjectClient checks the access of the impersonated subject, if impersonation is requested.
+	subjectClient clientauthz.SubjectAccessReviewInterface
+	impersonation *impersonation
+	dump          *accessReviewDump
+}
+
+// impersonation refers to the user and groups given by the --as and --as-group flags. They are referenced rather
+// than copied, because the flags are parsed after the AccessChecker has been created.
+type impersonation struct {
+	user   *string
+	groups *[]string
+}
+
+// requested returns `true` if a user or a group to impersonate is given.
+func (i *impersonation) requested() bool {
+	if i == nil {
+		return false
+	}
+	return (i.user != nil && *i.user != "") || (i.groups != nil && len(*i.groups) > 0)
 }
 
 func NewAccessChecker(client clientauthz.SelfSubjectAccessReviewInterface) AccessChecker {
@@ -23,21 +48,128 @@ func NewAccessChecker(client clientauthz.SelfSubjectAccessReviewInterface) Acces
 	}
 }
 
-func (ac *accessChecker) IsAllowedTo(verb, resource, namespace string) (bool, error) {
+// newImpersonatingAccessChecker returns an AccessChecker which, if impersonation is requested, checks the access of
+// the impersonated user and groups with SubjectAccessReviews instead of the access of the current user.
+// Each response is written to the dump.
+func newImpersonatingAccessChecker(client clientauthz.AuthorizationV1Interface, impersonation *impersonation, dump *accessReviewDump) AccessChecker {
+	return &accessChecker{
+		client:        client.SelfSubjectAccessReviews(),
+		subjectClient: client.SubjectAccessReviews(),
+		impersonation: impersonation,
+		dump:          dump,
+	}
+}
+
+func (ac *accessChecker) IsAllowedTo(verb, group, resource, namespace string) (bool, error) {
+	attributes := &authz.ResourceAttributes{
+		Verb:      verb,
+		Group:     group,
+		Resource:  resource,
+		Namespace: namespace,
+	}
+	if ac.impersonation.requested() {
+		return ac.isSubjectAllowedTo(attributes)
+	}
+
 	sar := &authz.SelfSubjectAccessReview{
 		Spec: authz.SelfSubjectAccessReviewSpec{
-			ResourceAttributes: &authz.ResourceAttributes{
-				Verb:      verb,
-				Resource:  resource,
-				Namespace: namespace,
-			},
+			ResourceAttributes: attributes,
 		},
 	}
 
-	sar, err := ac.client.Create(sar)
+	response, err := ac.client.Create(sar)
 	if err != nil {
+		ac.dump.write(attributes, nil, err)
 		return false, err
 	}
+	ac.dump.write(attributes, &response.Status, nil)
+
+	return response.Status.Allowed, nil
+}
+
+// isSubjectAllowedTo checks whether the impersonated user and groups are allowed to perform the given action.
+func (ac *accessChecker) isSubjectAllowedTo(attributes *authz.ResourceAttributes) (bool, error) {
+	sar := &authz.SubjectAccessReview{
+		Spec: authz.SubjectAccessReviewSpec{
+			ResourceAttributes: attributes,
+		},
+	}
+	if ac.impersonation.user != nil {
+		sar.Spec.User = *ac.impersonation.user
+	}
+	if ac.impersonation.groups != nil {
+		sar.Spec.Groups = append(sar.Spec.Groups, *ac.impersonation.groups...)
+	}
+	// The API server adds the group of authenticated users to any impersonated user except the anonymous one.
+	if sar.Spec.User != "" && sar.Spec.User != anonymousUser && !containsString(sar.Spec.Groups, authenticatedGroup) {
+		sar.Spec.Groups = append(sar.Spec.Groups, authenticatedGroup)
+	}
 
-	return sar.Status.Allowed, nil
+	response, err := ac.subjectClient.Create(sar)
+	if err != nil {
+		ac.dump.write(attributes, nil, err)
+		return false, err
+	}
+	ac.dump.write(attributes, &response.Status, nil)
+
+	return response.Status.Allowed, nil
+}
+
+// accessReviewDump writes the responses of SelfSubjectAccessReviews, or SubjectAccessReviews, as JSON lines for debugging, such as of
+// unexpected warnings with webhook authorizers. It is enabled by the hidden --dump-access-reviews flag.
+type accessReviewDump struct {
+	enabled bool
+	out     io.Writer
+	// mu serializes the lines, because the access reviews are run in parallel.
+	mu sync.Mutex
+}
+
+type accessReviewDumpEntry struct {
+	Verb            string `json:"verb"`
+	Group           string `json:"group,omitempty"`
+	Resource        string `json:"resource"`
+	Namespace       string `json:"namespace"`
+	Allowed         bool   `json:"allowed"`
+	Denied          bool   `json:"denied,omitempty"`
+	Reason          string `json:"reason,omitempty"`
+	EvaluationError string `json:"evaluationError,omitempty"`
+	Error           string `json:"error,omitempty"`
+}
+
+func (d *accessReviewDump) write(attributes *authz.ResourceAttributes, status *authz.SubjectAccessReviewStatus, err error) {
+	if d == nil || !d.enabled {
+		return
+	}
+	entry := accessReviewDumpEntry{
+		Verb:      attributes.Verb,
+		Group:     attributes.Group,
+		Resource:  attributes.Resource,
+		Namespace: attributes.Namespace,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	} else if status != nil {
+		entry.Allowed = status.Allowed
+		entry.Denied = status.Denied
+		entry.Reason = status.Reason
+		entry.EvaluationError = status.EvaluationError
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, _ = fmt.Fprintf(d.out, "%s\n", data)
+}
+
+// containsString returns `true` if the given strings contain the string s.
+func containsString(strings []string, s string) bool {
+	for _, str := range strings {
+		if str == s {
+			return true
+		}
+	}
+	return false
 }
@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"bytes"
 	"errors"
 	"github.com/stretchr/testify/assert"
 	authz "k8s.io/api/authorization/v1"
+	rbac "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
 	clientauthz "k8s.io/client-go/kubernetes/typed/authorization/v1"
@@ -43,7 +45,7 @@ func TestIsAllowed(t *testing.T) {
 			client := newClient(tt.reactionFunc)
 
 			// when
-			allowed, err := NewAccessChecker(client).IsAllowedTo("list", "roles", "")
+			allowed, err := NewAccessChecker(client).IsAllowedTo("list", rbac.GroupName, "roles", "")
 
 			// then
 			assert.Equal(t, tt.allowed, allowed)
@@ -69,3 +71,135 @@ func newSelfSubjectAccessReviewsReactionFunc(allowed bool, err error) clienttest
 		return true, sar, err
 	}
 }
+
+func TestIsAllowed_Dump(t *testing.T) {
+	data := []struct {
+		scenario     string
+		reactionFunc clienttesting.ReactionFunc
+		enabled      bool
+
+		dump string
+	}{
+		{
+			scenario: "Should dump SSAR's status",
+			reactionFunc: func(action clienttesting.Action) (bool, runtime.Object, error) {
+				return true, &authz.SelfSubjectAccessReview{
+					Status: authz.SubjectAccessReviewStatus{
+						Allowed:         false,
+						Reason:          "no RBAC policy matched",
+						EvaluationError: "webhook: connection refused",
+					},
+				}, nil
+			},
+			enabled: true,
+			dump: `{"verb":"list","group":"rbac.authorization.k8s.io","resource":"roles","namespace":"foo","allowed":false,` +
+				`"reason":"no RBAC policy matched","evaluationError":"webhook: connection refused"}` + "\n",
+		},
+		{
+			scenario:     "Should dump error when API request fails",
+			reactionFunc: newSelfSubjectAccessReviewsReactionFunc(false, errors.New("api is down")),
+			enabled:      true,
+			dump:         `{"verb":"list","group":"rbac.authorization.k8s.io","resource":"roles","namespace":"foo","allowed":false,"error":"api is down"}` + "\n",
+		},
+		{
+			scenario:     "Should not dump when disabled",
+			reactionFunc: newSelfSubjectAccessReviewsReactionFunc(true, nil),
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			var out bytes.Buffer
+			dump := &accessReviewDump{enabled: tt.enabled, out: &out}
+			client := fake.NewSimpleClientset()
+			client.Fake.PrependReactor("create", "selfsubjectaccessreviews", tt.reactionFunc)
+
+			// when
+			_, _ = newImpersonatingAccessChecker(client.AuthorizationV1(), nil, dump).IsAllowedTo("list", rbac.GroupName, "roles", "foo")
+
+			// then
+			assert.Equal(t, tt.dump, out.String())
+		})
+	}
+}
+
+func TestIsAllowed_Impersonation(t *testing.T) {
+	user := "jane"
+	groups := []string{"auditors"}
+	anonymous := "system:anonymous"
+	empty := ""
+
+	data := []struct {
+		scenario      string
+		impersonation *impersonation
+
+		review clienttesting.Action
+	}{
+		{
+			scenario:      "Should check access of current user when no impersonation is requested",
+			impersonation: &impersonation{user: &empty, groups: &[]string{}},
+			review: clienttesting.NewRootCreateAction(authz.SchemeGroupVersion.WithResource("selfsubjectaccessreviews"),
+				&authz.SelfSubjectAccessReview{
+					Spec: authz.SelfSubjectAccessReviewSpec{
+						ResourceAttributes: &authz.ResourceAttributes{Verb: "list", Group: rbac.GroupName, Resource: "roles", Namespace: "foo"},
+					},
+				}),
+		},
+		{
+			scenario:      "Should check access of impersonated user and groups",
+			impersonation: &impersonation{user: &user, groups: &groups},
+			review: clienttesting.NewRootCreateAction(authz.SchemeGroupVersion.WithResource("subjectaccessreviews"),
+				&authz.SubjectAccessReview{
+					Spec: authz.SubjectAccessReviewSpec{
+						ResourceAttributes: &authz.ResourceAttributes{Verb: "list", Group: rbac.GroupName, Resource: "roles", Namespace: "foo"},
+						User:               "jane",
+						Groups:             []string{"auditors", "system:authenticated"},
+					},
+				}),
+		},
+		{
+			scenario:      "Should not add group of authenticated users twice",
+			impersonation: &impersonation{user: &user, groups: &[]string{"system:authenticated", "auditors"}},
+			review: clienttesting.NewRootCreateAction(authz.SchemeGroupVersion.WithResource("subjectaccessreviews"),
+				&authz.SubjectAccessReview{
+					Spec: authz.SubjectAccessReviewSpec{
+						ResourceAttributes: &authz.ResourceAttributes{Verb: "list", Group: rbac.GroupName, Resource: "roles", Namespace: "foo"},
+						User:               "jane",
+						Groups:             []string{"system:authenticated", "auditors"},
+					},
+				}),
+		},
+		{
+			scenario:      "Should not add group of authenticated users to anonymous user",
+			impersonation: &impersonation{user: &anonymous, groups: &[]string{"system:unauthenticated"}},
+			review: clienttesting.NewRootCreateAction(authz.SchemeGroupVersion.WithResource("subjectaccessreviews"),
+				&authz.SubjectAccessReview{
+					Spec: authz.SubjectAccessReviewSpec{
+						ResourceAttributes: &authz.ResourceAttributes{Verb: "list", Group: rbac.GroupName, Resource: "roles", Namespace: "foo"},
+						User:               "system:anonymous",
+						Groups:             []string{"system:unauthenticated"},
+					},
+				}),
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			client := fake.NewSimpleClientset()
+			client.Fake.PrependReactor("create", "subjectaccessreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				return true, &authz.SubjectAccessReview{Status: authz.SubjectAccessReviewStatus{Allowed: true}}, nil
+			})
+			client.Fake.PrependReactor("create", "selfsubjectaccessreviews", newSelfSubjectAccessReviewsReactionFunc(true, nil))
+
+			// when
+			allowed, err := newImpersonatingAccessChecker(client.AuthorizationV1(), tt.impersonation, nil).IsAllowedTo("list", rbac.GroupName, "roles", "foo")
+
+			// then
+			assert.NoError(t, err)
+			assert.True(t, allowed)
+			assert.Equal(t, []clienttesting.Action{tt.review}, client.Actions())
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+
+	rbac "k8s.io/api/rbac/v1"
+)
+
+// clusterAdminRole is the built-in ClusterRole which grants all actions on all resources.
+const clusterAdminRole = "cluster-admin"
+
+// adminFinding is a subject bound to an admin role by a binding.
+type adminFinding struct {
+	subject     rbac.Subject
+	bindingKind string
+	binding     string
+	// namespace is the namespace of the RoleBinding to which the admin access is restricted.
+	// It is empty for ClusterRoleBindings, which grant admin access to the whole cluster.
+	namespace string
+	roleRef   rbac.RoleRef
+}
+
+// runAdminReport prints the subjects bound to the cluster-admin ClusterRole or to a role with a rule which grants
+// all verbs on all resources of all API groups. Cluster admins, bound by ClusterRoleBindings, are listed separately
+// from namespace admins, bound by RoleBindings in the namespace of the query.
+func (w *whoCan) runAdminReport(args []string) error {
+	if len(args) > 0 {
+		return errors.New("--who-is-admin cannot be used with VERB and TYPE")
+	}
+	if err := w.resolveNamespace(); err != nil {
+		return err
+	}
+	if err := w.namespaceValidator.Validate(w.namespace); err != nil {
+		return fmt.Errorf("validating namespace: %v", err)
+	}
+
+	snapshot, err := loadRBACSnapshot(w.clientRBAC, w.namespace)
+	if err != nil {
+		return err
+	}
+	findings, err := findAdmins(snapshot)
+	if err != nil {
+		return err
+	}
+	w.printAdmins(findings)
+	return nil
+}
+
+// findAdmins returns the subjects of the bindings which refer to admin roles, ordered by subject and binding.
+func findAdmins(snapshot *rbacSnapshot) ([]adminFinding, error) {
+	var findings []adminFinding
+	add := func(bindingKind, binding, namespace string, roleRef rbac.RoleRef, subjects []rbac.Subject) error {
+		admin, err := isAdminRole(snapshot, roleRef, namespace)
+		if err != nil || !admin {
+			return err
+		}
+		for _, s := range subjects {
+			findings = append(findings, adminFinding{
+				subject:     rbac.Subject{Kind: s.Kind, Name: s.Name, Namespace: s.Namespace},
+				bindingKind: bindingKind,
+				binding:     binding,
+				namespace:   namespace,
+				roleRef:     roleRef,
+			})
+		}
+		return nil
+	}
+
+	for _, crb := range snapshot.clusterRoleBindings {
+		if err := add("ClusterRoleBinding", crb.Name, "", crb.RoleRef, crb.Subjects); err != nil {
+			return nil, err
+		}
+	}
+	for _, rb := range snapshot.roleBindings {
+		if err := add("RoleBinding", rb.Name, rb.Namespace, rb.RoleRef, rb.Subjects); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.subject.Kind != b.subject.Kind {
+			return a.subject.Kind < b.subject.Kind
+		}
+		if a.subject.Namespace != b.subject.Namespace {
+			return a.subject.Namespace < b.subject.Namespace
+		}
+		if a.subject.Name != b.subject.Name {
+			return a.subject.Name < b.subject.Name
+		}
+		if a.namespace != b.namespace {
+			return a.namespace < b.namespace
+		}
+		return a.binding < b.binding
+	})
+	return findings, nil
+}
+
+// isAdminRole returns `true` if the role referenced by a binding in the given namespace is the cluster-admin
+// ClusterRole or has a rule which grants all actions.
+func isAdminRole(snapshot *rbacSnapshot, roleRef rbac.RoleRef, namespace string) (bool, error) {
+	if roleRef.Kind == "ClusterRole" && roleRef.Name == clusterAdminRole {
+		return true, nil
+	}
+	rules, err := snapshot.rulesOf(roleRef, namespace)
+	if err != nil {
+		return false, err
+	}
+	for _, rule := range rules {
+		if isAdminRule(rule) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isAdminRule returns `true` if the given rule grants all verbs on all resources of all API groups.
+func isAdminRule(rule rbac.PolicyRule) bool {
+	contains := func(values []string, value string) bool {
+		for _, v := range values {
+			if v == value {
+				return true
+			}
+		}
+		return false
+	}
+	return contains(rule.Verbs, rbac.VerbAll) &&
+		contains(rule.Resources, rbac.ResourceAll) &&
+		contains(rule.APIGroups, rbac.APIGroupAll)
+}
+
+func (w *whoCan) printAdmins(findings []adminFinding) {
+	var clusterAdmins, namespaceAdmins []adminFinding
+	for _, f := range findings {
+		if f.bindingKind == "ClusterRoleBinding" {
+			clusterAdmins = append(clusterAdmins, f)
+		} else {
+			namespaceAdmins = append(namespaceAdmins, f)
+		}
+	}
+
+	wr := new(tabwriter.Writer)
+	wr.Init(w.Out, 0, 8, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(wr, "Cluster admins:")
+	if len(clusterAdmins) == 0 {
+		_, _ = fmt.Fprintln(wr, "No subjects found who are bound to an admin role by a ClusterRoleBinding")
+	} else {
+		_, _ = fmt.Fprintln(wr, "SUBJECT\tTYPE\tSA-NAMESPACE\tCLUSTERROLEBINDING\tROLE")
+		for _, f := range clusterAdmins {
+			_, _ = fmt.Fprintf(wr, "%s\t%s\t%s\t%s\t%s/%s\n", f.subject.Name, f.subject.Kind, f.subject.Namespace,
+				f.binding, f.roleRef.Kind, f.roleRef.Name)
+		}
+	}
+
+	_, _ = fmt.Fprintln(wr)
+	_, _ = fmt.Fprintln(wr, "Namespace admins:")
+	if len(namespaceAdmins) == 0 {
+		_, _ = fmt.Fprintln(wr, "No subjects found who are bound to an admin role by a RoleBinding")
+	} else {
+		_, _ = fmt.Fprintln(wr, "SUBJECT\tTYPE\tSA-NAMESPACE\tNAMESPACE\tROLEBINDING\tROLE")
+		for _, f := range namespaceAdmins {
+			_, _ = fmt.Fprintf(wr, "%s\t%s\t%s\t%s\t%s\t%s/%s\n", f.subject.Name, f.subject.Kind, f.subject.Namespace,
+				f.namespace, f.binding, f.roleRef.Kind, f.roleRef.Name)
+		}
+	}
+	_ = wr.Flush()
+}
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+)
+
+func TestIsAdminRule(t *testing.T) {
+	data := []struct {
+		scenario string
+		rule     rbac.PolicyRule
+		admin    bool
+	}{
+		{
+			scenario: "Should return true for rule which grants all verbs on all resources of all API groups",
+			rule:     rbac.PolicyRule{APIGroups: []string{"*"}, Verbs: []string{"*"}, Resources: []string{"*"}},
+			admin:    true,
+		},
+		{
+			scenario: "Should return true for rule with wildcards among other values",
+			rule:     rbac.PolicyRule{APIGroups: []string{"", "*"}, Verbs: []string{"get", "*"}, Resources: []string{"pods", "*"}},
+			admin:    true,
+		},
+		{
+			scenario: "Should return false for rule restricted to an API group",
+			rule:     rbac.PolicyRule{APIGroups: []string{"apps"}, Verbs: []string{"*"}, Resources: []string{"*"}},
+		},
+		{
+			scenario: "Should return false for rule restricted to some verbs",
+			rule:     rbac.PolicyRule{APIGroups: []string{"*"}, Verbs: []string{"get", "list", "watch"}, Resources: []string{"*"}},
+		},
+		{
+			scenario: "Should return false for rule restricted to a resource",
+			rule:     rbac.PolicyRule{APIGroups: []string{"*"}, Verbs: []string{"*"}, Resources: []string{"secrets"}},
+		},
+		{
+			scenario: "Should return false for rule of non-resource URLs",
+			rule:     rbac.PolicyRule{Verbs: []string{"*"}, NonResourceURLs: []string{"*"}},
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			assert.Equal(t, tt.admin, isAdminRule(tt.rule))
+		})
+	}
+}
+
+func TestFindAdmins(t *testing.T) {
+	// given
+	wildcardRule := rbac.PolicyRule{APIGroups: []string{"*"}, Verbs: []string{"*"}, Resources: []string{"*"}}
+	client := fake.NewSimpleClientset(
+		// The rules of cluster-admin are not checked, the ClusterRole is matched by its name.
+		&rbac.ClusterRole{ObjectMeta: meta.ObjectMeta{Name: "cluster-admin"}},
+		&rbac.ClusterRole{
+			ObjectMeta: meta.ObjectMeta{Name: "superuser"},
+			Rules:      []rbac.PolicyRule{{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"pods"}}, wildcardRule},
+		},
+		&rbac.ClusterRole{
+			ObjectMeta: meta.ObjectMeta{Name: "apps-admin"},
+			Rules:      []rbac.PolicyRule{{APIGroups: []string{"apps"}, Verbs: []string{"*"}, Resources: []string{"*"}}},
+		},
+		&rbac.Role{
+			ObjectMeta: meta.ObjectMeta{Name: "owner", Namespace: "bar"},
+			Rules:      []rbac.PolicyRule{wildcardRule},
+		},
+		&rbac.Role{
+			ObjectMeta: meta.ObjectMeta{Name: "owner", Namespace: "foo"},
+			Rules:      []rbac.PolicyRule{{APIGroups: []string{""}, Verbs: []string{"*"}, Resources: []string{"*"}}},
+		},
+		&rbac.ClusterRoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "cluster-admins"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+			Subjects:   []rbac.Subject{{Kind: rbac.GroupKind, Name: "system:masters"}, {Kind: rbac.UserKind, Name: "alice"}},
+		},
+		&rbac.ClusterRoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "ci-is-superuser"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "superuser"},
+			Subjects:   []rbac.Subject{{Kind: rbac.ServiceAccountKind, Name: "ci", Namespace: "tools"}},
+		},
+		&rbac.ClusterRoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "bob-is-apps-admin"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "apps-admin"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "bob"}},
+		},
+		&rbac.ClusterRoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "missing-role"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "missing"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "eve"}},
+		},
+		&rbac.RoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "alice-is-admin", Namespace: "foo"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "alice"}},
+		},
+		&rbac.RoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "bob-is-owner", Namespace: "bar"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "owner"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "bob"}},
+		},
+		&rbac.RoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "bob-is-owner", Namespace: "foo"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "owner"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "bob"}},
+		},
+	)
+	snapshot, err := loadRBACSnapshot(client.RbacV1(), meta.NamespaceAll)
+	require.NoError(t, err)
+
+	// when
+	findings, err := findAdmins(snapshot)
+
+	// then
+	require.NoError(t, err)
+	clusterAdmin := rbac.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"}
+	assert.Equal(t, []adminFinding{
+		{
+			subject:     rbac.Subject{Kind: rbac.GroupKind, Name: "system:masters"},
+			bindingKind: "ClusterRoleBinding", binding: "cluster-admins", roleRef: clusterAdmin,
+		},
+		{
+			subject:     rbac.Subject{Kind: rbac.ServiceAccountKind, Name: "ci", Namespace: "tools"},
+			bindingKind: "ClusterRoleBinding", binding: "ci-is-superuser", roleRef: rbac.RoleRef{Kind: "ClusterRole", Name: "superuser"},
+		},
+		{
+			subject:     rbac.Subject{Kind: rbac.UserKind, Name: "alice"},
+			bindingKind: "ClusterRoleBinding", binding: "cluster-admins", roleRef: clusterAdmin,
+		},
+		{
+			subject:     rbac.Subject{Kind: rbac.UserKind, Name: "alice"},
+			bindingKind: "RoleBinding", binding: "alice-is-admin", namespace: "foo", roleRef: clusterAdmin,
+		},
+		{
+			subject:     rbac.Subject{Kind: rbac.UserKind, Name: "bob"},
+			bindingKind: "RoleBinding", binding: "bob-is-owner", namespace: "bar", roleRef: rbac.RoleRef{Kind: "Role", Name: "owner"},
+		},
+	}, findings)
+}
+
+func TestWhoCan_printAdmins(t *testing.T) {
+	data := []struct {
+		scenario string
+		findings []adminFinding
+		output   string
+	}{
+		{
+			scenario: "Should print cluster admins and namespace admins separately",
+			findings: []adminFinding{
+				{
+					subject:     rbac.Subject{Kind: rbac.ServiceAccountKind, Name: "ci", Namespace: "tools"},
+					bindingKind: "ClusterRoleBinding", binding: "ci-is-admin", roleRef: rbac.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+				},
+				{
+					subject:     rbac.Subject{Kind: rbac.UserKind, Name: "bob"},
+					bindingKind: "RoleBinding", binding: "bob-is-owner", namespace: "bar", roleRef: rbac.RoleRef{Kind: "Role", Name: "owner"},
+				},
+			},
+			output: `Cluster admins:
+SUBJECT  TYPE            SA-NAMESPACE  CLUSTERROLEBINDING  ROLE
+ci       ServiceAccount  tools         ci-is-admin         ClusterRole/cluster-admin
+
+Namespace admins:
+SUBJECT  TYPE  SA-NAMESPACE  NAMESPACE  ROLEBINDING   ROLE
+bob      User                bar        bob-is-owner  Role/owner
+`,
+		},
+		{
+			scenario: "Should print message when there are no admins",
+			output: `Cluster admins:
+No subjects found who are bound to an admin role by a ClusterRoleBinding
+
+Namespace admins:
+No subjects found who are bound to an admin role by a RoleBinding
+`,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			streams, _, out, _ := clioptions.NewTestIOStreams()
+			wc := whoCan{IOStreams: streams}
+
+			// when
+			wc.printAdmins(tt.findings)
+
+			// then
+			assert.Equal(t, tt.output, out.String())
+		})
+	}
+}
+
+func TestWhoCan_runAdminReport_WithArgs(t *testing.T) {
+	wc := whoCan{}
+
+	err := wc.runAdminReport([]string{"get", "pods"})
+
+	assert.Equal(t, errors.New("--who-is-admin cannot be used with VERB and TYPE"), err)
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"reflect"
+
+	"github.com/golang/glog"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// aggregation resolves the effective rules of aggregated ClusterRoles from the listed ClusterRoles, the same way
+// as the controller manager combines the rules of the ClusterRoles selected by an aggregationRule. This matters
+// when the rules of an aggregated ClusterRole have not been populated, such as when it has just been created.
+// The ClusterRoles selected by each aggregationRule are looked up once per query.
+type aggregation struct {
+	clusterRoles []rbac.ClusterRole
+	children     map[string][]rbac.ClusterRole
+}
+
+func newAggregation(clusterRoles []rbac.ClusterRole) *aggregation {
+	return &aggregation{
+		clusterRoles: clusterRoles,
+		children:     make(map[string][]rbac.ClusterRole),
+	}
+}
+
+// rulesOf returns the rules of the given ClusterRole followed by the rules of the ClusterRoles selected by its
+// aggregationRule, which may be aggregated ClusterRoles themselves. Rules are included once, and a ClusterRole
+// which selects itself, directly or through others, is not expanded again.
+func (a *aggregation) rulesOf(cr rbac.ClusterRole) []rbac.PolicyRule {
+	return a.expand(cr, make(map[string]struct{}), nil)
+}
+
+func (a *aggregation) expand(cr rbac.ClusterRole, visited map[string]struct{}, rules []rbac.PolicyRule) []rbac.PolicyRule {
+	visited[cr.Name] = struct{}{}
+	for _, rule := range cr.Rules {
+		if !containsRule(rules, rule) {
+			rules = append(rules, rule)
+		}
+	}
+	if cr.AggregationRule == nil {
+		return rules
+	}
+	for _, child := range a.selectedBy(cr) {
+		if _, ok := visited[child.Name]; ok {
+			continue
+		}
+		rules = a.expand(child, visited, rules)
+	}
+	return rules
+}
+
+// selectedBy returns the ClusterRoles selected by the aggregationRule of the given ClusterRole.
+func (a *aggregation) selectedBy(cr rbac.ClusterRole) []rbac.ClusterRole {
+	if children, ok := a.children[cr.Name]; ok {
+		return children
+	}
+	var children []rbac.ClusterRole
+	for _, s := range cr.AggregationRule.ClusterRoleSelectors {
+		selector, err := meta.LabelSelectorAsSelector(&s)
+		if err != nil {
+			glog.V(3).Infof("Ignoring invalid aggregation selector of ClusterRole [%s]: %v", cr.Name, err)
+			continue
+		}
+		for _, candidate := range a.clusterRoles {
+			if candidate.Name != cr.Name && selector.Matches(labels.Set(candidate.Labels)) && !containsClusterRole(children, candidate.Name) {
+				children = append(children, candidate)
+			}
+		}
+	}
+	a.children[cr.Name] = children
+	return children
+}
+
+func containsRule(rules []rbac.PolicyRule, rule rbac.PolicyRule) bool {
+	for _, r := range rules {
+		if reflect.DeepEqual(r, rule) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsClusterRole(clusterRoles []rbac.ClusterRole, name string) bool {
+	for _, cr := range clusterRoles {
+		if cr.Name == name {
+			return true
+		}
+	}
+	return false
+}
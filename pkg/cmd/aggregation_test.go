@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+func TestWhoCan_filterClusterRoles_aggregationRule(t *testing.T) {
+	// given
+	wc := whoCan{
+		verb:      "get",
+		resource:  "pods",
+		r:         make(map[role]struct{}),
+		rules:     make(map[role][]rbac.PolicyRule),
+		wildcards: make(map[role]bool),
+	}
+	aggregateToView := map[string]string{"rbac.authorization.k8s.io/aggregate-to-view": "true"}
+	getPods := rbac.PolicyRule{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"pods"}}
+	listServices := rbac.PolicyRule{APIGroups: []string{""}, Verbs: []string{"list"}, Resources: []string{"services"}}
+	clusterRoles := &rbac.ClusterRoleList{
+		Items: []rbac.ClusterRole{
+			{
+				ObjectMeta: meta.ObjectMeta{Name: "view"},
+				AggregationRule: &rbac.AggregationRule{ClusterRoleSelectors: []meta.LabelSelector{
+					{MatchLabels: aggregateToView},
+				}},
+			},
+			{
+				ObjectMeta: meta.ObjectMeta{Name: "view-services", Labels: aggregateToView},
+				Rules:      []rbac.PolicyRule{listServices},
+			},
+			{
+				ObjectMeta: meta.ObjectMeta{Name: "view-pods", Labels: aggregateToView},
+				Rules:      []rbac.PolicyRule{getPods},
+			},
+			{
+				ObjectMeta: meta.ObjectMeta{Name: "edit"},
+				AggregationRule: &rbac.AggregationRule{ClusterRoleSelectors: []meta.LabelSelector{
+					{MatchLabels: map[string]string{"rbac.authorization.k8s.io/aggregate-to-edit": "true"}},
+				}},
+			},
+		},
+	}
+
+	// when
+	wc.filterClusterRoles(clusterRoles)
+
+	// then
+	assert.Equal(t, roles{
+		{name: "view", isClusterRole: true}:      {},
+		{name: "view-pods", isClusterRole: true}: {},
+	}, wc.r)
+	assert.Equal(t, []rbac.PolicyRule{listServices, getPods}, wc.rules[role{name: "view", isClusterRole: true}])
+	assert.Equal(t, roles{{name: "view", isClusterRole: true}: {}}, wc.aggregatedRoles)
+}
+
+func TestAggregation_rulesOf(t *testing.T) {
+	getPods := rbac.PolicyRule{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"pods"}}
+	listPods := rbac.PolicyRule{APIGroups: []string{""}, Verbs: []string{"list"}, Resources: []string{"pods"}}
+	aggregatedBy := func(name string) *rbac.AggregationRule {
+		return &rbac.AggregationRule{ClusterRoleSelectors: []meta.LabelSelector{
+			{MatchLabels: map[string]string{"aggregate-to": name}},
+		}}
+	}
+
+	clusterRoles := []rbac.ClusterRole{
+		{
+			// The rules populated by the controller manager are not repeated.
+			ObjectMeta:      meta.ObjectMeta{Name: "admin"},
+			AggregationRule: aggregatedBy("admin"),
+			Rules:           []rbac.PolicyRule{getPods},
+		},
+		{
+			ObjectMeta:      meta.ObjectMeta{Name: "edit", Labels: map[string]string{"aggregate-to": "admin"}},
+			AggregationRule: aggregatedBy("edit"),
+		},
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "pod-reader", Labels: map[string]string{"aggregate-to": "edit"}},
+			Rules:      []rbac.PolicyRule{getPods, listPods},
+		},
+		{
+			ObjectMeta:      meta.ObjectMeta{Name: "ping", Labels: map[string]string{"aggregate-to": "pong"}},
+			AggregationRule: aggregatedBy("ping"),
+			Rules:           []rbac.PolicyRule{getPods},
+		},
+		{
+			ObjectMeta:      meta.ObjectMeta{Name: "pong", Labels: map[string]string{"aggregate-to": "ping"}},
+			AggregationRule: aggregatedBy("pong"),
+			Rules:           []rbac.PolicyRule{listPods},
+		},
+	}
+
+	data := []struct {
+		scenario    string
+		clusterRole rbac.ClusterRole
+		rules       []rbac.PolicyRule
+	}{
+		{
+			scenario:    "Should include rules of nested aggregated ClusterRoles once",
+			clusterRole: clusterRoles[0],
+			rules:       []rbac.PolicyRule{getPods, listPods},
+		},
+		{
+			scenario:    "Should stop at ClusterRoles which select each other",
+			clusterRole: clusterRoles[3],
+			rules:       []rbac.PolicyRule{getPods, listPods},
+		},
+		{
+			scenario: "Should return own rules when no ClusterRole is selected",
+			clusterRole: rbac.ClusterRole{
+				ObjectMeta:      meta.ObjectMeta{Name: "monitoring"},
+				AggregationRule: aggregatedBy("monitoring"),
+			},
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			assert.Equal(t, tt.rules, newAggregation(clusterRoles).rulesOf(tt.clusterRole))
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+
+	rbac "k8s.io/api/rbac/v1"
+)
+
+// categoryGrant is a subject granted the queried action on a resource of the queried category by a binding.
+type categoryGrant struct {
+	resource    ResolvedResource
+	bindingKind string
+	binding     string
+	// namespace is the namespace of a RoleBinding, and empty for a ClusterRoleBinding.
+	namespace string
+	subject   rbac.Subject
+}
+
+// runCategory checks the verb on each resource of the category given by --category, and prints the subjects
+// granted it with the resource in a single table.
+func (w *whoCan) runCategory(args []string) error {
+	if len(args) != 1 {
+		return errors.New("--category requires VERB only, such as: kubectl who-can get --category all")
+	}
+	w.verb = args[0]
+	if err := w.resolveNamespace(); err != nil {
+		return err
+	}
+	if err := w.namespaceValidator.Validate(w.namespace); err != nil {
+		return fmt.Errorf("validating namespace: %v", err)
+	}
+
+	resources, err := w.resourceResolver.ResourcesInCategory(w.category)
+	if err != nil {
+		return fmt.Errorf("resolving category: %v", err)
+	}
+
+	// Namespaces are only checked if any of the resources is namespaced, because RoleBindings grant nothing
+	// on cluster-scoped resources.
+	for _, resource := range resources {
+		if resource.Namespaced {
+			warnings, err := w.checkAPIAccess()
+			if err != nil {
+				return fmt.Errorf("checking API access: %v", err)
+			}
+			w.printAPIAccessWarnings(warnings)
+			break
+		}
+	}
+
+	grants, err := w.findCategoryGrants(resources)
+	if err != nil {
+		return err
+	}
+	w.printCategoryGrants(grants)
+	return nil
+}
+
+// findCategoryGrants matches the bindings for the verb on each of the given resources. The RoleBindings are only
+// matched for namespaced resources, so that neither Roles nor RoleBindings are listed for cluster-scoped resources.
+func (w *whoCan) findCategoryGrants(resources []ResolvedResource) ([]categoryGrant, error) {
+	var grants []categoryGrant
+	for _, resource := range resources {
+		w.resource, w.apiGroup, w.namespacedResource = resource.Resource, resource.Group, resource.Namespaced
+		w.rules = make(map[role][]rbac.PolicyRule)
+		w.wildcards = make(map[role]bool)
+		w.apiGroups = make(map[role]map[string]struct{})
+		w.nearMisses = make(map[role][]string)
+
+		roleBindings, clusterRoleBindings, err := w.getBindingsInScope()
+		if err != nil {
+			return nil, fmt.Errorf("checking %s %s: %v", w.verb, resource, err)
+		}
+		roleBindings, clusterRoleBindings = w.normalizeBindingSubjects(roleBindings, clusterRoleBindings)
+		roleBindings, clusterRoleBindings = w.excludeServiceAccounts(roleBindings, clusterRoleBindings)
+		roleBindings, clusterRoleBindings = w.filterSubjects(roleBindings, clusterRoleBindings)
+
+		for _, r := range w.roleBindingRows(roleBindings) {
+			grants = append(grants, categoryGrant{resource: resource, bindingKind: "RoleBinding", binding: r.binding, namespace: r.namespace, subject: r.subject})
+		}
+		for _, r := range w.clusterRoleBindingRows(clusterRoleBindings) {
+			grants = append(grants, categoryGrant{resource: resource, bindingKind: "ClusterRoleBinding", binding: r.binding, subject: r.subject})
+		}
+	}
+	sort.SliceStable(grants, func(i, j int) bool {
+		a, b := grants[i], grants[j]
+		if a.resource.String() != b.resource.String() {
+			return a.resource.String() < b.resource.String()
+		}
+		// ClusterRoleBindings, which grant the action in all namespaces, are listed first.
+		if a.bindingKind != b.bindingKind {
+			return a.bindingKind == "ClusterRoleBinding"
+		}
+		if a.namespace != b.namespace {
+			return a.namespace < b.namespace
+		}
+		return a.binding < b.binding
+	})
+	return grants, nil
+}
+
+// getBindingsInScope returns the bindings which grant the action on the resource in its scope.
+// A RoleBinding which references a ClusterRole with rules for a cluster-scoped resource grants nothing,
+// hence only ClusterRoles and ClusterRoleBindings are matched for cluster-scoped resources.
+func (w *whoCan) getBindingsInScope() ([]rbac.RoleBinding, []rbac.ClusterRoleBinding, error) {
+	if w.namespacedResource {
+		return w.getBindings()
+	}
+	w.r = make(map[role]struct{}, 10)
+	if err := w.getClusterRoles(); err != nil {
+		return nil, nil, fmt.Errorf("getting ClusterRoles: %v", err)
+	}
+	clusterRoleBindings, err := w.getClusterRoleBindings()
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting ClusterRoleBindings: %v", err)
+	}
+	return nil, clusterRoleBindings, nil
+}
+
+func (w *whoCan) printCategoryGrants(grants []categoryGrant) {
+	if len(grants) == 0 {
+		_, _ = fmt.Fprintf(w.Out, "No subjects found with permissions to %s resources in category %s\n", w.verb, w.category)
+		return
+	}
+
+	wr := new(tabwriter.Writer)
+	wr.Init(w.Out, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintln(wr, "RESOURCE\tSCOPE\tBINDING\tNAMESPACE\tSUBJECT\tTYPE\tSA-NAMESPACE")
+	for _, g := range grants {
+		scope := "cluster"
+		if g.resource.Namespaced {
+			scope = "namespaced"
+		}
+		_, _ = fmt.Fprintf(wr, "%s\t%s\t%s/%s\t%s\t%s\t%s\t%s\n", g.resource, scope, g.bindingKind, g.binding, g.namespace,
+			g.subject.Name, g.subject.Kind, g.subject.Namespace)
+	}
+	_ = wr.Flush()
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbac "k8s.io/api/rbac/v1"
+	apismeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+)
+
+func TestResourceResolver_ResourcesInCategory(t *testing.T) {
+	// given
+	client := fake.NewSimpleClientset()
+	client.Resources = []*apismeta.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []apismeta.APIResource{
+				{Version: "v1", Name: "pods", ShortNames: []string{"po"}, Namespaced: true, Categories: []string{"all"}, Verbs: []string{"list", "get"}},
+				{Version: "v1", Name: "pods/log", Namespaced: true, Verbs: []string{"get"}},
+				{Version: "v1", Name: "secrets", Namespaced: true, Verbs: []string{"list", "get"}},
+			},
+		},
+		{
+			GroupVersion: "example.com/v1",
+			APIResources: []apismeta.APIResource{
+				{Name: "clusterwidgets", ShortNames: []string{"cw"}, Namespaced: false, Categories: []string{"all", "widgets"}, Verbs: []string{"list", "get"}},
+			},
+		},
+	}
+	resolver := NewResourceResolver(client.Discovery(), new(mapperMock))
+
+	// when
+	resources, err := resolver.ResourcesInCategory("all")
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []ResolvedResource{
+		{Resource: "clusterwidgets", Group: "example.com", Version: "v1", Namespaced: false},
+		{Resource: "pods", Version: "v1", Namespaced: true},
+	}, resources)
+
+	// when
+	_, err = resolver.ResourcesInCategory("unknown")
+
+	// then
+	assert.Equal(t, errors.New("no resources found in category \"unknown\""), err)
+}
+
+func TestWhoCan_runCategory(t *testing.T) {
+	// given
+	client := fake.NewSimpleClientset(
+		&rbac.ClusterRole{
+			ObjectMeta: apismeta.ObjectMeta{Name: "deleter"},
+			Rules: []rbac.PolicyRule{
+				{APIGroups: []string{""}, Verbs: []string{"delete"}, Resources: []string{"pods"}},
+				{APIGroups: []string{""}, Verbs: []string{"delete"}, Resources: []string{"persistentvolumes"}},
+			},
+		},
+		&rbac.RoleBinding{
+			ObjectMeta: apismeta.ObjectMeta{Name: "alice-can-delete", Namespace: "foo"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "deleter"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "alice"}},
+		},
+		&rbac.ClusterRoleBinding{
+			ObjectMeta: apismeta.ObjectMeta{Name: "bob-can-delete"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "deleter"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "bob"}},
+		},
+	)
+
+	resourceResolver := new(resourceResolverMock)
+	resourceResolver.On("ResourcesInCategory", "storage").Return([]ResolvedResource{
+		{Resource: "persistentvolumes", Namespaced: false},
+		{Resource: "pods", Namespaced: true},
+	}, nil)
+	namespaceValidator := new(namespaceValidatorMock)
+	namespaceValidator.On("Validate", "foo").Return(nil)
+	accessChecker := new(accessCheckerMock)
+	for _, resource := range namespacedAPIAccess {
+		accessChecker.On("IsAllowedTo", "list", rbac.GroupName, resource, "foo").Return(true, nil)
+	}
+
+	streams, _, out, _ := clioptions.NewTestIOStreams()
+	namespace := "foo"
+	wc := whoCan{
+		category:           "storage",
+		configFlags:        &clioptions.ConfigFlags{Namespace: &namespace},
+		clientRBAC:         client.RbacV1(),
+		resourceResolver:   resourceResolver,
+		namespaceValidator: namespaceValidator,
+		accessChecker:      accessChecker,
+		IOStreams:          streams,
+	}
+
+	// when
+	err := wc.run([]string{"delete"})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, `RESOURCE           SCOPE       BINDING                            NAMESPACE  SUBJECT  TYPE  SA-NAMESPACE
+persistentvolumes  cluster     ClusterRoleBinding/bob-can-delete             bob      User  
+pods               namespaced  ClusterRoleBinding/bob-can-delete             bob      User  
+pods               namespaced  RoleBinding/alice-can-delete       foo        alice    User  
+`, out.String())
+
+	// RoleBindings are listed for the namespaced pods only.
+	var roleBindingLists int
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "list" && action.GetResource().Resource == "rolebindings" {
+			roleBindingLists++
+		}
+	}
+	assert.Equal(t, 1, roleBindingLists)
+	resourceResolver.AssertExpectations(t)
+	accessChecker.AssertExpectations(t)
+}
+
+func TestWhoCan_runCategory_WithType(t *testing.T) {
+	wc := whoCan{category: "all"}
+
+	err := wc.run([]string{"get", "pods"})
+
+	assert.Equal(t, errors.New("--category requires VERB only, such as: kubectl who-can get --category all"), err)
+}
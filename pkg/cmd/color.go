@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	rbac "k8s.io/api/rbac/v1"
+)
+
+const (
+	// colorAuto colors the table if the standard output is a terminal and the NO_COLOR environment variable is not set.
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
+
+// colorModes lists the supported values of the --color flag.
+var colorModes = []string{colorAuto, colorAlways, colorNever}
+
+// The ANSI escape sequences of the colors of the table rows. Each starts a row, hence they are all of the same length,
+// so that the columns of the table are still aligned by the tabwriter. colorDefault starts the rows left uncolored.
+const (
+	colorRed     = "\x1b[31m"
+	colorYellow  = "\x1b[33m"
+	colorMagenta = "\x1b[35m"
+	colorDefault = "\x1b[39m"
+	colorReset   = "\x1b[0m"
+)
+
+// dangerousSubjects are well-known subjects which stand for all users, or which bypass authorization altogether.
+var dangerousSubjects = map[rbac.Subject]bool{
+	{Kind: rbac.GroupKind, Name: mastersGroup}:             true,
+	{Kind: rbac.GroupKind, Name: "system:authenticated"}:   true,
+	{Kind: rbac.GroupKind, Name: "system:unauthenticated"}: true,
+	{Kind: rbac.UserKind, Name: "system:anonymous"}:        true,
+}
+
+// useColor tells whether the table is colored with the given --color mode when it is printed to the given writer.
+func useColor(mode string, out io.Writer) bool {
+	switch mode {
+	case colorAlways:
+		return true
+	case colorNever:
+		return false
+	}
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	return isTerminal(out)
+}
+
+// rowColor returns the escape sequence which starts the given row of the table, or an empty string if the table is
+// not colored. Rows of dangerous subjects are red, rows granted through a wildcard are yellow, and other rows
+// granted cluster-wide are magenta.
+func (w *whoCan) rowColor(r subjectRow) string {
+	if !w.color {
+		return ""
+	}
+	subject := rbac.Subject{Kind: r.subject.Kind, Name: r.subject.Name}
+	switch {
+	case dangerousSubjects[subject]:
+		return colorRed
+	case w.wildcards[newRoleFromRef(&r.roleRef, r.namespace)]:
+		return colorYellow
+	case r.namespace == "":
+		return colorMagenta
+	}
+	return colorDefault
+}
+
+// headerColor returns the escape sequence which starts a header row, or an empty string if the table is not colored.
+func (w *whoCan) headerColor() string {
+	if !w.color {
+		return ""
+	}
+	return colorDefault
+}
+
+// endColor returns the escape sequence which ends a row of the table, or an empty string if the table is not colored.
+func (w *whoCan) endColor() string {
+	if !w.color {
+		return ""
+	}
+	return colorReset
+}
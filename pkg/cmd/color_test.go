@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"testing"
+)
+
+func TestUseColor(t *testing.T) {
+	data := []struct {
+		scenario string
+		mode     string
+		expected bool
+	}{
+		{scenario: "Should color when always", mode: colorAlways, expected: true},
+		{scenario: "Should not color when never", mode: colorNever, expected: false},
+		{scenario: "Should not color when auto and not a terminal", mode: colorAuto, expected: false},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			assert.Equal(t, tt.expected, useColor(tt.mode, &bytes.Buffer{}))
+		})
+	}
+}
+
+func TestWhoCan_output_Color(t *testing.T) {
+	// given
+	roleBindings := []rbac.RoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "view-pods", Namespace: "default"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "view-pods"},
+			Subjects: []rbac.Subject{
+				{Name: "Alice", Kind: "User"},
+				{Name: "system:anonymous", Kind: "User"},
+			},
+		},
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "edit", Namespace: "default"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "edit"},
+			Subjects: []rbac.Subject{
+				{Name: "Bob", Kind: "User"},
+			},
+		},
+	}
+	clusterRoleBindings := []rbac.ClusterRoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "view"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "view"},
+			Subjects: []rbac.Subject{
+				{Name: "Eve", Kind: "User"},
+			},
+		},
+	}
+
+	streams, _, out, _ := clioptions.NewTestIOStreams()
+	wc := whoCan{
+		verb:     "get",
+		resource: "pods",
+		color:    true,
+		wildcards: map[role]bool{
+			{name: "edit", isClusterRole: true}: true,
+		},
+		IOStreams: streams,
+	}
+
+	// when
+	wc.output(roleBindings, clusterRoleBindings)
+
+	// then
+	assert.Equal(t, "\x1b[39mROLEBINDING  NAMESPACE  SUBJECT           TYPE  SA-NAMESPACE\x1b[0m\n"+
+		"\x1b[39mview-pods    default    Alice             User  \x1b[0m\n"+
+		"\x1b[31mview-pods    default    system:anonymous  User  \x1b[0m\n"+
+		"\x1b[33medit         default    Bob               User  \x1b[0m\n"+
+		"\n"+
+		"\x1b[39mCLUSTERROLEBINDING  SUBJECT  TYPE  SA-NAMESPACE\x1b[0m\n"+
+		"\x1b[35mview                Eve      User  \x1b[0m\n", out.String())
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	rbac "k8s.io/api/rbac/v1"
+)
+
+// column is a column of the output selected with --columns.
+type column struct {
+	header string
+	value  func(r subjectRow) string
+}
+
+// columns are the columns which can be selected with --columns in the order in which they are listed.
+var columns = []column{
+	{header: "BINDING", value: func(r subjectRow) string { return r.bindingKind + "/" + r.binding }},
+	{header: "ROLE", value: func(r subjectRow) string { return r.roleRef.Kind + "/" + r.roleRef.Name }},
+	{header: "SUBJECT", value: func(r subjectRow) string { return r.subject.Name }},
+	{header: "KIND", value: func(r subjectRow) string { return r.subject.Kind }},
+	{header: "NAMESPACE", value: func(r subjectRow) string { return r.namespace }},
+	{header: "SA-NAMESPACE", value: func(r subjectRow) string { return r.subject.Namespace }},
+}
+
+// columnPresets are the named sets of columns which can be selected with --columns instead of listing the columns.
+var columnPresets = map[string][]string{
+	"audit":   {"BINDING", "ROLE", "SUBJECT", "KIND", "NAMESPACE"},
+	"minimal": {"SUBJECT", "KIND"},
+}
+
+// expandColumns returns the columns of the given preset or of the given comma-separated list of column headers.
+func expandColumns(spec string) ([]column, error) {
+	if spec == "" {
+		return nil, nil
+	}
+	headers, ok := columnPresets[spec]
+	if !ok {
+		headers = strings.Split(spec, ",")
+	}
+
+	var selected []column
+	for _, header := range headers {
+		c, ok := columnByHeader(strings.ToUpper(strings.TrimSpace(header)))
+		if !ok {
+			return nil, fmt.Errorf("unknown column or preset \"%s\", expected one of %v or the presets %v",
+				header, columnHeaders(), columnPresetNames())
+		}
+		selected = append(selected, c)
+	}
+	return selected, nil
+}
+
+func columnByHeader(header string) (column, bool) {
+	for _, c := range columns {
+		if c.header == header {
+			return c, true
+		}
+	}
+	return column{}, false
+}
+
+func columnHeaders() []string {
+	var headers []string
+	for _, c := range columns {
+		headers = append(headers, c.header)
+	}
+	return headers
+}
+
+func columnPresetNames() []string {
+	var names []string
+	for name := range columnPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// printColumnPresets lists the presets of --columns with the columns each of them expands to.
+func (w *whoCan) printColumnPresets(args []string) error {
+	if len(args) > 0 {
+		return errors.New("--list-column-presets cannot be used with VERB and TYPE")
+	}
+	wr := new(tabwriter.Writer)
+	wr.Init(w.Out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(wr, "PRESET\tCOLUMNS")
+	for _, name := range columnPresetNames() {
+		fmt.Fprintf(wr, "%s\t%s\n", name, strings.Join(columnPresets[name], ","))
+	}
+	return wr.Flush()
+}
+
+// outputColumns prints the subjects of all bindings in a single table with the columns selected by --columns.
+func (w *whoCan) outputColumns(roleBindings []rbac.RoleBinding, clusterRoleBindings []rbac.ClusterRoleBinding) {
+	all := w.roleBindingRows(roleBindings)
+	for i := range all {
+		all[i].bindingKind = "RoleBinding"
+	}
+	for _, r := range w.clusterRoleBindingRows(clusterRoleBindings) {
+		r.bindingKind = "ClusterRoleBinding"
+		all = append(all, r)
+	}
+	if len(all) == 0 {
+		fmt.Fprintf(w.Out, "No subjects found with permissions to %s\n", w.prettyPrintAction())
+		return
+	}
+	if w.sortBy != "" {
+		sortRows(all, w.sortBy)
+	}
+
+	wr := new(tabwriter.Writer)
+	wr.Init(w.Out, 0, 8, 2, ' ', 0)
+	limit := &rowLimit{max: w.maxResults}
+
+	var headers []string
+	for _, c := range w.selectedColumns {
+		headers = append(headers, c.header)
+	}
+	w.printHeader(wr, strings.Join(headers, "\t"))
+	for _, r := range limit.take(all) {
+		var values []string
+		for _, c := range w.selectedColumns {
+			values = append(values, c.value(r))
+		}
+		fmt.Fprintln(wr, w.rowColor(r)+strings.Join(values, "\t")+w.optionalColumns(r)+w.endColor())
+	}
+	wr.Flush()
+	limit.printNotice(w.Out)
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"testing"
+)
+
+func TestExpandColumns(t *testing.T) {
+	data := []struct {
+		scenario string
+		spec     string
+
+		headers []string
+		err     error
+	}{
+		{
+			scenario: "Should expand audit preset",
+			spec:     "audit",
+			headers:  []string{"BINDING", "ROLE", "SUBJECT", "KIND", "NAMESPACE"},
+		},
+		{
+			scenario: "Should expand minimal preset",
+			spec:     "minimal",
+			headers:  []string{"SUBJECT", "KIND"},
+		},
+		{
+			scenario: "Should expand list of columns regardless of case",
+			spec:     "subject, SA-Namespace",
+			headers:  []string{"SUBJECT", "SA-NAMESPACE"},
+		},
+		{
+			scenario: "Should return no columns when not set",
+			spec:     "",
+		},
+		{
+			scenario: "Should return error when column is unknown",
+			spec:     "SUBJECT,VERB",
+			err: errors.New("unknown column or preset \"VERB\", expected one of " +
+				"[BINDING ROLE SUBJECT KIND NAMESPACE SA-NAMESPACE] or the presets [audit minimal]"),
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// when
+			selected, err := expandColumns(tt.spec)
+
+			// then
+			assert.Equal(t, tt.err, err)
+			var headers []string
+			for _, c := range selected {
+				headers = append(headers, c.header)
+			}
+			assert.Equal(t, tt.headers, headers)
+		})
+	}
+}
+
+func TestWhoCan_outputColumns(t *testing.T) {
+	// given
+	roleBindings := []rbac.RoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Alice-can-view-pods", Namespace: "default"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "view-pods"},
+			Subjects:   []rbac.Subject{{Name: "Alice", Kind: "User"}},
+		},
+	}
+	clusterRoleBindings := []rbac.ClusterRoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Bob-can-view"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "view"},
+			Subjects:   []rbac.Subject{{Name: "Bob", Kind: "ServiceAccount", Namespace: "foo"}},
+		},
+	}
+	selected, err := expandColumns("audit")
+	require.NoError(t, err)
+
+	streams, _, out, _ := clioptions.NewTestIOStreams()
+	wc := whoCan{
+		verb:            "get",
+		resource:        "pods",
+		selectedColumns: selected,
+		IOStreams:       streams,
+	}
+
+	// when
+	wc.output(roleBindings, clusterRoleBindings)
+
+	// then
+	assert.Equal(t, `BINDING                          ROLE              SUBJECT  KIND            NAMESPACE
+RoleBinding/Alice-can-view-pods  Role/view-pods    Alice    User            default
+ClusterRoleBinding/Bob-can-view  ClusterRole/view  Bob      ServiceAccount  
+`, out.String())
+}
+
+func TestWhoCan_printColumnPresets(t *testing.T) {
+	// given
+	streams, _, out, _ := clioptions.NewTestIOStreams()
+	wc := whoCan{IOStreams: streams}
+
+	// when
+	err := wc.printColumnPresets(nil)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, `PRESET   COLUMNS
+audit    BINDING,ROLE,SUBJECT,KIND,NAMESPACE
+minimal  SUBJECT,KIND
+`, out.String())
+}
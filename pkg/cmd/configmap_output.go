@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	core "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// configMapDataKey is the key of the ConfigMap data under which the JSON result is stored.
+	configMapDataKey = "result.json"
+	// configMapLabelPrefix qualifies the labels which describe the query of the result stored in a ConfigMap.
+	configMapLabelPrefix = "who-can.aquasecurity.github.io/"
+	configMapNamePrefix  = "who-can-"
+	// maxLabelValueLength is the maximum length of a label value.
+	maxLabelValueLength = 63
+	// maxNameLength is the maximum length of a ConfigMap name, which must be a DNS subdomain.
+	maxNameLength = 253
+)
+
+var (
+	invalidNameChars       = regexp.MustCompile(`[^a-z0-9.-]+`)
+	invalidLabelValueChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+)
+
+// printConfigMap writes the Result as a ConfigMap manifest in YAML, so that the result can be stored in the cluster
+// with `kubectl apply -f -`. The result is stored as JSON in the data of the ConfigMap, while the query is described
+// by its name and labels. The namespace is left to be chosen when the manifest is applied.
+func (w *whoCan) printConfigMap(out io.Writer, result Result) error {
+	doc, err := result.convert(w.outputVersion)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling result: %v", err)
+	}
+
+	cm := core.ConfigMap{
+		TypeMeta: meta.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: meta.ObjectMeta{
+			Name:   configMapName(result.Query),
+			Labels: configMapLabels(result.Query),
+		},
+		Data: map[string]string{configMapDataKey: string(data)},
+	}
+	manifest, err := yaml.Marshal(cm)
+	if err != nil {
+		return fmt.Errorf("marshalling ConfigMap: %v", err)
+	}
+	_, err = out.Write(manifest)
+	return err
+}
+
+// configMapName derives the name of the ConfigMap from the verb, the resource or the non-resource URL, and the
+// resource name of the query, such as `who-can-get-pods-log` or `who-can-get-healthz`.
+func configMapName(q Query) string {
+	parts := []string{q.Verb}
+	if q.NonResourceURL != "" {
+		parts = append(parts, q.NonResourceURL)
+	} else {
+		parts = append(parts, q.Resource)
+	}
+	parts = append(parts, q.ResourceName)
+
+	var tokens []string
+	for _, part := range parts {
+		part = strings.Replace(part, rbac.ResourceAll, "all", -1)
+		part = invalidNameChars.ReplaceAllString(strings.ToLower(part), "-")
+		if part = strings.Trim(part, ".-"); part != "" {
+			tokens = append(tokens, part)
+		}
+	}
+	name := configMapNamePrefix + strings.Join(tokens, "-")
+	if len(name) > maxNameLength {
+		name = strings.TrimRight(name[:maxNameLength], ".-")
+	}
+	return name
+}
+
+// configMapLabels returns the labels which describe the given query. Values which are not valid label values
+// are sanitized, and empty values are omitted.
+func configMapLabels(q Query) map[string]string {
+	labels := map[string]string{"app.kubernetes.io/managed-by": "kubectl-who-can"}
+	for key, value := range map[string]string{
+		"verb":           q.Verb,
+		"resource":       q.Resource,
+		"resource-name":  q.ResourceName,
+		"nonresourceurl": q.NonResourceURL,
+		"namespace":      q.Namespace,
+	} {
+		if value = labelValue(value); value != "" {
+			labels[configMapLabelPrefix+key] = value
+		}
+	}
+	return labels
+}
+
+// labelValue sanitizes the given value into a label value, which must consist of at most 63 alphanumeric characters,
+// `-`, `_` or `.`, and begin and end with an alphanumeric character. The `*` wildcard is represented by `all`.
+func labelValue(value string) string {
+	value = strings.Replace(value, rbac.ResourceAll, "all", -1)
+	value = strings.Trim(invalidLabelValueChars.ReplaceAllString(value, "-"), "._-")
+	if len(value) > maxLabelValueLength {
+		value = strings.TrimRight(value[:maxLabelValueLength], "._-")
+	}
+	return value
+}
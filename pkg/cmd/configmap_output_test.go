@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"testing"
+)
+
+func TestWhoCan_printConfigMap(t *testing.T) {
+	// given
+	roleBindings := []rbac.RoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Alice-can-view-pods", Namespace: "default"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "view-pods"},
+			Subjects:   []rbac.Subject{{Name: "Alice", Kind: "User"}},
+		},
+	}
+
+	streams, _, out, _ := clioptions.NewTestIOStreams()
+	wc := whoCan{
+		verb:               "get",
+		resource:           "pods/log",
+		namespacedResource: true,
+		namespace:          "default",
+		outputFormat:       outputConfigMap,
+		outputVersion:      outputVersionV1,
+		IOStreams:          streams,
+	}
+
+	// when
+	err := wc.printResult(wc.newResult(roleBindings, nil, nil))
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, `apiVersion: v1
+data:
+  result.json: |-
+    {
+      "apiVersion": "whocan.dev/v1",
+      "kind": "WhoCanResult",
+      "query": {
+        "verb": "get",
+        "resource": "pods/log",
+        "namespace": "default",
+        "resolved": {
+          "resource": "pods/log",
+          "apiGroup": "",
+          "namespaced": true
+        }
+      },
+      "roleBindings": [
+        {
+          "name": "Alice-can-view-pods",
+          "namespace": "default",
+          "roleRef": {
+            "kind": "Role",
+            "name": "view-pods"
+          },
+          "wildcard": false,
+          "subjects": [
+            {
+              "kind": "User",
+              "name": "Alice"
+            }
+          ]
+        }
+      ],
+      "clusterRoleBindings": []
+    }
+kind: ConfigMap
+metadata:
+  creationTimestamp: null
+  labels:
+    app.kubernetes.io/managed-by: kubectl-who-can
+    who-can.aquasecurity.github.io/namespace: default
+    who-can.aquasecurity.github.io/resource: pods-log
+    who-can.aquasecurity.github.io/verb: get
+  name: who-can-get-pods-log
+`, out.String())
+}
+
+func TestConfigMapName(t *testing.T) {
+	data := []struct {
+		scenario string
+		query    Query
+		name     string
+	}{
+		{
+			scenario: "Should derive name from verb and resource",
+			query:    Query{Verb: "get", Resource: "deployments.apps"},
+			name:     "who-can-get-deployments.apps",
+		},
+		{
+			scenario: "Should include resource name",
+			query:    Query{Verb: "bind", Resource: "clusterroles", ResourceName: "Admin"},
+			name:     "who-can-bind-clusterroles-admin",
+		},
+		{
+			scenario: "Should derive name from non-resource URL",
+			query:    Query{Verb: "get", NonResourceURL: "/logs/*"},
+			name:     "who-can-get-logs-all",
+		},
+		{
+			scenario: "Should name wildcards",
+			query:    Query{Verb: "*", Resource: "*"},
+			name:     "who-can-all-all",
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			assert.Equal(t, tt.name, configMapName(tt.query))
+		})
+	}
+}
+
+func TestConfigMapLabels(t *testing.T) {
+	labels := configMapLabels(Query{Verb: "get", NonResourceURL: "/healthz/*"})
+
+	assert.Equal(t, map[string]string{
+		"app.kubernetes.io/managed-by":                  "kubectl-who-can",
+		"who-can.aquasecurity.github.io/verb":           "get",
+		"who-can.aquasecurity.github.io/nonresourceurl": "healthz-all",
+	}, labels)
+}
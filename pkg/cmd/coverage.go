@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	rbac "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	coverageUsage = `coverage --subject KIND/NAME --resource TYPE`
+	coverageLong  = `Shows which of the common verbs a subject is granted on a resource type, and which it lacks.
+
+The verbs get, list, watch, create, update, patch, delete and deletecollection are checked against the rules granted
+to the subject by RoleBindings and ClusterRoleBindings, either directly or through a group which the subject implicitly
+belongs to. A verb granted on named objects of the resource only is listed as partially granted.
+
+Like for checking an action, the RoleBindings of the current namespace are considered unless --namespace or
+--all-namespaces is given. KIND is one of 'User', 'Group' or 'ServiceAccount'. The NAME of a ServiceAccount is given
+as NAMESPACE:NAME.`
+	coverageExample = `  # List which verbs the user "jane" is granted on secrets in the current namespace
+  kubectl who-can coverage --subject User/jane --resource secrets
+
+  # List which verbs the service account "deployer" of the namespace "ci" is granted on deployments in all namespaces
+  kubectl who-can coverage --subject ServiceAccount/ci:deployer --resource deployments.apps --all-namespaces`
+)
+
+// coverageVerbs are the verbs checked by the coverage report.
+var coverageVerbs = []string{"get", "list", "watch", "create", "update", "patch", "delete", "deletecollection"}
+
+type coverageWhoCan struct {
+	subject       string
+	resource      string
+	namespace     string
+	allNamespaces bool
+
+	configFlags      *clioptions.ConfigFlags
+	clientConfig     clientcmd.ClientConfig
+	client           kubernetes.Interface
+	resourceResolver ResourceResolver
+
+	clioptions.IOStreams
+}
+
+// verbCoverage tells through which bindings a subject is granted a verb.
+type verbCoverage struct {
+	verb string
+	// bindings grant the verb on all objects of the resource.
+	bindings []string
+	// partialBindings grant the verb on named objects of the resource only.
+	partialBindings []string
+}
+
+func NewCmdCoverage(client kubernetes.Interface, configFlags *clioptions.ConfigFlags, resourceResolver ResourceResolver, streams clioptions.IOStreams) *cobra.Command {
+	o := &coverageWhoCan{
+		configFlags:      configFlags,
+		clientConfig:     configFlags.ToRawKubeConfigLoader(),
+		client:           client,
+		resourceResolver: resourceResolver,
+		IOStreams:        streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:          coverageUsage,
+		Short:        "Show which verbs a subject is granted on a resource type",
+		Long:         coverageLong,
+		Example:      coverageExample,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if o.subject == "" || o.resource == "" {
+				return errors.New("--subject and --resource are required")
+			}
+			var err error
+			o.namespace, err = resolveNamespace(o.configFlags, o.clientConfig, o.allNamespaces)
+			if err != nil {
+				return err
+			}
+			return o.run()
+		},
+	}
+
+	cmd.Flags().StringVar(&o.subject, "subject", "", "Subject given as KIND/NAME, such as User/jane or ServiceAccount/ci:deployer")
+	cmd.Flags().StringVar(&o.resource, "resource", "", "Resource type, optionally qualified with its API group, such as secrets or deployments.apps")
+	cmd.Flags().BoolVarP(&o.allNamespaces, "all-namespaces", "A", false,
+		"If true, consider the RoleBindings in all namespaces.")
+
+	return cmd
+}
+
+func (o *coverageWhoCan) run() error {
+	subject, err := parseSubject(o.subject)
+	if err != nil {
+		return err
+	}
+	groupResource, _, err := o.resourceResolver.SupportedVerbs(o.resource)
+	if err != nil {
+		return fmt.Errorf("resolving resource: %v", err)
+	}
+
+	snapshot, err := loadRBACSnapshot(o.client.RbacV1(), o.namespace)
+	if err != nil {
+		return err
+	}
+	grants, err := snapshot.grantsFor(subject)
+	if err != nil {
+		return err
+	}
+
+	printCoverage(o.Out, o.subject, groupResource, coverageOf(grants, groupResource))
+	return nil
+}
+
+// coverageOf matches the grants of a subject against each of the coverageVerbs on the given resource.
+// The rules are matched the same way as when checking who can perform an action.
+func coverageOf(grants []grant, groupResource schema.GroupResource) []verbCoverage {
+	return coverageOfVerbs(grants, groupResource, coverageVerbs)
+}
+
+// coverageOfVerbs matches the grants of a subject against each of the given verbs on the given resource.
+func coverageOfVerbs(grants []grant, groupResource schema.GroupResource, verbs []string) []verbCoverage {
+	coverage := make([]verbCoverage, len(verbs))
+	for i, verb := range verbs {
+		w := whoCan{verb: verb, resource: groupResource.Resource, apiGroup: groupResource.Group}
+		full := make(map[string]bool)
+		var refs []string
+		for _, g := range grants {
+			ref := g.bindingKind + "/" + g.binding
+			if g.namespace != "" {
+				ref += " (" + g.namespace + ")"
+			}
+			matches := w.policyRuleMatches(g.rule)
+			if !matches && (len(g.rule.ResourceNames) == 0 || !w.policyRuleMatches(withoutResourceNames(g.rule))) {
+				continue
+			}
+			if _, ok := full[ref]; !ok {
+				refs = append(refs, ref)
+			}
+			// A binding with any rule for all objects grants the verb fully.
+			full[ref] = full[ref] || matches
+		}
+
+		coverage[i].verb = verb
+		for _, ref := range refs {
+			if full[ref] {
+				coverage[i].bindings = append(coverage[i].bindings, ref)
+			} else {
+				coverage[i].partialBindings = append(coverage[i].partialBindings, ref)
+			}
+		}
+	}
+	return coverage
+}
+
+// withoutResourceNames returns a copy of the rule which applies to all objects rather than to the named ones.
+func withoutResourceNames(rule rbac.PolicyRule) rbac.PolicyRule {
+	rule.ResourceNames = nil
+	return rule
+}
+
+// printCoverage prints a checklist of the verbs, where a granted verb is checked and a verb granted on named objects
+// only is marked with a tilde, followed by the bindings which grant it.
+func printCoverage(out io.Writer, subject string, groupResource schema.GroupResource, coverage []verbCoverage) {
+	var granted int
+	for _, c := range coverage {
+		if len(c.bindings) > 0 {
+			granted++
+		}
+	}
+	_, _ = fmt.Fprintf(out, "%s is granted %d of %d verbs on %s:\n\n", subject, granted, len(coverage), groupResource)
+
+	wr := new(tabwriter.Writer)
+	wr.Init(out, 0, 8, 2, ' ', 0)
+	for _, c := range coverage {
+		switch {
+		case len(c.bindings) > 0:
+			_, _ = fmt.Fprintf(wr, "[x] %s\t%s\n", c.verb, strings.Join(c.bindings, ", "))
+		case len(c.partialBindings) > 0:
+			_, _ = fmt.Fprintf(wr, "[~] %s\tnamed objects only: %s\n", c.verb, strings.Join(c.partialBindings, ", "))
+		default:
+			_, _ = fmt.Fprintf(wr, "[ ] %s\t\n", c.verb)
+		}
+	}
+	_ = wr.Flush()
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+)
+
+func TestCoverageOf(t *testing.T) {
+	// given
+	secrets := schema.GroupResource{Resource: "secrets"}
+	grants := []grant{
+		{bindingKind: "RoleBinding", binding: "read-secrets", namespace: "foo",
+			rule: rbac.PolicyRule{APIGroups: []string{""}, Verbs: []string{"get", "list"}, Resources: []string{"secrets"}}},
+		{bindingKind: "RoleBinding", binding: "read-secrets", namespace: "foo",
+			rule: rbac.PolicyRule{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"secrets"}, ResourceNames: []string{"tls"}}},
+		{bindingKind: "RoleBinding", binding: "update-tls", namespace: "foo",
+			rule: rbac.PolicyRule{APIGroups: []string{""}, Verbs: []string{"update", "patch"}, Resources: []string{"secrets"}, ResourceNames: []string{"tls"}}},
+		{bindingKind: "ClusterRoleBinding", binding: "watch-all",
+			rule: rbac.PolicyRule{APIGroups: []string{"*"}, Verbs: []string{"watch"}, Resources: []string{"*"}}},
+		{bindingKind: "ClusterRoleBinding", binding: "delete-deployments",
+			rule: rbac.PolicyRule{APIGroups: []string{"apps"}, Verbs: []string{"delete"}, Resources: []string{"deployments"}}},
+	}
+
+	// when
+	coverage := coverageOf(grants, secrets)
+
+	// then
+	assert.Equal(t, []verbCoverage{
+		{verb: "get", bindings: []string{"RoleBinding/read-secrets (foo)"}},
+		{verb: "list", bindings: []string{"RoleBinding/read-secrets (foo)"}},
+		{verb: "watch", bindings: []string{"ClusterRoleBinding/watch-all"}},
+		{verb: "create"},
+		{verb: "update", partialBindings: []string{"RoleBinding/update-tls (foo)"}},
+		{verb: "patch", partialBindings: []string{"RoleBinding/update-tls (foo)"}},
+		{verb: "delete"},
+		{verb: "deletecollection"},
+	}, coverage)
+}
+
+func TestCoverageWhoCan_run(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&rbac.Role{ObjectMeta: meta.ObjectMeta{Name: "secret-reader", Namespace: "foo"},
+			Rules: []rbac.PolicyRule{{APIGroups: []string{""}, Verbs: []string{"get", "list", "watch"}, Resources: []string{"secrets"}}}},
+		&rbac.ClusterRole{ObjectMeta: meta.ObjectMeta{Name: "tls-rotator"},
+			Rules: []rbac.PolicyRule{{APIGroups: []string{""}, Verbs: []string{"update"}, Resources: []string{"secrets"}, ResourceNames: []string{"tls"}}}},
+		&rbac.RoleBinding{ObjectMeta: meta.ObjectMeta{Name: "jane-reads-secrets", Namespace: "foo"},
+			RoleRef: rbac.RoleRef{Kind: "Role", Name: "secret-reader"}, Subjects: []rbac.Subject{{Kind: rbac.UserKind, Name: "jane"}}},
+		&rbac.ClusterRoleBinding{ObjectMeta: meta.ObjectMeta{Name: "jane-rotates-tls"},
+			RoleRef: rbac.RoleRef{Kind: "ClusterRole", Name: "tls-rotator"}, Subjects: []rbac.Subject{{Kind: rbac.UserKind, Name: "jane"}}},
+	)
+
+	data := []struct {
+		scenario string
+		subject  string
+		output   string
+		err      string
+	}{
+		{
+			scenario: "Should print partial coverage of subject",
+			subject:  "User/jane",
+			output: `User/jane is granted 3 of 8 verbs on secrets:
+
+[x] get               RoleBinding/jane-reads-secrets (foo)
+[x] list              RoleBinding/jane-reads-secrets (foo)
+[x] watch             RoleBinding/jane-reads-secrets (foo)
+[ ] create            
+[~] update            named objects only: ClusterRoleBinding/jane-rotates-tls
+[ ] patch             
+[ ] delete            
+[ ] deletecollection  
+`,
+		},
+		{
+			scenario: "Should print no coverage of subject which is not bound",
+			subject:  "User/bob",
+			output: `User/bob is granted 0 of 8 verbs on secrets:
+
+[ ] get               
+[ ] list              
+[ ] watch             
+[ ] create            
+[ ] update            
+[ ] patch             
+[ ] delete            
+[ ] deletecollection  
+`,
+		},
+		{
+			scenario: "Should return error when subject is invalid",
+			subject:  "jane",
+			err:      "invalid subject \"jane\", expected KIND/NAME",
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			resourceResolver := new(resourceResolverMock)
+			resourceResolver.On("SupportedVerbs", "secrets").Return(schema.GroupResource{Resource: "secrets"}, []string{"get"}, nil)
+			streams, _, out, _ := clioptions.NewTestIOStreams()
+			o := &coverageWhoCan{subject: tt.subject, resource: "secrets", namespace: "foo",
+				client: client, resourceResolver: resourceResolver, IOStreams: streams}
+
+			// when
+			err := o.run()
+
+			// then
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.output, out.String())
+		})
+	}
+}
+
+func TestCoverageWhoCan_run_UnknownResource(t *testing.T) {
+	// given
+	resourceResolver := new(resourceResolverMock)
+	resourceResolver.On("SupportedVerbs", "unicorns").Return(schema.GroupResource{}, []string(nil), errors.New("the server doesn't have a resource type \"unicorns\""))
+	o := &coverageWhoCan{subject: "User/jane", resource: "unicorns", client: fake.NewSimpleClientset(), resourceResolver: resourceResolver}
+
+	// when
+	err := o.run()
+
+	// then
+	assert.EqualError(t, err, "resolving resource: the server doesn't have a resource type \"unicorns\"")
+}
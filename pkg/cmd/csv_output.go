@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// csvHeader is the header of the CSV output, which has the columns of the table and the kind of each binding.
+var csvHeader = []string{"Binding", "Binding Kind", "Namespace", "Subject", "Kind", "SA Namespace"}
+
+// printCSV writes one RFC 4180 CSV row per subject of the Result, first for the RoleBindings and then for the
+// ClusterRoleBindings, which have an empty namespace.
+func (w *whoCan) printCSV(out io.Writer, result Result) error {
+	var rows [][]string
+	result.forEachBinding(func(bindingKind string, b Binding) {
+		for _, s := range b.Subjects {
+			rows = append(rows, []string{b.Name, bindingKind, b.Namespace, s.Name, s.Kind, s.Namespace})
+		}
+	})
+	return writeCSV(out, csvHeader, rows)
+}
+
+// writeCSV writes the header followed by the rows as RFC 4180 CSV.
+func writeCSV(out io.Writer, header []string, rows [][]string) error {
+	if err := csv.NewWriter(out).WriteAll(append([][]string{header}, rows...)); err != nil {
+		return fmt.Errorf("writing CSV: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"testing"
+)
+
+func TestWhoCan_printCSV(t *testing.T) {
+	// given
+	roleBindings := []rbac.RoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Alice-can-view-pods", Namespace: "default"},
+			Subjects: []rbac.Subject{
+				{Name: "Alice Smith", Kind: "User"},
+				{Name: "team, \"ops\"", Kind: "Group"},
+			},
+		},
+	}
+	clusterRoleBindings := []rbac.ClusterRoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "view-pods"},
+			Subjects: []rbac.Subject{
+				{Name: "bar", Kind: "ServiceAccount", Namespace: "foo"},
+			},
+		},
+	}
+
+	streams, _, out, _ := clioptions.NewTestIOStreams()
+	wc := whoCan{
+		verb:         "get",
+		resource:     "pods",
+		outputFormat: outputCSV,
+		IOStreams:    streams,
+	}
+
+	// when
+	err := wc.printResult(wc.newResult(roleBindings, clusterRoleBindings, []string{"list roles"}))
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, `Binding,Binding Kind,Namespace,Subject,Kind,SA Namespace
+Alice-can-view-pods,RoleBinding,default,Alice Smith,User,
+Alice-can-view-pods,RoleBinding,default,"team, ""ops""",Group,
+view-pods,ClusterRoleBinding,,bar,ServiceAccount,foo
+`, out.String())
+}
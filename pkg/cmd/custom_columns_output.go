@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// customColumnsPrefix is the prefix of the custom-columns output format, which is followed by the columns given
+// as HEADER:JSONPATH pairs separated by commas, like for kubectl.
+const customColumnsPrefix = "custom-columns="
+
+// customColumn is a column of the custom-columns output, whose value is selected from a customColumnsRow.
+type customColumn struct {
+	header string
+	path   *jsonpath.JSONPath
+}
+
+// customColumnsRow is the document of a subject of a binding which the JSONPath of a custom column refers to,
+// such as .subject.name, .binding.roleRef.name or .query.verb.
+type customColumnsRow struct {
+	Subject     Subject `json:"subject"`
+	BindingKind string  `json:"bindingKind"`
+	Binding     Binding `json:"binding"`
+	Query       Query   `json:"query"`
+}
+
+// customColumnsSpec returns the columns given with the custom-columns output format, or false for other formats.
+func customColumnsSpec(outputFormat string) (string, bool) {
+	if !strings.HasPrefix(outputFormat, customColumnsPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(outputFormat, customColumnsPrefix), true
+}
+
+// parseCustomColumns parses the columns given as HEADER:JSONPATH pairs separated by commas. Like for kubectl, the
+// JSONPath may be given without braces, such as SUBJECT:.subject.name.
+func parseCustomColumns(spec string) ([]customColumn, error) {
+	var columns []customColumn
+	for _, pair := range strings.Split(spec, ",") {
+		tokens := strings.SplitN(pair, ":", 2)
+		if len(tokens) != 2 || tokens[0] == "" || tokens[1] == "" {
+			return nil, fmt.Errorf("invalid custom-columns \"%s\", expected HEADER:JSONPATH pairs separated by commas", spec)
+		}
+		expression := tokens[1]
+		if !strings.HasPrefix(expression, "{") {
+			expression = "{" + expression + "}"
+		}
+		path := jsonpath.New(tokens[0]).AllowMissingKeys(true)
+		if err := path.Parse(expression); err != nil {
+			return nil, fmt.Errorf("invalid custom column \"%s\": %v", pair, err)
+		}
+		columns = append(columns, customColumn{header: tokens[0], path: path})
+	}
+	return columns, nil
+}
+
+// printCustomColumns prints a table with a row per subject of the Result and the given columns.
+// A column without a value shows <none>, and several values are separated by commas.
+func (w *whoCan) printCustomColumns(out io.Writer, result Result, columns []customColumn) error {
+	var rows []customColumnsRow
+	result.forEachBinding(func(bindingKind string, b Binding) {
+		for _, s := range b.Subjects {
+			rows = append(rows, customColumnsRow{Subject: s, BindingKind: bindingKind, Binding: b, Query: result.Query})
+		}
+	})
+
+	wr := new(tabwriter.Writer)
+	wr.Init(out, 0, 8, 2, ' ', 0)
+	var headers []string
+	for _, c := range columns {
+		headers = append(headers, c.header)
+	}
+	_, _ = fmt.Fprintln(wr, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		data, err := json.Marshal(row)
+		if err != nil {
+			return fmt.Errorf("marshalling row: %v", err)
+		}
+		var doc interface{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("unmarshalling row: %v", err)
+		}
+
+		var values []string
+		for _, c := range columns {
+			value, err := customColumnValue(c.path, doc)
+			if err != nil {
+				return fmt.Errorf("evaluating custom column %s: %v", c.header, err)
+			}
+			values = append(values, value)
+		}
+		_, _ = fmt.Fprintln(wr, strings.Join(values, "\t"))
+	}
+	return wr.Flush()
+}
+
+// customColumnValue returns the values selected by the given JSONPath separated by commas, or <none> if there are none.
+func customColumnValue(path *jsonpath.JSONPath, doc interface{}) (string, error) {
+	results, err := path.FindResults(doc)
+	if err != nil {
+		return "", err
+	}
+	var values []string
+	for _, result := range results {
+		for _, v := range result {
+			values = append(values, fmt.Sprintf("%v", v.Interface()))
+		}
+	}
+	if len(values) == 0 {
+		return "<none>", nil
+	}
+	return strings.Join(values, ","), nil
+}
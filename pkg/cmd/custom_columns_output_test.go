@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"testing"
+)
+
+func TestWhoCan_printCustomColumns(t *testing.T) {
+	// given
+	roleBindings := []rbac.RoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Alice-can-read-secrets", Namespace: "default"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "secret-reader"},
+			Subjects:   []rbac.Subject{{Name: "Alice", Kind: "User"}, {Name: "ci", Kind: "ServiceAccount", Namespace: "default"}},
+		},
+	}
+	clusterRoleBindings := []rbac.ClusterRoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Bob-can-read-all"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "view"},
+			Subjects:   []rbac.Subject{{Name: "Bob", Kind: "User"}},
+		},
+	}
+
+	streams, _, out, _ := clioptions.NewTestIOStreams()
+	wc := whoCan{
+		verb:         "get",
+		resource:     "secrets",
+		namespace:    "default",
+		outputFormat: "custom-columns=SUBJECT:.subject.name,KIND:.subject.kind,SA-NAMESPACE:.subject.namespace,BINDING-KIND:.bindingKind,BINDING:.binding.name,VERB:.query.verb",
+		IOStreams:    streams,
+	}
+	result := wc.newResult(roleBindings, clusterRoleBindings, nil)
+	result.Warnings = []string{"The user is not allowed to list roles in the default namespace"}
+
+	// when
+	err := wc.printResult(result)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, `SUBJECT  KIND            SA-NAMESPACE  BINDING-KIND        BINDING                 VERB
+Alice    User            <none>        RoleBinding         Alice-can-read-secrets  get
+ci       ServiceAccount  default       RoleBinding         Alice-can-read-secrets  get
+Bob      User            <none>        ClusterRoleBinding  Bob-can-read-all        get
+`, out.String())
+}
+
+func TestParseCustomColumns(t *testing.T) {
+	data := []struct {
+		scenario string
+		spec     string
+
+		headers []string
+		err     error
+	}{
+		{
+			scenario: "Should parse columns with relaxed JSONPath",
+			spec:     "SUBJECT:.subject.name,ROLE:{.binding.roleRef.name}",
+			headers:  []string{"SUBJECT", "ROLE"},
+		},
+		{
+			scenario: "Should return error when JSONPath is missing",
+			spec:     "SUBJECT:.subject.name,KIND",
+			err:      errors.New("invalid custom-columns \"SUBJECT:.subject.name,KIND\", expected HEADER:JSONPATH pairs separated by commas"),
+		},
+		{
+			scenario: "Should return error when JSONPath is invalid",
+			spec:     "SUBJECT:.subject[name",
+			err:      errors.New("invalid custom column \"SUBJECT:.subject[name\": unterminated array"),
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// when
+			columns, err := parseCustomColumns(tt.spec)
+
+			// then
+			assert.Equal(t, tt.err, err)
+			var headers []string
+			for _, c := range columns {
+				headers = append(headers, c.header)
+			}
+			assert.Equal(t, tt.headers, headers)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	rbac "k8s.io/api/rbac/v1"
+)
+
+// dotEscaper escapes the characters which would otherwise end a quoted DOT string.
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ")
+
+// dotGraph collects the nodes and the edges of a DOT graph, each written once in the order they are added.
+type dotGraph struct {
+	buf   bytes.Buffer
+	nodes map[string]bool
+	edges map[string]bool
+}
+
+// printDOT writes the Result as a directed graph in the DOT language of Graphviz, with the edges
+// subject -> binding -> role -> rule for each grant, so that it can be rendered with `dot -Tsvg`.
+// Rules are the ones of the role which match the queried action.
+func (w *whoCan) printDOT(out io.Writer, result Result) error {
+	g := &dotGraph{nodes: make(map[string]bool), edges: make(map[string]bool)}
+	_, _ = fmt.Fprintf(&g.buf, "digraph \"who-can\" {\n  rankdir=LR;\n  label=\"who can %s\";\n",
+		dotEscaper.Replace(w.prettyPrintAction()))
+	result.forEachBinding(func(bindingKind string, b Binding) {
+		binding := g.node(bindingKind, b.Namespace, b.Name, "box")
+		roleRef := rbac.RoleRef{Kind: b.RoleRef.Kind, Name: b.RoleRef.Name}
+		roleNamespace := b.Namespace
+		if roleRef.Kind == "ClusterRole" {
+			roleNamespace = ""
+		}
+		role := g.node(roleRef.Kind, roleNamespace, roleRef.Name, "box3d")
+		for _, s := range b.Subjects {
+			g.edge(g.node(s.Kind, s.Namespace, s.Name, "ellipse"), binding)
+		}
+		g.edge(binding, role)
+		for i, rule := range w.matchingRules(newRoleFromRef(&roleRef, b.Namespace)) {
+			g.edge(role, g.ruleNode(fmt.Sprintf("%s#%d", role, i), rule))
+		}
+	})
+	g.buf.WriteString("}\n")
+
+	_, err := out.Write(g.buf.Bytes())
+	return err
+}
+
+// matchingRules returns the rules of the given matched role which grant the queried action, or any of the queried
+// verbs if the verb is given as a comma-separated list of verbs.
+func (w *whoCan) matchingRules(r role) []rbac.PolicyRule {
+	verbs := w.verbs
+	if len(verbs) == 0 {
+		verbs = []string{w.verb}
+	}
+	defer func(verb string) { w.verb = verb }(w.verb)
+
+	var rules []rbac.PolicyRule
+	for _, rule := range w.rules[r] {
+		for _, verb := range verbs {
+			w.verb = verb
+			if w.policyRuleMatches(rule) {
+				rules = append(rules, rule)
+				break
+			}
+		}
+	}
+	return rules
+}
+
+// node adds the node of the given kind, namespace and name unless it was already added, and returns its ID.
+func (g *dotGraph) node(kind, namespace, name, shape string) string {
+	id := kind + "/" + name
+	label := dotEscaper.Replace(kind) + `\n` + dotEscaper.Replace(name)
+	if namespace != "" {
+		id = kind + "/" + namespace + "/" + name
+		label = dotEscaper.Replace(kind) + `\n` + dotEscaper.Replace(namespace+"/"+name)
+	}
+	if !g.nodes[id] {
+		g.nodes[id] = true
+		_, _ = fmt.Fprintf(&g.buf, "  \"%s\" [label=\"%s\", shape=%s];\n", dotEscaper.Replace(id), label, shape)
+	}
+	return id
+}
+
+// ruleNode adds the node of the given rule with the given ID unless it was already added, and returns its ID.
+func (g *dotGraph) ruleNode(id string, rule rbac.PolicyRule) string {
+	label := strings.Join(rule.Verbs, ",") + " " + ruleResources(rule)
+	if len(rule.ResourceNames) > 0 {
+		label += " [" + strings.Join(rule.ResourceNames, ",") + "]"
+	}
+	if !g.nodes[id] {
+		g.nodes[id] = true
+		_, _ = fmt.Fprintf(&g.buf, "  \"%s\" [label=\"%s\", shape=note];\n", dotEscaper.Replace(id), dotEscaper.Replace(label))
+	}
+	return id
+}
+
+// edge adds the edge between the nodes of the given IDs unless it was already added.
+func (g *dotGraph) edge(from, to string) {
+	key := from + "\x00" + to
+	if g.edges[key] {
+		return
+	}
+	g.edges[key] = true
+	_, _ = fmt.Fprintf(&g.buf, "  \"%s\" -> \"%s\";\n", dotEscaper.Replace(from), dotEscaper.Replace(to))
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"testing"
+)
+
+func TestWhoCan_printDOT(t *testing.T) {
+	// given
+	roleBindings := []rbac.RoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "view-pods", Namespace: "default"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "view-pods"},
+			Subjects: []rbac.Subject{
+				{Name: "alice", Kind: "User"},
+				{Name: "bar", Kind: "ServiceAccount", Namespace: "foo"},
+			},
+		},
+	}
+	clusterRoleBindings := []rbac.ClusterRoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "admins"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+			Subjects: []rbac.Subject{
+				{Name: "alice", Kind: "User"},
+			},
+		},
+	}
+
+	streams, _, out, _ := clioptions.NewTestIOStreams()
+	wc := whoCan{
+		verb:         "get",
+		resource:     "pods",
+		outputFormat: outputDOT,
+		rules: map[role][]rbac.PolicyRule{
+			{name: "view-pods", namespace: "default"}: {
+				{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"pods"}},
+				{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"services"}},
+			},
+			{name: "cluster-admin", isClusterRole: true}: {
+				{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}},
+			},
+		},
+		IOStreams: streams,
+	}
+
+	// when
+	err := wc.printResult(wc.newResult(roleBindings, clusterRoleBindings, []string{"list roles"}))
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, `digraph "who-can" {
+  rankdir=LR;
+  label="who can get pods";
+  "RoleBinding/default/view-pods" [label="RoleBinding\ndefault/view-pods", shape=box];
+  "Role/default/view-pods" [label="Role\ndefault/view-pods", shape=box3d];
+  "User/alice" [label="User\nalice", shape=ellipse];
+  "User/alice" -> "RoleBinding/default/view-pods";
+  "ServiceAccount/foo/bar" [label="ServiceAccount\nfoo/bar", shape=ellipse];
+  "ServiceAccount/foo/bar" -> "RoleBinding/default/view-pods";
+  "RoleBinding/default/view-pods" -> "Role/default/view-pods";
+  "Role/default/view-pods#0" [label="get,list pods", shape=note];
+  "Role/default/view-pods" -> "Role/default/view-pods#0";
+  "ClusterRoleBinding/admins" [label="ClusterRoleBinding\nadmins", shape=box];
+  "ClusterRole/cluster-admin" [label="ClusterRole\ncluster-admin", shape=box3d];
+  "User/alice" -> "ClusterRoleBinding/admins";
+  "ClusterRoleBinding/admins" -> "ClusterRole/cluster-admin";
+  "ClusterRole/cluster-admin#0" [label="* *.*", shape=note];
+  "ClusterRole/cluster-admin" -> "ClusterRole/cluster-admin#0";
+}
+`, out.String())
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	rbac "k8s.io/api/rbac/v1"
+)
+
+// escalationCheck is an action that allows the subjects granted it to escalate their privileges.
+type escalationCheck struct {
+	verb     string
+	resource string
+	apiGroup string
+	// clusterScoped is set for cluster-scoped resources, which RoleBindings cannot grant access to.
+	clusterScoped bool
+}
+
+func (c escalationCheck) String() string {
+	return fmt.Sprintf("%s %s", c.verb, qualifiedName(c.resource, c.apiGroup))
+}
+
+// podCreationChecks are the actions which create pods, either directly or through a workload controller.
+// A pod may run as any service account of its namespace, therefore whoever can create pods can assume
+// the identity of those service accounts.
+var podCreationChecks = []escalationCheck{
+	{verb: "create", resource: "pods"},
+	{verb: "create", resource: "deployments", apiGroup: "apps"},
+	{verb: "create", resource: "replicasets", apiGroup: "apps"},
+	{verb: "create", resource: "statefulsets", apiGroup: "apps"},
+	{verb: "create", resource: "daemonsets", apiGroup: "apps"},
+	{verb: "create", resource: "jobs", apiGroup: "batch"},
+	{verb: "create", resource: "cronjobs", apiGroup: "batch"},
+}
+
+// bindingManagementChecks are the actions which create or modify RoleBindings and ClusterRoleBindings.
+// Whoever is granted them can bind themselves, or anyone else, to the roles which they are allowed to bind.
+var bindingManagementChecks = []escalationCheck{
+	{verb: "create", resource: "rolebindings", apiGroup: rbac.GroupName},
+	{verb: "patch", resource: "rolebindings", apiGroup: rbac.GroupName},
+	{verb: "create", resource: "clusterrolebindings", apiGroup: rbac.GroupName, clusterScoped: true},
+	{verb: "patch", resource: "clusterrolebindings", apiGroup: rbac.GroupName, clusterScoped: true},
+}
+
+// escalationFinding is a subject granted some of the checked actions and the namespaces in which it is granted them.
+type escalationFinding struct {
+	subject rbac.Subject
+	actions []string
+	// namespaces is [cluster-wide] if any of the actions is granted by a ClusterRoleBinding.
+	namespaces []string
+}
+
+// escalationSection is a part of the escalation report with the checks it consists of.
+type escalationSection struct {
+	title       string
+	explanation string
+	checks      []escalationCheck
+}
+
+var escalationSections = []escalationSection{
+	{
+		title: "Subjects who can escalate privileges by running pods as any service account",
+		explanation: `A subject who can create pods, or workload controllers which create pods, can set the serviceAccountName of a
+pod to any service account of the namespace and thereby act with the permissions of that service account,
+unless an admission controller restricts the service accounts which pods may run as.`,
+		checks: podCreationChecks,
+	},
+	{
+		title: "Subjects who can escalate privileges by creating or patching role bindings",
+		explanation: `A subject who can create or patch RoleBindings or ClusterRoleBindings can bind itself to any role whose permissions
+it already holds or which it is allowed to bind with the 'bind' verb, and can grant those roles to other subjects.
+Patching an existing binding adds subjects to the role which the binding references.`,
+		checks: bindingManagementChecks,
+	},
+}
+
+// runEscalationReport prints the subjects who can escalate their privileges in the namespace of the query.
+func (w *whoCan) runEscalationReport(args []string) error {
+	if len(args) > 0 {
+		return errors.New("--escalation cannot be used with VERB and TYPE")
+	}
+	if err := w.resolveNamespace(); err != nil {
+		return err
+	}
+	if err := w.namespaceValidator.Validate(w.namespace); err != nil {
+		return fmt.Errorf("validating namespace: %v", err)
+	}
+
+	warnings, err := w.checkAPIAccess()
+	if err != nil {
+		return fmt.Errorf("checking API access: %v", err)
+	}
+	w.printAPIAccessWarnings(warnings)
+
+	for i, section := range escalationSections {
+		findings, err := w.findEscalations(section.checks)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			_, _ = fmt.Fprintln(w.Out)
+		}
+		w.printEscalations(section, findings)
+	}
+	return nil
+}
+
+// findEscalations matches the bindings for each of the checks and aggregates the subjects granted any of them.
+func (w *whoCan) findEscalations(checks []escalationCheck) ([]escalationFinding, error) {
+	type state struct {
+		actions    map[string]struct{}
+		namespaces map[string]struct{}
+	}
+	subjects := make(map[rbac.Subject]*state)
+	grant := func(s rbac.Subject, action, namespace string) {
+		s = rbac.Subject{Kind: s.Kind, Name: s.Name, Namespace: s.Namespace}
+		st, ok := subjects[s]
+		if !ok {
+			st = &state{actions: make(map[string]struct{}), namespaces: make(map[string]struct{})}
+			subjects[s] = st
+		}
+		st.actions[action] = struct{}{}
+		st.namespaces[namespace] = struct{}{}
+	}
+
+	for _, check := range checks {
+		w.verb, w.resource, w.apiGroup = check.verb, check.resource, check.apiGroup
+		w.rules = make(map[role][]rbac.PolicyRule)
+		w.wildcards = make(map[role]bool)
+		w.apiGroups = make(map[role]map[string]struct{})
+		w.nearMisses = make(map[role][]string)
+
+		roleBindings, clusterRoleBindings, err := w.getBindings()
+		if err != nil {
+			return nil, fmt.Errorf("checking %s: %v", check, err)
+		}
+		if check.clusterScoped {
+			// A RoleBinding which references a ClusterRole with rules for a cluster-scoped resource grants nothing.
+			roleBindings = nil
+		}
+		for _, rb := range roleBindings {
+			for _, s := range rb.Subjects {
+				grant(s, check.String(), rb.Namespace)
+			}
+		}
+		for _, crb := range clusterRoleBindings {
+			for _, s := range crb.Subjects {
+				grant(s, check.String(), clusterWide)
+			}
+		}
+	}
+
+	var findings []escalationFinding
+	for s, st := range subjects {
+		finding := escalationFinding{subject: s}
+		for _, check := range checks {
+			if _, ok := st.actions[check.String()]; ok {
+				finding.actions = append(finding.actions, check.String())
+			}
+		}
+		if _, ok := st.namespaces[clusterWide]; ok {
+			finding.namespaces = []string{clusterWide}
+		} else {
+			for ns := range st.namespaces {
+				finding.namespaces = append(finding.namespaces, ns)
+			}
+			sort.Strings(finding.namespaces)
+		}
+		findings = append(findings, finding)
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		a, b := findings[i].subject, findings[j].subject
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return findings, nil
+}
+
+func (w *whoCan) printEscalations(section escalationSection, findings []escalationFinding) {
+	_, _ = fmt.Fprintf(w.Out, "%s:\n\n%s\n\n", section.title, section.explanation)
+
+	if len(findings) == 0 {
+		var actions []string
+		for _, check := range section.checks {
+			actions = append(actions, check.String())
+		}
+		_, _ = fmt.Fprintf(w.Out, "No subjects found who can %s\n", strings.Join(actions, ", "))
+		return
+	}
+
+	wr := new(tabwriter.Writer)
+	wr.Init(w.Out, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintln(wr, "SUBJECT\tTYPE\tSA-NAMESPACE\tACTIONS\tNAMESPACES")
+	for _, f := range findings {
+		_, _ = fmt.Fprintf(wr, "%s\t%s\t%s\t%s\t%s\n", f.subject.Name, f.subject.Kind, f.subject.Namespace,
+			strings.Join(f.actions, ", "), strings.Join(f.namespaces, ", "))
+	}
+	_ = wr.Flush()
+}
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+)
+
+func TestWhoCan_findEscalations(t *testing.T) {
+	// given
+	client := fake.NewSimpleClientset(
+		&rbac.Role{
+			ObjectMeta: meta.ObjectMeta{Name: "pod-creator", Namespace: "foo"},
+			Rules:      []rbac.PolicyRule{{APIGroups: []string{""}, Verbs: []string{"create"}, Resources: []string{"pods"}}},
+		},
+		&rbac.ClusterRole{
+			ObjectMeta: meta.ObjectMeta{Name: "deployer"},
+			Rules: []rbac.PolicyRule{{APIGroups: []string{"apps"}, Verbs: []string{"create", "update"},
+				Resources: []string{"deployments", "statefulsets"}}},
+		},
+		&rbac.ClusterRole{
+			ObjectMeta: meta.ObjectMeta{Name: "view"},
+			Rules:      []rbac.PolicyRule{{APIGroups: []string{"", "apps"}, Verbs: []string{"get", "list"}, Resources: []string{"*"}}},
+		},
+		&rbac.RoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "alice-can-create-pods", Namespace: "foo"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "pod-creator"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "alice"}},
+		},
+		&rbac.RoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "alice-can-deploy", Namespace: "bar"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "deployer"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "alice"}},
+		},
+		&rbac.ClusterRoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "ci-can-deploy"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "deployer"},
+			Subjects:   []rbac.Subject{{Kind: rbac.ServiceAccountKind, Name: "ci", Namespace: "tools"}},
+		},
+		&rbac.ClusterRoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "bob-can-view"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "view"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "bob"}},
+		},
+	)
+
+	wc := whoCan{
+		namespace:  "",
+		clientRBAC: client.RbacV1(),
+	}
+
+	// when
+	findings, err := wc.findEscalations(podCreationChecks)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []escalationFinding{
+		{
+			subject:    rbac.Subject{Kind: rbac.ServiceAccountKind, Name: "ci", Namespace: "tools"},
+			actions:    []string{"create deployments.apps", "create statefulsets.apps"},
+			namespaces: []string{"cluster-wide"},
+		},
+		{
+			subject:    rbac.Subject{Kind: rbac.UserKind, Name: "alice"},
+			actions:    []string{"create pods", "create deployments.apps", "create statefulsets.apps"},
+			namespaces: []string{"bar", "foo"},
+		},
+	}, findings)
+}
+
+func TestWhoCan_findEscalations_BindingManagement(t *testing.T) {
+	// given
+	client := fake.NewSimpleClientset(
+		&rbac.Role{
+			ObjectMeta: meta.ObjectMeta{Name: "binder", Namespace: "foo"},
+			Rules: []rbac.PolicyRule{{APIGroups: []string{rbac.GroupName}, Verbs: []string{"create"},
+				Resources: []string{"rolebindings"}}},
+		},
+		&rbac.Role{
+			ObjectMeta: meta.ObjectMeta{Name: "binding-viewer", Namespace: "foo"},
+			Rules: []rbac.PolicyRule{{APIGroups: []string{rbac.GroupName}, Verbs: []string{"get", "list"},
+				Resources: []string{"rolebindings"}}},
+		},
+		&rbac.ClusterRole{
+			ObjectMeta: meta.ObjectMeta{Name: "binding-patcher"},
+			Rules: []rbac.PolicyRule{{APIGroups: []string{rbac.GroupName}, Verbs: []string{"patch"},
+				Resources: []string{"rolebindings", "clusterrolebindings"}}},
+		},
+		&rbac.RoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "alice-can-bind", Namespace: "foo"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "binder"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "alice"}},
+		},
+		&rbac.RoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "alice-can-patch", Namespace: "bar"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "binding-patcher"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "alice"}},
+		},
+		&rbac.RoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "bob-can-view-bindings", Namespace: "foo"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "binding-viewer"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "bob"}},
+		},
+		&rbac.ClusterRoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "ops-can-patch"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "binding-patcher"},
+			Subjects:   []rbac.Subject{{Kind: rbac.GroupKind, Name: "ops"}},
+		},
+	)
+
+	wc := whoCan{
+		namespace:  "",
+		clientRBAC: client.RbacV1(),
+	}
+
+	// when
+	findings, err := wc.findEscalations(bindingManagementChecks)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []escalationFinding{
+		{
+			subject: rbac.Subject{Kind: rbac.GroupKind, Name: "ops"},
+			actions: []string{"patch rolebindings.rbac.authorization.k8s.io",
+				"patch clusterrolebindings.rbac.authorization.k8s.io"},
+			namespaces: []string{"cluster-wide"},
+		},
+		{
+			subject:    rbac.Subject{Kind: rbac.UserKind, Name: "alice"},
+			actions:    []string{"create rolebindings.rbac.authorization.k8s.io", "patch rolebindings.rbac.authorization.k8s.io"},
+			namespaces: []string{"bar", "foo"},
+		},
+	}, findings)
+}
+
+func TestWhoCan_printEscalations(t *testing.T) {
+	section := escalationSection{
+		title:       "Subjects who can escalate privileges by running pods as any service account",
+		explanation: "Explanation.",
+		checks:      []escalationCheck{{verb: "create", resource: "pods"}, {verb: "create", resource: "jobs", apiGroup: "batch"}},
+	}
+
+	data := []struct {
+		scenario string
+		findings []escalationFinding
+		output   string
+	}{
+		{
+			scenario: "Should print findings",
+			findings: []escalationFinding{
+				{
+					subject:    rbac.Subject{Kind: rbac.ServiceAccountKind, Name: "ci", Namespace: "tools"},
+					actions:    []string{"create jobs.batch"},
+					namespaces: []string{"cluster-wide"},
+				},
+				{
+					subject:    rbac.Subject{Kind: rbac.UserKind, Name: "alice"},
+					actions:    []string{"create pods", "create jobs.batch"},
+					namespaces: []string{"bar", "foo"},
+				},
+			},
+			output: `Subjects who can escalate privileges by running pods as any service account:
+
+Explanation.
+
+SUBJECT  TYPE            SA-NAMESPACE  ACTIONS                         NAMESPACES
+ci       ServiceAccount  tools         create jobs.batch               cluster-wide
+alice    User                          create pods, create jobs.batch  bar, foo
+`,
+		},
+		{
+			scenario: "Should print message when there are no findings",
+			output: `Subjects who can escalate privileges by running pods as any service account:
+
+Explanation.
+
+No subjects found who can create pods, create jobs.batch
+`,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			streams, _, out, _ := clioptions.NewTestIOStreams()
+			wc := whoCan{IOStreams: streams}
+
+			// when
+			wc.printEscalations(section, tt.findings)
+
+			// then
+			assert.Equal(t, tt.output, out.String())
+		})
+	}
+}
+
+func TestWhoCan_runEscalationReport_WithArgs(t *testing.T) {
+	wc := whoCan{}
+
+	err := wc.runEscalationReport([]string{"get", "pods"})
+
+	assert.Equal(t, errors.New("--escalation cannot be used with VERB and TYPE"), err)
+}
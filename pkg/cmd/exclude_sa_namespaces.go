@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	rbac "k8s.io/api/rbac/v1"
+)
+
+// serviceAccountNamespace returns the namespace of a ServiceAccount subject of a binding.
+// Like the RBAC authorizer, a ServiceAccount without a namespace in a RoleBinding refers to the namespace of the RoleBinding.
+func serviceAccountNamespace(s rbac.Subject, bindingNamespace string) string {
+	if s.Namespace == "" {
+		return bindingNamespace
+	}
+	return s.Namespace
+}
+
+// excludeServiceAccounts drops the ServiceAccount subjects of the namespaces given by --exclude-sa-namespace
+// from the bindings, as well as the bindings which are left without subjects.
+func (w *whoCan) excludeServiceAccounts(roleBindings []rbac.RoleBinding, clusterRoleBindings []rbac.ClusterRoleBinding) ([]rbac.RoleBinding, []rbac.ClusterRoleBinding) {
+	if len(w.excludeSANamespaces) == 0 {
+		return roleBindings, clusterRoleBindings
+	}
+	excluded := make(map[string]struct{}, len(w.excludeSANamespaces))
+	for _, namespace := range w.excludeSANamespaces {
+		excluded[namespace] = struct{}{}
+	}
+
+	kept := func(subjects []rbac.Subject, bindingNamespace string) []rbac.Subject {
+		var kept []rbac.Subject
+		for _, s := range subjects {
+			if s.Kind == rbac.ServiceAccountKind {
+				if _, ok := excluded[serviceAccountNamespace(s, bindingNamespace)]; ok {
+					continue
+				}
+			}
+			kept = append(kept, s)
+		}
+		return kept
+	}
+
+	var keptRoleBindings []rbac.RoleBinding
+	for _, rb := range roleBindings {
+		if rb.Subjects = kept(rb.Subjects, rb.Namespace); len(rb.Subjects) > 0 {
+			keptRoleBindings = append(keptRoleBindings, rb)
+		}
+	}
+	var keptClusterRoleBindings []rbac.ClusterRoleBinding
+	for _, crb := range clusterRoleBindings {
+		if crb.Subjects = kept(crb.Subjects, ""); len(crb.Subjects) > 0 {
+			keptClusterRoleBindings = append(keptClusterRoleBindings, crb)
+		}
+	}
+	return keptRoleBindings, keptClusterRoleBindings
+}
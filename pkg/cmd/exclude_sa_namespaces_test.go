@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+func TestWhoCan_excludeServiceAccounts(t *testing.T) {
+	alice := rbac.Subject{Kind: rbac.UserKind, Name: "alice"}
+	monitoring := rbac.Subject{Kind: rbac.ServiceAccountKind, Name: "prometheus", Namespace: "monitoring"}
+	deployer := rbac.Subject{Kind: rbac.ServiceAccountKind, Name: "deployer", Namespace: "ci"}
+	// implicitDeployer is a ServiceAccount of the namespace of the RoleBinding which binds it.
+	implicitDeployer := rbac.Subject{Kind: rbac.ServiceAccountKind, Name: "deployer"}
+
+	roleBindings := []rbac.RoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "readers", Namespace: "ci"},
+			Subjects:   []rbac.Subject{alice, monitoring, implicitDeployer},
+		},
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "scrapers", Namespace: "foo"},
+			Subjects:   []rbac.Subject{monitoring},
+		},
+	}
+	clusterRoleBindings := []rbac.ClusterRoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "deployers"},
+			Subjects:   []rbac.Subject{deployer},
+		},
+	}
+
+	data := []struct {
+		scenario   string
+		namespaces []string
+
+		roleBindings        []rbac.RoleBinding
+		clusterRoleBindings []rbac.ClusterRoleBinding
+	}{
+		{
+			scenario:            "Should keep all subjects when no namespace is excluded",
+			roleBindings:        roleBindings,
+			clusterRoleBindings: clusterRoleBindings,
+		},
+		{
+			scenario:   "Should drop ServiceAccounts of the excluded namespace and bindings left without subjects",
+			namespaces: []string{"monitoring"},
+			roleBindings: []rbac.RoleBinding{
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "readers", Namespace: "ci"},
+					Subjects:   []rbac.Subject{alice, implicitDeployer},
+				},
+			},
+			clusterRoleBindings: clusterRoleBindings,
+		},
+		{
+			scenario:   "Should drop ServiceAccounts which default to the namespace of the RoleBinding",
+			namespaces: []string{"ci"},
+			roleBindings: []rbac.RoleBinding{
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "readers", Namespace: "ci"},
+					Subjects:   []rbac.Subject{alice, monitoring},
+				},
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "scrapers", Namespace: "foo"},
+					Subjects:   []rbac.Subject{monitoring},
+				},
+			},
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			wc := whoCan{excludeSANamespaces: tt.namespaces}
+
+			// when
+			rbs, crbs := wc.excludeServiceAccounts(roleBindings, clusterRoleBindings)
+
+			// then
+			assert.Equal(t, tt.roleBindings, rbs)
+			assert.Equal(t, tt.clusterRoleBindings, crbs)
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"sort"
+	"strings"
+)
+
+const (
+	execUsage = `exec --workload TYPE/NAME`
+	execLong  = `Shows the service accounts used by the pods of a workload and which users, groups and service accounts can exec into pods in the workload's namespace.
+
+TYPE is one of 'pod', 'deployment', 'statefulset', 'daemonset', 'replicaset' or 'job'. Shortcuts, such as 'po' or 'deploy', will be resolved.`
+	execExample = `  # List who can exec into the pods of the deployment "foo" in the current namespace
+  kubectl who-can exec --workload deployment/foo
+
+  # List who can exec into the pod "bar" in namespace "baz"
+  kubectl who-can exec --workload pod/bar -n baz`
+)
+
+// workloadKinds maps the supported workload types, including plurals and short names, to their canonical names.
+var workloadKinds = map[string]string{
+	"pod": "pod", "pods": "pod", "po": "pod",
+	"deployment": "deployment", "deployments": "deployment", "deploy": "deployment",
+	"statefulset": "statefulset", "statefulsets": "statefulset", "sts": "statefulset",
+	"daemonset": "daemonset", "daemonsets": "daemonset", "ds": "daemonset",
+	"replicaset": "replicaset", "replicasets": "replicaset", "rs": "replicaset",
+	"job": "job", "jobs": "job",
+}
+
+type execWhoCan struct {
+	workload string
+
+	client kubernetes.Interface
+	whoCan *whoCan
+
+	clioptions.IOStreams
+}
+
+func NewCmdExec(client kubernetes.Interface, w *whoCan, streams clioptions.IOStreams) *cobra.Command {
+	o := &execWhoCan{
+		client:    client,
+		whoCan:    w,
+		IOStreams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:          execUsage,
+		Short:        "Show who can exec into the pods of a workload",
+		Long:         execLong,
+		Example:      execExample,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.run()
+			silenceExitError(cmd, err)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&o.workload, "workload", "", "Workload whose pods are checked, such as deployment/foo")
+	// The output flags are local to the parent command, so they are registered again on the options it shares.
+	cmd.Flags().StringVarP(&w.outputFormat, "output", "o", "",
+		"Output format. One of: "+strings.Join(allOutputFormats(), "|")+".")
+	cmd.Flags().StringVar((*string)(&w.sortBy), "sort-by", "",
+		"If set, order the rows of the tables, and the bindings of the structured output formats, by this column. One of: subject|kind|namespace|binding.")
+
+	return cmd
+}
+
+func (e *execWhoCan) run() error {
+	kind, name, err := parseWorkload(e.workload)
+	if err != nil {
+		return err
+	}
+
+	w := e.whoCan
+	if err := w.start(); err != nil {
+		return err
+	}
+	w.subResource = "exec"
+	if err := w.Complete([]string{"create", "pods"}); err != nil {
+		return err
+	}
+	if w.namespace == core.NamespaceAll {
+		return errors.New("--all-namespaces cannot be used with --workload")
+	}
+	if err := w.Validate(); err != nil {
+		return err
+	}
+
+	serviceAccounts, err := e.serviceAccountsOf(w.namespace, kind, name)
+	if err != nil {
+		return fmt.Errorf("getting service accounts of %s/%s: %v", kind, name, err)
+	}
+
+	_, _ = fmt.Fprintf(e.Out, "The pods of %s/%s in the %s namespace run as service account(s): %s\n\n",
+		kind, name, w.namespace, strings.Join(serviceAccounts, ", "))
+
+	return w.Check()
+}
+
+// parseWorkload parses the TYPE/NAME argument into the canonical workload kind and its name.
+func parseWorkload(workload string) (string, string, error) {
+	tokens := strings.SplitN(workload, "/", 2)
+	if len(tokens) != 2 || tokens[1] == "" {
+		return "", "", fmt.Errorf("invalid workload \"%s\", expected TYPE/NAME", workload)
+	}
+	kind, ok := workloadKinds[strings.ToLower(tokens[0])]
+	if !ok {
+		return "", "", fmt.Errorf("unsupported workload type \"%s\"", tokens[0])
+	}
+	return kind, tokens[1], nil
+}
+
+// serviceAccountsOf returns the sorted names of the service accounts used by the running pods of the given workload
+// and by its pod template.
+func (e *execWhoCan) serviceAccountsOf(namespace, kind, name string) ([]string, error) {
+	var selector *meta.LabelSelector
+	var template core.PodSpec
+
+	switch kind {
+	case "pod":
+		pod, err := e.client.CoreV1().Pods(namespace).Get(name, meta.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return []string{serviceAccountName(pod.Spec)}, nil
+	case "deployment":
+		obj, err := e.client.AppsV1().Deployments(namespace).Get(name, meta.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector, template = obj.Spec.Selector, obj.Spec.Template.Spec
+	case "statefulset":
+		obj, err := e.client.AppsV1().StatefulSets(namespace).Get(name, meta.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector, template = obj.Spec.Selector, obj.Spec.Template.Spec
+	case "daemonset":
+		obj, err := e.client.AppsV1().DaemonSets(namespace).Get(name, meta.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector, template = obj.Spec.Selector, obj.Spec.Template.Spec
+	case "replicaset":
+		obj, err := e.client.AppsV1().ReplicaSets(namespace).Get(name, meta.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector, template = obj.Spec.Selector, obj.Spec.Template.Spec
+	case "job":
+		obj, err := e.client.BatchV1().Jobs(namespace).Get(name, meta.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector, template = obj.Spec.Selector, obj.Spec.Template.Spec
+	}
+
+	names := map[string]struct{}{
+		serviceAccountName(template): {},
+	}
+
+	// Pods created from an older template might still run as a different service account.
+	if selector != nil {
+		s, err := meta.LabelSelectorAsSelector(selector)
+		if err != nil {
+			return nil, fmt.Errorf("parsing selector: %v", err)
+		}
+		if !s.Empty() {
+			pods, err := e.client.CoreV1().Pods(namespace).List(meta.ListOptions{LabelSelector: s.String()})
+			if err != nil {
+				return nil, fmt.Errorf("listing pods: %v", err)
+			}
+			for _, pod := range pods.Items {
+				names[serviceAccountName(pod.Spec)] = struct{}{}
+			}
+		}
+	}
+
+	var serviceAccounts []string
+	for name := range names {
+		serviceAccounts = append(serviceAccounts, name)
+	}
+	sort.Strings(serviceAccounts)
+	return serviceAccounts, nil
+}
+
+// serviceAccountName returns the name of the service account of the given pod spec,
+// which defaults to the `default` service account.
+func serviceAccountName(spec core.PodSpec) string {
+	if spec.ServiceAccountName == "" {
+		return defaultServiceAccount
+	}
+	return spec.ServiceAccountName
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+)
+
+func TestParseWorkload(t *testing.T) {
+	data := []struct {
+		workload string
+
+		kind string
+		name string
+		err  error
+	}{
+		{workload: "deployment/foo", kind: "deployment", name: "foo"},
+		{workload: "deploy/foo", kind: "deployment", name: "foo"},
+		{workload: "po/bar", kind: "pod", name: "bar"},
+		{workload: "STS/baz", kind: "statefulset", name: "baz"},
+		{workload: "foo", err: errors.New("invalid workload \"foo\", expected TYPE/NAME")},
+		{workload: "deployment/", err: errors.New("invalid workload \"deployment/\", expected TYPE/NAME")},
+		{workload: "cronjob/foo", err: errors.New("unsupported workload type \"cronjob\"")},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.workload, func(t *testing.T) {
+			kind, name, err := parseWorkload(tt.workload)
+
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.kind, kind)
+			assert.Equal(t, tt.name, name)
+		})
+	}
+}
+
+func TestExecWhoCan_serviceAccountsOf(t *testing.T) {
+	newPod := func(name, serviceAccount string, labels map[string]string) *core.Pod {
+		return &core.Pod{
+			ObjectMeta: meta.ObjectMeta{Name: name, Namespace: "foo", Labels: labels},
+			Spec:       core.PodSpec{ServiceAccountName: serviceAccount},
+		}
+	}
+
+	objects := []runtime.Object{
+		&apps.Deployment{
+			ObjectMeta: meta.ObjectMeta{Name: "web", Namespace: "foo"},
+			Spec: apps.DeploymentSpec{
+				Selector: &meta.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				Template: core.PodTemplateSpec{Spec: core.PodSpec{ServiceAccountName: "web-v2"}},
+			},
+		},
+		&apps.DaemonSet{
+			ObjectMeta: meta.ObjectMeta{Name: "agent", Namespace: "foo"},
+			Spec: apps.DaemonSetSpec{
+				Selector: &meta.LabelSelector{MatchLabels: map[string]string{"app": "agent"}},
+			},
+		},
+		newPod("web-1", "web-v1", map[string]string{"app": "web"}),
+		newPod("web-2", "web-v2", map[string]string{"app": "web"}),
+		newPod("db-1", "db", map[string]string{"app": "db"}),
+	}
+
+	data := []struct {
+		scenario string
+
+		kind string
+		name string
+
+		serviceAccounts []string
+		err             error
+	}{
+		{
+			scenario:        "Should return service accounts of pods and template of deployment",
+			kind:            "deployment",
+			name:            "web",
+			serviceAccounts: []string{"web-v1", "web-v2"},
+		},
+		{
+			scenario:        "Should return default service account of daemonset without pods",
+			kind:            "daemonset",
+			name:            "agent",
+			serviceAccounts: []string{"default"},
+		},
+		{
+			scenario:        "Should return service account of pod",
+			kind:            "pod",
+			name:            "db-1",
+			serviceAccounts: []string{"db"},
+		},
+		{
+			scenario: "Should return error when workload does not exist",
+			kind:     "statefulset",
+			name:     "web",
+			err:      errors.New("statefulsets.apps \"web\" not found"),
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			e := execWhoCan{client: fake.NewSimpleClientset(objects...)}
+
+			// when
+			serviceAccounts, err := e.serviceAccountsOf("foo", tt.kind, tt.name)
+
+			// then
+			if tt.err != nil {
+				assert.EqualError(t, err, tt.err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.serviceAccounts, serviceAccounts)
+		})
+	}
+}
+
+func TestNewCmdExec_AllNamespaces(t *testing.T) {
+	// given
+	resourceResolver := new(resourceResolverMock)
+	resourceResolver.On("Resolve", "create", "pods", "exec").
+		Return(ResolvedResource{Resource: "pods/exec", Namespaced: true}, nil)
+	configFlags := &clioptions.ConfigFlags{}
+	w := NewWhoCanOptions(configFlags,
+		configFlags.ToRawKubeConfigLoader(),
+		nil,
+		nil,
+		new(namespaceValidatorMock),
+		resourceResolver,
+		new(accessCheckerMock),
+		clioptions.NewTestIOStreamsDiscard())
+
+	root := &cobra.Command{Use: "who-can", SilenceErrors: true}
+	root.PersistentFlags().BoolVarP(&w.allNamespaces, "all-namespaces", "A", false, "")
+	root.AddCommand(NewCmdExec(fake.NewSimpleClientset(), w, clioptions.NewTestIOStreamsDiscard()))
+	root.SetArgs([]string{"exec", "--workload", "deployment/web", "-A"})
+
+	// when
+	err := root.Execute()
+
+	// then
+	assert.EqualError(t, err, "--all-namespaces cannot be used with --workload")
+	resourceResolver.AssertExpectations(t)
+}
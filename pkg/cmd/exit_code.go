@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// ExitCodeNoSubjects is the exit code of a query which succeeds, but finds no subject granted the queried action.
+// It differs from the exit code of errors, so that scripts can tell both apart.
+const ExitCodeNoSubjects = 2
+
+// ExitError is returned when a command completes, but signals its result by a non-zero exit code.
+// It is not printed as an error.
+type ExitError struct {
+	Code   int
+	Reason string
+}
+
+func (e *ExitError) Error() string {
+	return e.Reason
+}
+
+// silenceExitError prevents the given error from being printed by the command if it is an ExitError.
+// It returns `true` if the error is an ExitError.
+func silenceExitError(cmd *cobra.Command, err error) bool {
+	if _, ok := err.(*ExitError); !ok {
+		return false
+	}
+	cmd.SilenceErrors = true
+	return true
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+)
+
+func TestWhoCan_Check_ExitCode(t *testing.T) {
+	data := []struct {
+		scenario string
+
+		verb     string
+		quiet    bool
+		warnings bool
+
+		expectedErr    error
+		expectedOut    string
+		expectedErrOut string
+	}{
+		{
+			scenario: "Should print table and return nil when a subject matches",
+			verb:     "get",
+			expectedOut: `ROLEBINDING          NAMESPACE  SUBJECT  TYPE  SA-NAMESPACE
+Alice-can-view-pods  foo        Alice    User  
+
+No subjects found with permissions to get pods assigned through ClusterRoleBindings
+`,
+		},
+		{
+			scenario:    "Should print nothing and return nil when a subject matches in quiet mode",
+			verb:        "get",
+			quiet:       true,
+			expectedOut: "",
+		},
+		{
+			scenario:    "Should return exit error when no subject matches",
+			verb:        "delete",
+			expectedErr: &ExitError{Code: ExitCodeNoSubjects, Reason: "no subjects found with permissions to delete pods"},
+			expectedOut: `No subjects found with permissions to delete pods assigned through RoleBindings
+
+No subjects found with permissions to delete pods assigned through ClusterRoleBindings
+`,
+		},
+		{
+			scenario:    "Should print nothing and return exit error when no subject matches in quiet mode",
+			verb:        "delete",
+			quiet:       true,
+			expectedErr: &ExitError{Code: ExitCodeNoSubjects, Reason: "no subjects found with permissions to delete pods"},
+			expectedOut: "",
+		},
+		{
+			scenario:       "Should print warnings to standard error in quiet mode",
+			verb:           "delete",
+			quiet:          true,
+			warnings:       true,
+			expectedErr:    &ExitError{Code: ExitCodeNoSubjects, Reason: "no subjects found with permissions to delete pods"},
+			expectedOut:    "",
+			expectedErrOut: "Warning: The list might not be complete due to missing permission(s):\n\tThe user is not allowed to list roles in the foo namespace\n\tThe user is not allowed to list rolebindings in the foo namespace\n\n",
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			client := fake.NewSimpleClientset(
+				&rbac.Role{
+					ObjectMeta: meta.ObjectMeta{Name: "view-pods", Namespace: "foo"},
+					Rules:      []rbac.PolicyRule{{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"pods"}}},
+				},
+				&rbac.RoleBinding{
+					ObjectMeta: meta.ObjectMeta{Name: "Alice-can-view-pods", Namespace: "foo"},
+					RoleRef:    rbac.RoleRef{Kind: "Role", Name: "view-pods"},
+					Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "Alice"}},
+				},
+			)
+			accessChecker := new(accessCheckerMock)
+			for _, resource := range namespacedAPIAccess {
+				accessChecker.On("IsAllowedTo", "list", rbac.GroupName, resource, "foo").Return(!tt.warnings, nil)
+			}
+
+			streams, _, out, errOut := clioptions.NewTestIOStreams()
+			wc := whoCan{
+				verb:               tt.verb,
+				resource:           "pods",
+				namespacedResource: true,
+				namespace:          "foo",
+				quiet:              tt.quiet,
+				clientRBAC:         client.RbacV1(),
+				accessChecker:      accessChecker,
+				IOStreams:          streams,
+			}
+
+			// when
+			err := wc.Check()
+
+			// then
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedOut, out.String())
+			assert.Equal(t, tt.expectedErrOut, errOut.String())
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	forSubjectUsage = `for KIND/NAME`
+	forSubjectLong  = `Shows what a subject can do, which is the inverse of checking who can perform an action.
+
+The rules granted to the subject by RoleBindings and ClusterRoleBindings are listed, either directly or through a group
+which the subject implicitly belongs to, such as system:authenticated. Like for checking an action, the RoleBindings of
+the current namespace are considered unless --namespace or --all-namespaces is given.
+
+KIND is one of 'User', 'Group' or 'ServiceAccount', and may be given in lower case. The NAME of a ServiceAccount is given
+as NAMESPACE:NAME. The KIND and the NAME may also be given as separate arguments.`
+	forSubjectExample = `  # List what the user "alice" can do in the current namespace
+  kubectl who-can for User/alice
+
+  # List what the user "alice" can do in the current namespace, with the kind and the name as separate arguments
+  kubectl who-can for user alice
+
+  # List what the service account "deployer" of the namespace "ci" can do in all namespaces
+  kubectl who-can for ServiceAccount/ci:deployer --all-namespaces`
+)
+
+type forSubjectWhoCan struct {
+	subject        string
+	namespace      string
+	allNamespaces  bool
+	showUnresolved bool
+
+	configFlags  *clioptions.ConfigFlags
+	clientConfig clientcmd.ClientConfig
+	client       kubernetes.Interface
+
+	clioptions.IOStreams
+}
+
+func NewCmdForSubject(client kubernetes.Interface, configFlags *clioptions.ConfigFlags, streams clioptions.IOStreams) *cobra.Command {
+	o := &forSubjectWhoCan{
+		configFlags:  configFlags,
+		clientConfig: configFlags.ToRawKubeConfigLoader(),
+		client:       client,
+		IOStreams:    streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:          forSubjectUsage,
+		Short:        "Show what a subject can do",
+		Long:         forSubjectLong,
+		Example:      forSubjectExample,
+		SilenceUsage: true,
+		Args:         cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.subject = strings.Join(args, "/")
+			var err error
+			o.namespace, err = resolveNamespace(o.configFlags, o.clientConfig, o.allNamespaces)
+			if err != nil {
+				return err
+			}
+			return o.run()
+		},
+	}
+
+	cmd.Flags().BoolVarP(&o.allNamespaces, "all-namespaces", "A", false,
+		"If true, consider the RoleBindings in all namespaces.")
+	cmd.Flags().BoolVar(&o.showUnresolved, "show-unresolved", false,
+		"If true, also list the bindings which refer to roles that are missing or that cannot be read, with the reason why their rules are unavailable.")
+
+	return cmd
+}
+
+func (o *forSubjectWhoCan) run() error {
+	subject, err := parseSubject(o.subject)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := loadRBACSnapshot(o.client.RbacV1(), o.namespace)
+	if err != nil {
+		return err
+	}
+	snapshot.showUnresolved = o.showUnresolved
+	grants, err := snapshot.grantsFor(subject)
+	if err != nil {
+		return err
+	}
+
+	if len(grants) == 0 {
+		_, _ = fmt.Fprintf(o.Out, "No permissions found for %s\n", o.subject)
+		return nil
+	}
+	printGrants(o.Out, grants)
+	return nil
+}
+
+// printGrants prints a table of the given grants with the binding, the role and the rule of each.
+func printGrants(out io.Writer, grants []grant) {
+	wr := new(tabwriter.Writer)
+	wr.Init(out, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintln(wr, "BINDING\tNAMESPACE\tROLE\tVERBS\tRESOURCES\tRESOURCE-NAMES")
+	for _, g := range grants {
+		if g.unresolved != "" {
+			_, _ = fmt.Fprintf(wr, "%s/%s\t%s\t%s/%s\t(rules unavailable: %s)\t\t\n", g.bindingKind, g.binding, g.namespace,
+				g.roleRef.Kind, g.roleRef.Name, g.unresolved)
+			continue
+		}
+		_, _ = fmt.Fprintf(wr, "%s/%s\t%s\t%s/%s\t%s\t%s\t%s\n", g.bindingKind, g.binding, g.namespace,
+			g.roleRef.Kind, g.roleRef.Name, strings.Join(g.rule.Verbs, ","), ruleResources(g.rule), strings.Join(g.rule.ResourceNames, ","))
+	}
+	_ = wr.Flush()
+}
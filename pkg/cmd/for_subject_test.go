@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+)
+
+func TestForSubjectWhoCan_run(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&rbac.Role{ObjectMeta: meta.ObjectMeta{Name: "secret-reader", Namespace: "foo"},
+			Rules: []rbac.PolicyRule{{APIGroups: []string{""}, Verbs: []string{"get", "list"}, Resources: []string{"secrets"}}}},
+		&rbac.ClusterRole{ObjectMeta: meta.ObjectMeta{Name: "tls-rotator"},
+			Rules: []rbac.PolicyRule{{APIGroups: []string{""}, Verbs: []string{"update"}, Resources: []string{"secrets"}, ResourceNames: []string{"tls"}}}},
+		&rbac.RoleBinding{ObjectMeta: meta.ObjectMeta{Name: "alice-reads-secrets", Namespace: "foo"},
+			RoleRef: rbac.RoleRef{Kind: "Role", Name: "secret-reader"}, Subjects: []rbac.Subject{{Kind: rbac.UserKind, Name: "alice"}}},
+		&rbac.ClusterRoleBinding{ObjectMeta: meta.ObjectMeta{Name: "alice-rotates-tls"},
+			RoleRef: rbac.RoleRef{Kind: "ClusterRole", Name: "tls-rotator"}, Subjects: []rbac.Subject{{Kind: rbac.UserKind, Name: "alice"}}},
+	)
+
+	data := []struct {
+		scenario string
+		subject  string
+		output   string
+		err      string
+	}{
+		{
+			scenario: "Should print rules granted to subject",
+			subject:  "user/alice",
+			output: `BINDING                               NAMESPACE  ROLE                     VERBS     RESOURCES  RESOURCE-NAMES
+RoleBinding/alice-reads-secrets       foo        Role/secret-reader       get,list  secrets    
+ClusterRoleBinding/alice-rotates-tls             ClusterRole/tls-rotator  update    secrets    tls
+`,
+		},
+		{
+			scenario: "Should print no permissions of subject which is not bound",
+			subject:  "User/bob",
+			output:   "No permissions found for User/bob\n",
+		},
+		{
+			scenario: "Should return error when subject is invalid",
+			subject:  "alice",
+			err:      "invalid subject \"alice\", expected KIND/NAME",
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			streams, _, out, _ := clioptions.NewTestIOStreams()
+			o := &forSubjectWhoCan{subject: tt.subject, namespace: "foo", client: client, IOStreams: streams}
+
+			// when
+			err := o.run()
+
+			// then
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.output, out.String())
+		})
+	}
+}
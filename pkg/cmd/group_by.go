@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"text/tabwriter"
+
+	rbac "k8s.io/api/rbac/v1"
+)
+
+// groupByAPIGroup prints the matched grants in one section per API group of the granted resources.
+const groupByAPIGroup = "apigroup"
+
+// recordAPIGroups records the API groups of the resources which the given matching rule grants the queried action on.
+// When querying all resources with `*` these are the API groups of the rule, otherwise the API group of the queried resource.
+func (w *whoCan) recordAPIGroups(r role, rule rbac.PolicyRule) {
+	if w.apiGroups == nil {
+		w.apiGroups = make(map[role]map[string]struct{})
+	}
+	groups, ok := w.apiGroups[r]
+	if !ok {
+		groups = make(map[string]struct{})
+		w.apiGroups[r] = groups
+	}
+	if w.resource != rbac.ResourceAll {
+		groups[w.apiGroup] = struct{}{}
+		return
+	}
+	for _, group := range rule.APIGroups {
+		groups[group] = struct{}{}
+	}
+}
+
+// outputByAPIGroup prints the subjects in one section per API group of the resources granted by the matched roles.
+// A subject is listed in several sections if its role grants the action on resources of several API groups.
+func (w *whoCan) outputByAPIGroup(roleBindings []rbac.RoleBinding, clusterRoleBindings []rbac.ClusterRoleBinding) {
+	all := w.roleBindingRows(roleBindings)
+	for i := range all {
+		all[i].bindingKind = "RoleBinding"
+	}
+	for _, r := range w.clusterRoleBindingRows(clusterRoleBindings) {
+		r.bindingKind = "ClusterRoleBinding"
+		all = append(all, r)
+	}
+	if len(all) == 0 {
+		fmt.Fprintf(w.Out, "No subjects found with permissions to %s\n", w.prettyPrintAction())
+		return
+	}
+	if w.sortBy != "" {
+		sortRows(all, w.sortBy)
+	}
+
+	rowsByGroup := make(map[string][]subjectRow)
+	for _, r := range all {
+		for group := range w.apiGroups[newRoleFromRef(&r.roleRef, r.namespace)] {
+			rowsByGroup[group] = append(rowsByGroup[group], r)
+		}
+	}
+	var groups []string
+	for group := range rowsByGroup {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	wr := new(tabwriter.Writer)
+	wr.Init(w.Out, 0, 8, 2, ' ', 0)
+	limit := &rowLimit{max: w.maxResults}
+	for i, group := range groups {
+		rows := limit.take(rowsByGroup[group])
+		if len(rows) == 0 {
+			continue
+		}
+		if i > 0 {
+			fmt.Fprintln(wr)
+		}
+		fmt.Fprintf(wr, "API group %s:\n", displayAPIGroup(group))
+		w.printHeader(wr, "BINDING\tNAMESPACE\tSUBJECT\tTYPE\tSA-NAMESPACE")
+		for _, r := range rows {
+			fmt.Fprintf(wr, "%s%s/%s\t%s\t%s\t%s\t%s%s%s\n", w.rowColor(r), r.bindingKind, r.binding, r.namespace, r.subject.Name, r.subject.Kind, r.subject.Namespace, w.optionalColumns(r), w.endColor())
+		}
+	}
+	wr.Flush()
+	limit.printNotice(w.Out)
+}
+
+// displayAPIGroup returns the name under which the given API group is shown by the apigroup rendering.
+func displayAPIGroup(group string) string {
+	switch group {
+	case "":
+		return coreGroup
+	case rbac.APIGroupAll:
+		return "* (all API groups)"
+	}
+	return group
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"testing"
+)
+
+func TestWhoCan_outputByAPIGroup(t *testing.T) {
+	roles := &rbac.RoleList{Items: []rbac.Role{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "core-reader", Namespace: "foo"},
+			Rules:      []rbac.PolicyRule{{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"*"}}},
+		},
+	}}
+	clusterRoles := &rbac.ClusterRoleList{Items: []rbac.ClusterRole{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "apps-reader"},
+			Rules:      []rbac.PolicyRule{{Verbs: []string{"get"}, APIGroups: []string{"apps"}, Resources: []string{"*"}}},
+		},
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "workload-reader"},
+			Rules: []rbac.PolicyRule{
+				{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"*"}},
+				{Verbs: []string{"get"}, APIGroups: []string{"apps"}, Resources: []string{"*"}},
+			},
+		},
+	}}
+	roleBindings := []rbac.RoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "alice-can-read-core", Namespace: "foo"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "core-reader"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "alice"}},
+		},
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "bob-can-read-apps", Namespace: "foo"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "apps-reader"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "bob"}},
+		},
+	}
+	clusterRoleBindings := []rbac.ClusterRoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "ops-can-read-workloads"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "workload-reader"},
+			Subjects:   []rbac.Subject{{Kind: rbac.GroupKind, Name: "ops"}},
+		},
+	}
+
+	data := []struct {
+		scenario string
+		resource string
+		apiGroup string
+
+		output string
+	}{
+		{
+			scenario: "Should print grants of all resources in a section per API group",
+			resource: "*",
+			output: `API group core:
+BINDING                                    NAMESPACE  SUBJECT  TYPE   SA-NAMESPACE
+RoleBinding/alice-can-read-core            foo        alice    User   
+ClusterRoleBinding/ops-can-read-workloads             ops      Group  
+
+API group apps:
+BINDING                                    NAMESPACE  SUBJECT  TYPE   SA-NAMESPACE
+RoleBinding/bob-can-read-apps              foo        bob      User   
+ClusterRoleBinding/ops-can-read-workloads             ops      Group  
+`,
+		},
+		{
+			scenario: "Should print grants of a single resource in the section of its API group",
+			resource: "deployments",
+			apiGroup: "apps",
+			output: `API group apps:
+BINDING                                    NAMESPACE  SUBJECT  TYPE   SA-NAMESPACE
+RoleBinding/bob-can-read-apps              foo        bob      User   
+ClusterRoleBinding/ops-can-read-workloads             ops      Group  
+`,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			streams, _, out, _ := clioptions.NewTestIOStreams()
+			wc := whoCan{
+				verb:      "get",
+				resource:  tt.resource,
+				apiGroup:  tt.apiGroup,
+				groupBy:   groupByAPIGroup,
+				r:         make(map[role]struct{}),
+				rules:     make(map[role][]rbac.PolicyRule),
+				wildcards: make(map[role]bool),
+				IOStreams: streams,
+			}
+			wc.filterRoles(roles)
+			wc.filterClusterRoles(clusterRoles)
+
+			var matchedRoleBindings []rbac.RoleBinding
+			for _, rb := range roleBindings {
+				if wc.r.match(&rb.RoleRef, rb.Namespace) {
+					matchedRoleBindings = append(matchedRoleBindings, rb)
+				}
+			}
+			var matchedClusterRoleBindings []rbac.ClusterRoleBinding
+			for _, crb := range clusterRoleBindings {
+				if wc.r.match(&crb.RoleRef, "") {
+					matchedClusterRoleBindings = append(matchedClusterRoleBindings, crb)
+				}
+			}
+
+			// when
+			wc.output(matchedRoleBindings, matchedClusterRoleBindings)
+
+			// then
+			assert.Equal(t, tt.output, out.String())
+		})
+	}
+}
+
+func TestWhoCan_outputByAPIGroup_NoSubjects(t *testing.T) {
+	// given
+	streams, _, out, _ := clioptions.NewTestIOStreams()
+	wc := whoCan{
+		verb:      "get",
+		resource:  "*",
+		groupBy:   groupByAPIGroup,
+		IOStreams: streams,
+	}
+
+	// when
+	wc.output(nil, nil)
+
+	// then
+	assert.Equal(t, "No subjects found with permissions to get *\n", out.String())
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+)
+
+// htmlReport is a self-contained HTML page which shows a Result in sortable tables, so that it can be shared with
+// people who do not use kubectl. Clicking the header of a column sorts the table by that column.
+var htmlReport = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>who-can {{.Action}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.8em; text-align: left; }
+th { background: #f0f0f0; cursor: pointer; }
+.warning { color: #a60; }
+</style>
+</head>
+<body>
+<h1>Who can {{.Action}}</h1>
+<dl>
+{{- with .Result.Query.Namespace}}
+<dt>Namespace</dt><dd>{{.}}</dd>
+{{- end}}
+{{- with .Result.Metadata}}
+<dt>Generated</dt><dd>{{.Timestamp}}</dd>
+{{- with .Context}}
+<dt>Context</dt><dd>{{.}}</dd>
+{{- end}}
+{{- with .Server}}
+<dt>Server</dt><dd>{{.}}</dd>
+{{- end}}
+{{- end}}
+</dl>
+{{- with .Result.Warnings}}
+<h2 class="warning">Warnings</h2>
+<ul class="warning">
+{{- range .}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- range .Sections}}
+<h2>{{.Title}}</h2>
+{{- if .Rows}}
+<table class="sortable">
+<thead><tr><th>Binding</th><th>Namespace</th><th>Role</th><th>Subject</th><th>Type</th><th>SA Namespace</th><th>Wildcard</th></tr></thead>
+<tbody>
+{{- range .Rows}}
+<tr><td>{{.Binding.Name}}</td><td>{{.Binding.Namespace}}</td><td>{{.Binding.RoleRef.Kind}}/{{.Binding.RoleRef.Name}}</td><td>{{.Subject.Name}}</td><td>{{.Subject.Kind}}</td><td>{{.Subject.Namespace}}</td><td>{{.Binding.Wildcard}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- else}}
+<p>No subjects found.</p>
+{{- end}}
+{{- end}}
+<script>
+document.querySelectorAll("table.sortable th").forEach(function (th, column) {
+  var ascending = true;
+  th.addEventListener("click", function () {
+    var tbody = th.closest("table").querySelector("tbody");
+    var rows = Array.prototype.slice.call(tbody.rows);
+    rows.sort(function (a, b) {
+      var x = a.cells[column].textContent, y = b.cells[column].textContent;
+      return ascending ? x.localeCompare(y) : y.localeCompare(x);
+    });
+    ascending = !ascending;
+    rows.forEach(function (row) { tbody.appendChild(row); });
+  });
+});
+</script>
+</body>
+</html>
+`))
+
+// htmlRow is a subject of a binding, which is shown as a row of the report.
+type htmlRow struct {
+	Binding Binding
+	Subject Subject
+}
+
+// htmlSection is a table of the report.
+type htmlSection struct {
+	Title string
+	Rows  []htmlRow
+}
+
+// printHTML writes the Result as a self-contained HTML report with the query, its metadata and the warnings
+// in the header, followed by the RoleBinding and ClusterRoleBinding grants in sortable tables.
+func (w *whoCan) printHTML(out io.Writer, result Result) error {
+	section := func(title string, bindings []Binding) htmlSection {
+		s := htmlSection{Title: title}
+		for _, b := range bindings {
+			for _, subject := range b.Subjects {
+				s.Rows = append(s.Rows, htmlRow{Binding: b, Subject: subject})
+			}
+		}
+		return s
+	}
+
+	err := htmlReport.Execute(out, struct {
+		Action   string
+		Result   Result
+		Sections []htmlSection
+	}{
+		Action: w.prettyPrintAction(),
+		Result: result,
+		Sections: []htmlSection{
+			section("Granted through RoleBindings", result.RoleBindings),
+			section("Granted through ClusterRoleBindings", result.ClusterRoleBindings),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("rendering HTML report: %v", err)
+	}
+	return nil
+}
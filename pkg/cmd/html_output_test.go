@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"testing"
+)
+
+func TestWhoCan_printHTML(t *testing.T) {
+	// given
+	roleBindings := []rbac.RoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Alice-can-view-pods", Namespace: "default"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "view-pods"},
+			Subjects:   []rbac.Subject{{Name: "Alice", Kind: "User"}},
+		},
+	}
+	clusterRoleBindings := []rbac.ClusterRoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "ops-<admins>"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "admin"},
+			Subjects:   []rbac.Subject{{Name: "ops", Kind: "Group"}, {Name: "robot", Kind: "ServiceAccount", Namespace: "ci"}},
+		},
+	}
+
+	streams, _, out, _ := clioptions.NewTestIOStreams()
+	wc := whoCan{
+		verb:               "delete",
+		resource:           "pods",
+		namespacedResource: true,
+		namespace:          "default",
+		outputFormat:       outputHTML,
+		IOStreams:          streams,
+	}
+	result := wc.newResult(roleBindings, clusterRoleBindings, nil)
+	result.Metadata = &Metadata{Timestamp: "2019-06-12T12:30:00Z", Context: "kind-kind", Server: "https://127.0.0.1:6443"}
+	result.Warnings = []string{"The user is not allowed to list namespaces"}
+
+	// when
+	err := wc.printResult(result)
+
+	// then
+	require.NoError(t, err)
+	html := out.String()
+	assert.Contains(t, html, "<title>who-can delete pods</title>")
+	assert.Contains(t, html, "<dt>Namespace</dt><dd>default</dd>")
+	assert.Contains(t, html, "<dt>Generated</dt><dd>2019-06-12T12:30:00Z</dd>")
+	assert.Contains(t, html, "<dt>Context</dt><dd>kind-kind</dd>")
+	assert.Contains(t, html, "<dt>Server</dt><dd>https://127.0.0.1:6443</dd>")
+	assert.Contains(t, html, "<li>The user is not allowed to list namespaces</li>")
+	assert.Contains(t, html, "<tr><td>Alice-can-view-pods</td><td>default</td><td>Role/view-pods</td><td>Alice</td><td>User</td><td></td><td>false</td></tr>")
+	assert.Contains(t, html, "<tr><td>ops-&lt;admins&gt;</td><td></td><td>ClusterRole/admin</td><td>ops</td><td>Group</td><td></td><td>false</td></tr>")
+	assert.Contains(t, html, "<tr><td>ops-&lt;admins&gt;</td><td></td><td>ClusterRole/admin</td><td>robot</td><td>ServiceAccount</td><td>ci</td><td>false</td></tr>")
+	assert.Contains(t, html, `<table class="sortable">`)
+	assert.NotContains(t, html, "ops-<admins>")
+}
+
+func TestWhoCan_printHTML_NoSubjects(t *testing.T) {
+	// given
+	streams, _, out, _ := clioptions.NewTestIOStreams()
+	wc := whoCan{
+		verb:         "get",
+		resource:     "secrets",
+		outputFormat: outputHTML,
+		IOStreams:    streams,
+	}
+
+	// when
+	err := wc.printResult(wc.newResult(nil, nil, nil))
+
+	// then
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "<h2>Granted through RoleBindings</h2>\n<p>No subjects found.</p>")
+	assert.NotContains(t, out.String(), "Warnings")
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	rbac "k8s.io/api/rbac/v1"
+)
+
+// jsonlRecord is the JSON object written per subject of the matched bindings by the JSON Lines output.
+type jsonlRecord struct {
+	Subject     Subject `json:"subject"`
+	BindingKind string  `json:"bindingKind"`
+	Binding     string  `json:"binding"`
+	Namespace   string  `json:"namespace,omitempty"`
+	RoleRef     RoleRef `json:"roleRef"`
+	Wildcard    bool    `json:"wildcard"`
+}
+
+// printJSONLines writes one JSON object per subject of the Result, each on its own line, so that line-oriented tools
+// such as `jq -c` or grep can process the subjects. It is used when the output cannot be streamed, see streamsJSONLines.
+func (w *whoCan) printJSONLines(out io.Writer, result Result) error {
+	enc := json.NewEncoder(out)
+	var err error
+	result.forEachBinding(func(bindingKind string, b Binding) {
+		if err == nil {
+			err = writeJSONLines(enc, bindingKind, b)
+		}
+	})
+	return err
+}
+
+// writeJSONLines writes a JSON object per subject of the given binding.
+func writeJSONLines(enc *json.Encoder, bindingKind string, b Binding) error {
+	for _, s := range b.Subjects {
+		err := enc.Encode(jsonlRecord{Subject: s, BindingKind: bindingKind, Binding: b.Name,
+			Namespace: b.Namespace, RoleRef: b.RoleRef, Wildcard: b.Wildcard})
+		if err != nil {
+			return fmt.Errorf("writing JSON line: %v", err)
+		}
+	}
+	return nil
+}
+
+// streamsJSONLines tells whether the jsonl output is written while the bindings are matched, rather than once the
+// Result is complete. The output is streamed unless the subjects are sorted, truncated, aggregated over several
+// verbs or resource names, or compared with the current user, or unless the output is written to a file.
+func (w *whoCan) streamsJSONLines() bool {
+	return w.outputFormat == outputJSONLines && w.outputFile == "" && w.sortBy == "" && w.maxResults == 0 &&
+		len(w.verbs) <= 1 && len(w.resourceNames) == 0 && !w.excludeSelfFlag
+}
+
+// jsonLinesStream writes the JSON objects of the subjects of each binding as soon as the binding is matched,
+// so that streaming consumers can process the subjects of very large clusters before all bindings are matched.
+// A nil stream writes nothing.
+type jsonLinesStream struct {
+	w   *whoCan
+	enc *json.Encoder
+}
+
+// newJSONLinesStream writes the warnings, which are known before the bindings are matched, to the standard error,
+// and returns the stream of the JSON lines to the standard output.
+func (w *whoCan) newJSONLinesStream(warnings []string) *jsonLinesStream {
+	for _, warning := range warnings {
+		_, _ = fmt.Fprintf(w.ErrOut, "Warning: %s\n", warning)
+	}
+	return &jsonLinesStream{w: w, enc: json.NewEncoder(w.Out)}
+}
+
+// writeRoleBinding writes the subjects of the matched RoleBinding which are kept by the subject filters.
+func (s *jsonLinesStream) writeRoleBinding(rb rbac.RoleBinding) error {
+	if s == nil {
+		return nil
+	}
+	roleBindings, _ := s.w.filterBindings([]rbac.RoleBinding{rb}, nil)
+	for _, rb := range roleBindings {
+		if err := writeJSONLines(s.enc, "RoleBinding", s.w.newBinding(rb.Name, rb.Namespace, rb.RoleRef, rb.Subjects)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeClusterRoleBinding writes the subjects of the matched ClusterRoleBinding which are kept by the subject filters.
+func (s *jsonLinesStream) writeClusterRoleBinding(crb rbac.ClusterRoleBinding) error {
+	if s == nil {
+		return nil
+	}
+	_, clusterRoleBindings := s.w.filterBindings(nil, []rbac.ClusterRoleBinding{crb})
+	for _, crb := range clusterRoleBindings {
+		if err := writeJSONLines(s.enc, "ClusterRoleBinding", s.w.newBinding(crb.Name, "", crb.RoleRef, crb.Subjects)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// close writes the warnings about the bindings of the masters group, which are known once all bindings are matched.
+func (s *jsonLinesStream) close(masters []string) {
+	for _, binding := range masters {
+		_, _ = fmt.Fprintf(s.w.ErrOut, "Warning: %s grants access to the %s group, which bypasses RBAC authorization\n", binding, mastersGroup)
+	}
+	s.w.resultPrinted = true
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	clientTesting "k8s.io/client-go/testing"
+	"testing"
+)
+
+func TestWhoCan_printJSONLines(t *testing.T) {
+	// given
+	roleBindings := []rbac.RoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "view-pods", Namespace: "default"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "view-pods"},
+			Subjects: []rbac.Subject{
+				{Name: "alice", Kind: "User"},
+				{Name: "bar", Kind: "ServiceAccount", Namespace: "foo"},
+			},
+		},
+	}
+	clusterRoleBindings := []rbac.ClusterRoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "admins"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+			Subjects: []rbac.Subject{
+				{Name: "devs", Kind: "Group"},
+			},
+		},
+	}
+
+	streams, _, out, _ := clioptions.NewTestIOStreams()
+	wc := whoCan{
+		verb:         "get",
+		resource:     "pods",
+		outputFormat: outputJSONLines,
+		IOStreams:    streams,
+	}
+
+	// when
+	err := wc.printResult(wc.newResult(roleBindings, clusterRoleBindings, []string{"list roles"}))
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, `{"subject":{"kind":"User","name":"alice"},"bindingKind":"RoleBinding","binding":"view-pods","namespace":"default","roleRef":{"kind":"Role","name":"view-pods"},"wildcard":false}
+{"subject":{"kind":"ServiceAccount","name":"bar","namespace":"foo"},"bindingKind":"RoleBinding","binding":"view-pods","namespace":"default","roleRef":{"kind":"Role","name":"view-pods"},"wildcard":false}
+{"subject":{"kind":"Group","name":"devs"},"bindingKind":"ClusterRoleBinding","binding":"admins","roleRef":{"kind":"ClusterRole","name":"cluster-admin"},"wildcard":false}
+`, out.String())
+}
+
+func TestWhoCan_Check_StreamsJSONLines(t *testing.T) {
+	aliceLine := `{"subject":{"kind":"User","name":"alice"},"bindingKind":"RoleBinding","binding":"alice-can-view-pods","namespace":"foo","roleRef":{"kind":"Role","name":"view-pods"},"wildcard":false}
+`
+	devsLine := `{"subject":{"kind":"Group","name":"devs"},"bindingKind":"ClusterRoleBinding","binding":"devs-can-view-pods","roleRef":{"kind":"ClusterRole","name":"view-pods"},"wildcard":false}
+`
+
+	data := []struct {
+		scenario string
+		sortBy   sortKey
+
+		expectedOutBeforeClusterRoleBindings string
+	}{
+		{
+			scenario:                             "Should write subjects of RoleBindings before ClusterRoleBindings are matched",
+			expectedOutBeforeClusterRoleBindings: aliceLine,
+		},
+		{
+			scenario:                             "Should write subjects once all bindings are matched when sorted",
+			sortBy:                               sortBySubject,
+			expectedOutBeforeClusterRoleBindings: "",
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			viewPods := []rbac.PolicyRule{{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"pods"}}}
+			client := fake.NewSimpleClientset(
+				&rbac.Role{ObjectMeta: meta.ObjectMeta{Name: "view-pods", Namespace: "foo"}, Rules: viewPods},
+				&rbac.RoleBinding{
+					ObjectMeta: meta.ObjectMeta{Name: "alice-can-view-pods", Namespace: "foo"},
+					RoleRef:    rbac.RoleRef{Kind: "Role", Name: "view-pods"},
+					Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "alice"}},
+				},
+				&rbac.ClusterRole{ObjectMeta: meta.ObjectMeta{Name: "view-pods"}, Rules: viewPods},
+				&rbac.ClusterRoleBinding{
+					ObjectMeta: meta.ObjectMeta{Name: "devs-can-view-pods"},
+					RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "view-pods"},
+					Subjects:   []rbac.Subject{{Kind: rbac.GroupKind, Name: "devs"}},
+				},
+			)
+			accessChecker := new(accessCheckerMock)
+			for _, resource := range namespacedAPIAccess {
+				accessChecker.On("IsAllowedTo", "list", rbac.GroupName, resource, "foo").Return(true, nil)
+			}
+
+			streams, _, out, _ := clioptions.NewTestIOStreams()
+			var outBeforeClusterRoleBindings string
+			client.PrependReactor("list", "clusterrolebindings", func(action clientTesting.Action) (bool, runtime.Object, error) {
+				outBeforeClusterRoleBindings = out.String()
+				return false, nil, nil
+			})
+
+			wc := whoCan{
+				verb:               "get",
+				resource:           "pods",
+				namespacedResource: true,
+				namespace:          "foo",
+				outputFormat:       outputJSONLines,
+				outputVersion:      outputVersionV1,
+				sortBy:             tt.sortBy,
+				clientRBAC:         client.RbacV1(),
+				accessChecker:      accessChecker,
+				IOStreams:          streams,
+			}
+
+			// when
+			err := wc.Check()
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedOutBeforeClusterRoleBindings, outBeforeClusterRoleBindings)
+			assert.Equal(t, aliceLine+devsLine, out.String())
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// junitTestSuites is the subset of the JUnit XML format which is understood by CI systems.
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// printJUnit writes the Result as JUnit XML, which asserts that no subject is granted the queried action. Each
+// subject of the matched bindings is a failed test case, so that a pipeline surfaces the grants left after excluding
+// the expected subjects, such as with --exclude-sa-namespace. A query which finds no subjects is a single passed
+// test case.
+func (w *whoCan) printJUnit(out io.Writer, result Result) error {
+	action := w.prettyPrintAction()
+	suite := junitTestSuite{Name: "who-can " + action}
+	result.forEachBinding(func(bindingKind string, b Binding) {
+		scope := "in namespace " + b.Namespace
+		if b.Namespace == "" {
+			scope = clusterWide
+		}
+		for _, s := range b.Subjects {
+			message := fmt.Sprintf("%s %s can %s %s", s.Kind, s.Name, action, scope)
+			suite.Cases = append(suite.Cases, junitTestCase{
+				Name:      subjectName(s) + " through " + bindingKind + "/" + b.Name,
+				ClassName: bindingKind,
+				Failure: &junitFailure{
+					Message: message,
+					Type:    "UnexpectedGrant",
+					Text:    fmt.Sprintf("%s through %s %s, which grants %s %s", message, bindingKind, b.Name, b.RoleRef.Kind, b.RoleRef.Name),
+				},
+			})
+		}
+	})
+	suite.Failures = len(suite.Cases)
+	if len(suite.Cases) == 0 {
+		suite.Cases = append(suite.Cases, junitTestCase{Name: "no subject can " + action, ClassName: "who-can"})
+	}
+	suite.Tests = len(suite.Cases)
+
+	data, err := xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling JUnit XML: %v", err)
+	}
+	_, err = fmt.Fprintf(out, "%s%s\n", xml.Header, data)
+	return err
+}
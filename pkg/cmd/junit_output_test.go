@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"testing"
+)
+
+func TestWhoCan_printJUnit(t *testing.T) {
+	data := []struct {
+		scenario            string
+		roleBindings        []rbac.RoleBinding
+		clusterRoleBindings []rbac.ClusterRoleBinding
+		expected            string
+	}{
+		{
+			scenario: "Should fail a test case per subject",
+			roleBindings: []rbac.RoleBinding{
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "delete-nodes", Namespace: "default"},
+					RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "node-admin"},
+					Subjects: []rbac.Subject{
+						{Name: "bar", Kind: "ServiceAccount", Namespace: "foo"},
+					},
+				},
+			},
+			clusterRoleBindings: []rbac.ClusterRoleBinding{
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "admins"},
+					RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+					Subjects: []rbac.Subject{
+						{Name: "alice", Kind: "User"},
+					},
+				},
+			},
+			expected: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="who-can delete nodes" tests="2" failures="2">
+    <testcase name="serviceaccount/foo/bar through RoleBinding/delete-nodes" classname="RoleBinding">
+      <failure message="ServiceAccount bar can delete nodes in namespace default" type="UnexpectedGrant">ServiceAccount bar can delete nodes in namespace default through RoleBinding delete-nodes, which grants ClusterRole node-admin</failure>
+    </testcase>
+    <testcase name="user/alice through ClusterRoleBinding/admins" classname="ClusterRoleBinding">
+      <failure message="User alice can delete nodes cluster-wide" type="UnexpectedGrant">User alice can delete nodes cluster-wide through ClusterRoleBinding admins, which grants ClusterRole cluster-admin</failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`,
+		},
+		{
+			scenario: "Should pass a single test case when no subjects are found",
+			expected: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="who-can delete nodes" tests="1" failures="0">
+    <testcase name="no subject can delete nodes" classname="who-can"></testcase>
+  </testsuite>
+</testsuites>
+`,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			streams, _, out, _ := clioptions.NewTestIOStreams()
+			wc := whoCan{
+				verb:         "delete",
+				resource:     "nodes",
+				outputFormat: outputJUnit,
+				IOStreams:    streams,
+			}
+
+			// when
+			err := wc.printResult(wc.newResult(tt.roleBindings, tt.clusterRoleBindings, nil))
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, out.String())
+		})
+	}
+}
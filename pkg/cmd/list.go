@@ -1,22 +1,83 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"github.com/spf13/cobra"
+	"io"
 	core "k8s.io/api/core/v1"
 	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+	diskcached "k8s.io/client-go/discovery/cached/disk"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	clientcore "k8s.io/client-go/kubernetes/typed/core/v1"
 	clientrbac "k8s.io/client-go/kubernetes/typed/rbac/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/transport"
+	"k8s.io/client-go/util/homedir"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"sync"
 	"text/tabwriter"
+	"time"
 
 	"github.com/golang/glog"
 	rbac "k8s.io/api/rbac/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	outputJSON = "json"
+	// outputYAML prints the same document as outputJSON in YAML.
+	outputYAML = "yaml"
+	// outputRawJSON prints the matched RoleBindings and ClusterRoleBindings as a List of API objects.
+	outputRawJSON = "raw-json"
+	// outputRego prints the matched grants as a Rego module with facts to be evaluated by OPA.
+	outputRego = "rego"
+	// outputLogfmt prints a logfmt line of key=value pairs per subject of the matched bindings.
+	outputLogfmt = "logfmt"
+	// outputConfigMap prints the same document as outputJSON wrapped in a ConfigMap manifest.
+	outputConfigMap = "configmap"
+	// outputHTML prints a self-contained HTML report with sortable tables.
+	outputHTML = "html"
+	// outputReviewCSV prints a CSV row per subject of the matched bindings for spreadsheet-based access reviews.
+	outputReviewCSV = "review-csv"
+	// outputCSV prints a CSV row per subject of the matched bindings with the columns of the table.
+	outputCSV = "csv"
+	// outputMarkdown prints the subjects of the matched bindings as Markdown tables.
+	outputMarkdown = "markdown"
+	// outputDOT prints the grants as a Graphviz graph of subjects, bindings, roles and rules.
+	outputDOT = "dot"
+	// outputMermaid prints the grants as a Mermaid flowchart of subjects, bindings and roles.
+	outputMermaid = "mermaid"
+	// outputSARIF prints a SARIF log with a result per subject of the matched bindings for code scanning dashboards.
+	outputSARIF = "sarif"
+	// outputJUnit prints JUnit XML with a failed test case per subject of the matched bindings.
+	outputJUnit = "junit"
+	// outputJSONLines prints a JSON object per subject of the matched bindings on its own line.
+	outputJSONLines = "jsonl"
+	// outputName prints the canonical identifier of each subject of the matched bindings, such as user/alice.
+	outputName = "name"
+	// outputWide prints the table with the role granted by each binding and the API group of the queried resource.
+	outputWide = "wide"
+
+	// clusterRoleGrantsByBindingKind shows RoleBindings referencing ClusterRoles together with other RoleBindings.
+	clusterRoleGrantsByBindingKind = "binding-kind"
+	// clusterRoleGrantsByRoleKind shows RoleBindings referencing ClusterRoles together with ClusterRoleBindings.
+	clusterRoleGrantsByRoleKind = "role-kind"
+
+	// maxConcurrentAccessChecks limits the number of access reviews that are run in parallel.
+	maxConcurrentAccessChecks = 10
 )
 
 const (
@@ -24,8 +85,15 @@ const (
 	whoCanLong  = `Shows which users, groups and service accounts can perform a given verb on a given resource type.
 
 VERB is a logical Kubernetes API verb like 'get', 'list', 'watch', 'delete', etc.
+VERB may also be a comma-separated list of verbs, such as 'get,list,watch', in which case the subjects granted any of the
+verbs are listed, or only those granted all of them, possibly through different bindings, with --require-all-verbs.
 TYPE is a Kubernetes resource. Shortcuts, such as 'pod' or 'po' will be resolved. NAME is the name of a particular Kubernetes resource.
-NONRESOURCEURL is a partial URL that starts with "/".`
+TYPE may be qualified with its API group, such as 'deployments.apps', or fully qualified with a served version, such as 'deployments.v1.apps'.
+TYPE/NAME is split at the first slash only, hence NAME may contain slashes. Sub-resources are given by the --subresource flag.
+TYPE[/NAME] may also be given as a path of the Kubernetes API, such as 'apis/apps/v1/deployments' or 'api/v1/pods/my-pod'.
+NONRESOURCEURL is a partial URL that starts with "/".
+
+The exit code is 0 if any subject can perform the action, 2 if none can, and 1 on errors.`
 	whoCanExample = `  # List who can get pods in any namespace
   kubectl who-can get pods --all-namespaces
 
@@ -47,26 +115,244 @@ NONRESOURCEURL is a partial URL that starts with "/".`
   # List who can read pod logs
   kubectl who-can get pods --subresource=log
 
+  # List who can get every secret listed in the file names.txt in namespace "foo"
+  kubectl who-can get secrets --resource-name-file names.txt --resource-name-match all -n foo
+
+  # List who can get deployments given as a path of the Kubernetes API
+  kubectl who-can get apis/apps/v1/deployments
+
+  # List who can bind the ClusterRole named "admin"
+  kubectl who-can bind clusterroles/admin
+
   # List who can access the URL /logs/
-  kubectl who-can get /logs`
+  kubectl who-can get /logs
+
+  # List who can get, list and watch secrets in namespace "foo", through the same or different bindings
+  kubectl who-can get,list,watch secrets -n foo --require-all-verbs
+
+  # List who can get pods as a table and write the JSON output to the file "who-can.json"
+  kubectl who-can get pods -o json --output-file who-can.json
+
+  # List who can get secrets as a v1 document, whose apiVersion whocan.dev/v1 tells that its schema only changes compatibly
+  kubectl who-can get secrets -o yaml --output-version v1
+
+  # List the names of the subjects who can get secrets, extracted with a JSONPath template
+  kubectl who-can get secrets -o jsonpath='{.roleBindings[*].subjects[*].name}'
+
+  # Render who can delete deployments in namespace "prod" with a Go template, such as for the body of a ticket
+  kubectl who-can delete deployments.apps -n prod -o go-template='{{range .roleBindings}}{{.name}}: {{range .subjects}}{{.kind}}/{{.name}} {{end}}{{"\n"}}{{end}}'
+
+  # List who can get secrets in all namespaces ordered by the namespace of the bindings
+  kubectl who-can get secrets -A --sort-by namespace
+
+  # Write a compliance report of who can get secrets in all namespaces with a custom Go template
+  kubectl who-can get secrets -A --report-template report.tmpl --output-file report.txt
+
+  # List who can get deployments with the role granted by each binding and the API group of deployments
+  kubectl who-can get deployments -o wide
+
+  # List who can get secrets with the columns selected by JSONPath expressions
+  kubectl who-can get secrets -o custom-columns=SUBJECT:.subject.name,KIND:.subject.kind,ROLE:.binding.roleRef.name
+
+  # Store who can get secrets in namespace "foo" as a ConfigMap in the cluster
+  kubectl who-can get secrets -n foo -o configmap | kubectl apply -n foo -f -
+
+  # Write who can delete pods as an HTML report with sortable tables to the file "who-can.html"
+  kubectl who-can delete pods -o html --output-file who-can.html
+
+  # Write who can list pods in all namespaces as a CSV to the file "who-can.csv"
+  kubectl who-can list pods -A -o csv --output-file who-can.csv
+
+  # List who can delete secrets in namespace "prod" as Markdown tables, such as for a pull request
+  kubectl who-can delete secrets -n prod -o markdown
+
+  # Render how who can get secrets in namespace "foo" is granted as an SVG graph with Graphviz
+  kubectl who-can get secrets -n foo -o dot | dot -Tsvg > who-can.svg
+
+  # Embed who can delete pods as a Mermaid flowchart in a Markdown document
+  kubectl who-can delete pods -o mermaid
+
+  # Write who can delete secrets as a SARIF log to be uploaded to a code scanning dashboard
+  kubectl who-can delete secrets -A -o sarif --output-file who-can.sarif
+
+  # Report any subject other than the service accounts of "kube-system" who can delete nodes as a failed CI test
+  kubectl who-can delete nodes --exclude-sa-namespace kube-system -o junit --output-file who-can.xml
+
+  # Stream who can list secrets in all namespaces as a JSON object per subject to jq
+  kubectl who-can list secrets -A -o jsonl | jq -c 'select(.subject.kind == "ServiceAccount")'
+
+  # Write who can get secrets as a CSV for a quarterly access review to the file "review.csv"
+  kubectl who-can get secrets -A -o review-csv --output-file review.csv
+
+  # List who can delete namespaces as one subject per line, such as user/jane or serviceaccount/foo/bar
+  kubectl who-can delete namespaces -o name
+
+  # Upload who can get secrets as JSON to a bucket
+  kubectl who-can get secrets -o json --post-command 'aws s3 cp - s3://bucket/who-can.json'
+
+  # Check whether the service account "ci-deployer" of namespace "foo" can create deployments in namespace "bar"
+  kubectl who-can create deployments -n bar --subject foo:ci-deployer --subject-kind ServiceAccount
+
+  # List who can list secrets, guaranteeing that no request modifies the cluster
+  kubectl who-can list secrets --read-only
+
+  # List who can list secrets within one minute, giving up on each request to the API server after 5 seconds
+  kubectl who-can list secrets --timeout 1m --request-timeout 5s
+
+  # List who else, apart from the current user, can delete secrets in namespace "foo"
+  kubectl who-can delete secrets -n foo --exclude-self
+
+  # List who can delete any of the resources in the "all" category in namespace "foo"
+  kubectl who-can delete --category all -n foo
+
+  # List who can get pods in the namespaces labeled with team=payments
+  kubectl who-can get pods --all-namespaces --namespace-selector team=payments
+
+  # List who can delete secrets in namespace "foo", sorted by an experimental blast radius score
+  kubectl who-can delete secrets -n foo --score
+
+  # Exit with code 2 if nobody can delete secrets in namespace "foo", without printing the table
+  kubectl who-can delete secrets -n foo --quiet
+
+  # List what the default service account of namespace "foo" can do
+  kubectl who-can --default-sa foo
+
+  # Report who can escalate their privileges in namespace "foo", such as by running pods as any service account
+  kubectl who-can --escalation -n foo
+
+  # List the cluster admins and the admins of all namespaces
+  kubectl who-can --who-is-admin --all-namespaces
+
+  # List who can get pods in namespace "foo" and warn if the user "jane" could not see the complete list
+  kubectl who-can get pods -n foo --as jane`
 )
 
+// mastersGroup is the group whose members are granted unrestricted access by the API server regardless of RBAC.
+const mastersGroup = "system:masters"
+
 type role struct {
 	name          string
 	isClusterRole bool
+	// namespace is the namespace of a Role. Roles of the same name in different namespaces are different roles,
+	// which matters when checking all namespaces. It is empty for ClusterRoles.
+	namespace string
 }
 
 type roles map[role]struct{}
 
 type whoCan struct {
-	verb           string
-	resource       string
+	verb string
+	// verbs holds the verbs of a VERB given as a comma-separated list, which are checked one by one.
+	verbs []string
+	// requireAllVerbs keeps only the subjects granted every one of the verbs rather than any of them.
+	requireAllVerbs bool
+	resource        string
+	// apiGroup is the API group of the resolved resource.
+	apiGroup string
+	// apiVersion is the version of the API group in which the resource was resolved.
+	apiVersion string
+	// namespacedResource tells whether the objects of the resolved resource are namespaced.
+	namespacedResource bool
+	// resourceGroup is the API group given by the --apigroup flag to qualify an ambiguous resource name.
+	resourceGroup  string
 	nonResourceURL string
 	subResource    string
 	resourceName   string
 
+	// resourceNames holds the resource names read from the --resource-name-file, or matched by the --selector.
+	resourceNames     []string
+	resourceNameFile  string
+	resourceNameMatch string
+	// selector is the label selector of the objects whose names are checked, see lookupObjects.
+	selector string
+	// verifyResourceName fails the check if the object named by TYPE/NAME does not exist.
+	verifyResourceName bool
+
 	namespace     string
 	allNamespaces bool
+	// namespaceSelector is a label selector which narrows the namespaces checked in all namespaces.
+	namespaceSelector string
+	// selectedNamespaces holds the namespaces matched by the namespaceSelector, or nil if all namespaces are checked.
+	selectedNamespaces map[string]struct{}
+
+	outputFormat string
+	// outputFile is the file to which the structured output is written, in which case the table is printed as well.
+	outputFile    string
+	outputVersion string
+	sortBy        sortKey
+	showRisk      bool
+	showWildcard  bool
+	flagMasters   bool
+
+	// wide is set by the wide output, which is not a structured format but adds columns to the table.
+	wide bool
+	// template and allowMissingTemplateKeys are used by the template output formats, such as jsonpath.
+	template                 string
+	allowMissingTemplateKeys bool
+	// reportTemplate is a Go template file which renders the whole result, as a shorthand of the go-template-file output.
+	reportTemplate string
+
+	// interactive is set when errors are shown on a terminal, in which case they include suggestions.
+	interactive bool
+	// keepManagedFields keeps the server-managed metadata of the objects printed with the raw-json output.
+	keepManagedFields bool
+	// maxResults caps the number of printed subject rows. Zero means no cap.
+	maxResults int
+	// clusterRoleGrantsSection selects the output section of RoleBindings which reference ClusterRoles.
+	clusterRoleGrantsSection string
+	// excludeSANamespaces are the namespaces whose ServiceAccounts are omitted from the results.
+	excludeSANamespaces []string
+	// subject and subjectKind narrow the results to the matching subjects.
+	subject     string
+	subjectKind string
+	// normalizeSubjects rewrites equivalent representations of subjects, such as a ServiceAccount given as a User, into one.
+	normalizeSubjects bool
+	// columnsSpec is a preset or a comma-separated list of the columns to print, which are expanded into selectedColumns.
+	columnsSpec       string
+	selectedColumns   []column
+	listColumnPresets bool
+	// groupBy selects an alternative rendering of the text output which groups the grants, such as by API group.
+	groupBy string
+	// noHeaders omits the header rows of the tables, so that scripts can count or grep the rows of the subjects.
+	noHeaders bool
+	// colorMode is the --color mode, which is resolved into color when the command is run.
+	colorMode string
+	// color is set when the rows of the tables are colored by their risk.
+	color bool
+	// escalation runs the privilege escalation report instead of checking an action.
+	escalation bool
+	// category checks the verb on each resource of this category, such as all, instead of a single resource.
+	category string
+	// whoIsAdmin lists the subjects bound to admin roles instead of checking an action.
+	whoIsAdmin bool
+	// defaultSA is the namespace whose default service account is looked up instead of checking an action.
+	defaultSA string
+	// showUnresolved lists the bindings of the default service account whose roles cannot be resolved.
+	showUnresolved bool
+	// excludeSelfFlag drops the subjects which refer to the current user.
+	excludeSelfFlag bool
+	// score prints the subjects with their experimental blast radius scores instead of the bindings.
+	score bool
+	// quiet suppresses the table, so that the result is only signalled by the exit code.
+	quiet bool
+	// postCommand is the shell command to which the output is piped, such as to upload it to a bucket.
+	postCommand string
+	// showResolved prints the resolved resource before the results.
+	showResolved bool
+	// resultPrinted is set once the structured result has been printed.
+	resultPrinted bool
+	// jsonLines writes the jsonl output while the bindings are matched, if it is streamed. See streamsJSONLines.
+	jsonLines *jsonLinesStream
+	// clock tells the time at which the structured result is generated. Defaults to the real clock.
+	clock clock.Clock
+
+	showNearMisses   bool
+	showRolesSummary bool
+	// nearMisses holds the resource names to which the roles that grant the queried verb and resource are restricted,
+	// if they do not include the queried resource name.
+	nearMisses                  map[role][]string
+	nearMissRoleBindings        []rbac.RoleBinding
+	nearMissClusterRoleBindings []rbac.ClusterRoleBinding
 
 	configFlags     *clioptions.ConfigFlags
 	clientConfig    clientcmd.ClientConfig
@@ -76,8 +362,22 @@ type whoCan struct {
 	namespaceValidator NamespaceValidator
 	resourceResolver   ResourceResolver
 	accessChecker      AccessChecker
+	selfIdentifier     SelfIdentifier
+	objectLookup       ObjectLookup
+	// timeouts bounds the requests of the clients by the --request-timeout and the deadline of the --timeout.
+	timeouts *requestTimeouts
+	// timeout is the time given to the whole operation, or zero for no deadline.
+	timeout time.Duration
 
 	r roles
+	// rules holds the PolicyRules of the matched Roles and ClusterRoles.
+	rules map[role][]rbac.PolicyRule
+	// wildcards tells whether the matched Roles and ClusterRoles grant the queried action only through wildcards.
+	wildcards map[role]bool
+	// aggregatedRoles holds the matched ClusterRoles with an aggregationRule, whose rules are composed from other ClusterRoles.
+	aggregatedRoles roles
+	// apiGroups holds the API groups of the resources on which the matched Roles and ClusterRoles grant the queried action.
+	apiGroups map[role]map[string]struct{}
 
 	clioptions.IOStreams
 }
@@ -98,10 +398,41 @@ func NewWhoCanOptions(configFlags *clioptions.ConfigFlags,
 		namespaceValidator: namespaceValidator,
 		resourceResolver:   resourceResolver,
 		accessChecker:      accessChecker,
+		clock:              clock.RealClock{},
 		IOStreams:          streams,
 	}
 }
 
+// newDiscoveryClient creates the discovery client for the given config like ConfigFlags.ToDiscoveryClient does, which
+// cannot be used because it ignores the transport wrappers of the config. The API resources discovered by previous
+// runs of kubectl are reused from the discovery cache directory for 10 minutes.
+func newDiscoveryClient(configFlags *clioptions.ConfigFlags, config *rest.Config) (discovery.CachedDiscoveryInterface, error) {
+	discoveryConfig := rest.CopyConfig(config)
+	// The more groups you have, the more discovery requests you need to make.
+	discoveryConfig.Burst = 100
+
+	httpCacheDir := filepath.Join(homedir.HomeDir(), ".kube", "http-cache")
+	if configFlags.CacheDir != nil && *configFlags.CacheDir != "" {
+		httpCacheDir = *configFlags.CacheDir
+	}
+	discoveryCacheDir := filepath.Join(discoveryCacheParentDir, discoveryCacheHostDir(discoveryConfig.Host))
+	return diskcached.NewCachedDiscoveryClientForConfig(discoveryConfig, discoveryCacheDir, httpCacheDir, 10*time.Minute)
+}
+
+var (
+	// discoveryCacheParentDir is the parent directory of the discovery cache shared with kubectl.
+	discoveryCacheParentDir = filepath.Join(homedir.HomeDir(), ".kube", "cache", "discovery")
+	// illegalFileCharacters matches the characters of the host which kubectl replaces in the discovery cache directory.
+	illegalFileCharacters = regexp.MustCompile(`[^(\w/\.)]`)
+)
+
+// discoveryCacheHostDir returns the name of the discovery cache directory of the API server at the given host,
+// which is computed the same way as kubectl computes it.
+func discoveryCacheHostDir(host string) string {
+	schemelessHost := strings.Replace(strings.Replace(host, "https://", "", 1), "http://", "", 1)
+	return illegalFileCharacters.ReplaceAllString(schemelessHost, "_")
+}
+
 func NewCmdWhoCan(streams clioptions.IOStreams) (*cobra.Command, error) {
 	configFlags := clioptions.NewConfigFlags(true)
 
@@ -110,20 +441,36 @@ func NewCmdWhoCan(streams clioptions.IOStreams) (*cobra.Command, error) {
 		return nil, fmt.Errorf("getting config: %v", err)
 	}
 
+	timeouts := newRequestTimeouts(configFlags.Timeout, clock.RealClock{})
+	readOnly := &readOnlyGuard{enabled: new(bool)}
+	clientConfig.WrapTransport = transport.Wrappers(clientConfig.WrapTransport, readOnly.wrap, timeouts.wrap)
+
 	client, err := kubernetes.NewForConfig(clientConfig)
 	if err != nil {
 		return nil, fmt.Errorf("creating client: %v", err)
 	}
 
-	mapper, err := configFlags.ToRESTMapper()
+	// The discovery client and the mapper are created from the wrapped config, so that the --timeout bounds
+	// the discovery requests too.
+	discoveryClient, err := newDiscoveryClient(configFlags, clientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("creating discovery client: %v", err)
+	}
+	mapper := restmapper.NewShortcutExpander(restmapper.NewDeferredDiscoveryRESTMapper(discoveryClient), discoveryClient)
+
+	dynamicClient, err := dynamic.NewForConfig(clientConfig)
 	if err != nil {
-		return nil, fmt.Errorf("getting mapper: %v", err)
+		return nil, fmt.Errorf("creating dynamic client: %v", err)
 	}
 
 	clientNamespace := client.CoreV1().Namespaces()
-	accessChecker := NewAccessChecker(client.AuthorizationV1().SelfSubjectAccessReviews())
+	dump := &accessReviewDump{out: streams.ErrOut}
+	// The clients do not impersonate, because they are created before the --as and --as-group flags are parsed.
+	// Instead the access checker checks whether the impersonated subject would see the complete results.
+	accessChecker := newImpersonatingAccessChecker(client.AuthorizationV1(),
+		&impersonation{user: configFlags.Impersonate, groups: configFlags.ImpersonateGroup}, dump)
 	namespaceValidator := NewNamespaceValidator(clientNamespace)
-	resourceResolver := NewResourceResolver(client.Discovery(), mapper)
+	resourceResolver := NewResourceResolver(discoveryClient, mapper)
 
 	o := NewWhoCanOptions(configFlags,
 		configFlags.ToRawKubeConfigLoader(),
@@ -133,24 +480,22 @@ func NewCmdWhoCan(streams clioptions.IOStreams) (*cobra.Command, error) {
 		resourceResolver,
 		accessChecker,
 		streams)
+	o.objectLookup = NewObjectLookup(dynamicClient)
+	o.interactive = isTerminal(streams.ErrOut)
+	o.timeouts = timeouts
+	o.selfIdentifier = NewSelfIdentifier(client.AuthenticationV1().TokenReviews(), clientConfig,
+		&impersonation{user: configFlags.Impersonate, groups: configFlags.ImpersonateGroup})
 
 	cmd := &cobra.Command{
 		Use:          whoCanUsage,
 		Long:         whoCanLong,
 		Example:      whoCanExample,
 		SilenceUsage: true,
+		Args:         cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := o.Complete(args); err != nil {
-				return err
-			}
-			if err := o.Validate(); err != nil {
-				return err
-			}
-			if err := o.Check(); err != nil {
-				return err
-			}
-
-			return nil
+			return o.handleError(cmd, o.runWithPostCommand(func() error {
+				return o.run(args)
+			}))
 		},
 	}
 
@@ -158,15 +503,200 @@ func NewCmdWhoCan(streams clioptions.IOStreams) (*cobra.Command, error) {
 		"SubResource such as pod/log or deployment/scale")
 	cmd.PersistentFlags().BoolVarP(&o.allNamespaces, "all-namespaces", "A", false,
 		"If true, check the specified action in all namespaces.")
+	cmd.Flags().StringVar(&o.namespaceSelector, "namespace-selector", "",
+		"Label selector, such as team=payments, which narrows the namespaces checked with --all-namespaces. Ignored if a single namespace is checked.")
+	cmd.Flags().StringVar(&o.resourceGroup, "apigroup", "",
+		"API group of the resource, such as apps or "+coreGroup+" for the core API group. Required if the resource name is served by several API groups.")
+	cmd.Flags().StringVar(&o.resourceNameFile, "resource-name-file", "",
+		"File with resource names separated by new lines. Lines starting with # are ignored.")
+	cmd.Flags().StringVarP(&o.selector, "selector", "l", "",
+		"Label selector, such as tier=backend, of the objects of TYPE whose names are checked, as if they were listed in the --resource-name-file. "+
+			"The objects are listed with the dynamic client, hence custom resources are supported.")
+	cmd.Flags().BoolVar(&o.verifyResourceName, "verify-resource-name", false,
+		"If true, fail when the object named by TYPE/NAME does not exist, rather than listing who could access it. "+
+			"The object is looked up with the dynamic client, hence custom resources are supported.")
+	cmd.Flags().StringVar(&o.resourceNameMatch, "resource-name-match", resourceNameMatchAny,
+		"Used with --resource-name-file or --selector. 'any' lists subjects who can access any of the named resources, "+
+			"'all' lists only subjects who can access every named resource.")
+	cmd.Flags().BoolVar(&o.requireAllVerbs, "require-all-verbs", false,
+		"Used with a comma-separated VERB. If true, list only subjects who are granted every verb, "+
+			"otherwise subjects who are granted any of the verbs are listed.")
+	cmd.Flags().StringVarP(&o.outputFormat, "output", "o", "",
+		"Output format. One of: "+strings.Join(allOutputFormats(), "|")+".")
+	cmd.Flags().StringVar(&o.template, "template", "",
+		"Template string or path to template file to use when -o=jsonpath, -o=jsonpath-file, -o=go-template or -o=go-template-file. "+
+			"The template refers to the fields of the json output format.")
+	cmd.Flags().BoolVar(&o.allowMissingTemplateKeys, "allow-missing-template-keys", true,
+		"If true, ignore any errors in templates when a field or map key is missing in the template.")
+	cmd.Flags().StringVar(&o.reportTemplate, "report-template", "",
+		"Path to a Go template file which renders the whole result, such as a compliance report. "+
+			"It is a shorthand for -o go-template-file with --template, hence the template refers to the fields of the json output format, "+
+			"such as {{.query.verb}}, {{.warnings}}, {{.roleBindings}} and {{.clusterRoleBindings}}.")
+	cmd.Flags().StringVar(&o.outputFile, "output-file", "",
+		"If set, write the --output format to this file instead of the standard output, which then shows the table as without --output.")
+	cmd.Flags().BoolVar(&o.keepManagedFields, "keep-managed-fields", false,
+		"If true, keep managedFields, generation and resourceVersion in the metadata of the raw-json output.")
+	cmd.Flags().StringVar(&o.outputVersion, "output-version", outputVersionV1,
+		"Version of the structured output format. One of: "+strings.Join(supportedOutputVersions(), "|")+".")
+	cmd.Flags().BoolVar(&o.showRisk, "show-risk", false,
+		"If true, show the number of rules in the role referenced by each binding, whether any of them uses a wildcard and whether the role is an aggregated ClusterRole.")
+	cmd.Flags().BoolVar(&o.showWildcard, "show-wildcard", false,
+		"If true, show whether each binding grants the action only through a wildcard verb, resource or API group.")
+	cmd.Flags().BoolVar(&o.showNearMisses, "show-near-misses", false,
+		"If true, also show bindings which grant the action on the resource type, but only for other resource names. Requires TYPE/NAME.")
+	cmd.Flags().BoolVar(&o.showRolesSummary, "show-roles-summary", false,
+		"If true, list the distinct roles referenced by the matched bindings with the number of subjects each grants the action to.")
+	cmd.Flags().BoolVar(&o.showResolved, "show-resolved", false,
+		"If true, print the resource with its API group and scope as resolved by the API discovery before the results.")
+	cmd.Flags().StringVar(&o.defaultSA, "default-sa", "",
+		"Namespace whose default service account is looked up, instead of checking an action, to list what it can do.")
+	cmd.Flags().BoolVar(&o.showUnresolved, "show-unresolved", false,
+		"If true, with --default-sa also list the bindings which refer to roles that are missing or that cannot be read, with the reason why their rules are unavailable.")
+	cmd.Flags().BoolVar(&o.whoIsAdmin, "who-is-admin", false,
+		"If true, instead of checking an action, list the subjects bound to the "+clusterAdminRole+" ClusterRole or to a role which grants all verbs on all resources, "+
+			"separately for cluster admins and namespace admins.")
+	cmd.Flags().BoolVar(&o.escalation, "escalation", false,
+		"If true, instead of checking an action, report the subjects who can escalate their privileges, such as by creating pods which run as any service account.")
+	cmd.Flags().StringVar(&o.category, "category", "",
+		"If set, check VERB on each resource of this category, such as all, instead of TYPE. "+
+			"RoleBindings are only checked for namespaced resources, since they grant nothing on cluster-scoped ones.")
+	cmd.Flags().IntVar(&o.maxResults, "max-results", 0,
+		"If positive, print at most this many subject rows after sorting and report how many were truncated.")
+	cmd.Flags().StringVar(&o.clusterRoleGrantsSection, "crole-grants-section", clusterRoleGrantsByBindingKind,
+		"Section in which RoleBindings referencing ClusterRoles are shown. One of: "+clusterRoleGrantsByBindingKind+"|"+clusterRoleGrantsByRoleKind+". "+
+			"With "+clusterRoleGrantsByRoleKind+" they are shown together with ClusterRoleBindings, but still grant the action only in their namespace.")
+	cmd.Flags().StringSliceVar(&o.excludeSANamespaces, "exclude-sa-namespace", nil,
+		"Namespace whose ServiceAccounts are omitted from the results. May be repeated or given as a comma-separated list.")
+	cmd.Flags().StringVar(&o.subject, "subject", "",
+		"If set, show only the subjects with this name. A ServiceAccount may be given as NAMESPACE:NAME to match only the ServiceAccount of that namespace.")
+	cmd.Flags().StringVar(&o.subjectKind, "subject-kind", "",
+		"If set, show only the subjects of this kind. One of: User|Group|ServiceAccount.")
+	cmd.Flags().BoolVar(&o.excludeSelfFlag, "exclude-self", false,
+		"If true, omit the current user, or the user impersonated with --as, from the results to focus on other subjects. "+
+			"Groups of the current user are still shown.")
+	cmd.Flags().BoolVar(&o.normalizeSubjects, "normalize-subjects", false,
+		"If true, list equivalent subjects once, such as the User "+serviceAccountUserPrefix+"NAMESPACE:NAME and the ServiceAccount NAME in NAMESPACE.")
+	cmd.Flags().StringVar((*string)(&o.sortBy), "sort-by", "",
+		"If set, order the rows of the tables, and the bindings and subjects of the structured output formats, by this column, "+
+			"and then by the remaining columns, so that the output is stable across runs. "+
+			"One of: subject|kind|namespace|binding.")
+	cmd.Flags().StringVar(&o.columnsSpec, "columns", "",
+		"Columns to print in a single table of all bindings, given as a preset such as audit or minimal, or as a comma-separated list of "+
+			strings.Join(columnHeaders(), ", ")+".")
+	cmd.Flags().BoolVar(&o.listColumnPresets, "list-column-presets", false,
+		"If true, list the presets of --columns with their columns instead of checking an action.")
+	cmd.Flags().StringVar(&o.groupBy, "group-by", "",
+		"If set to "+groupByAPIGroup+", print the grants in one section per API group of the granted resources, such as when querying all resources with '*'.")
+	cmd.Flags().BoolVar(&o.noHeaders, "no-headers", false,
+		"If true, print the tables without their header rows.")
+	cmd.Flags().StringVar(&o.colorMode, "color", colorAuto,
+		"When to color the rows of the tables by their risk. One of: "+strings.Join(colorModes, "|")+". "+
+			"With auto, the tables are colored if the standard output is a terminal and NO_COLOR is not set. "+
+			"Rows of subjects such as the "+mastersGroup+" group are red, rows granted through a wildcard are yellow, and other rows granted cluster-wide are magenta.")
+	cmd.Flags().BoolVar(&o.score, "score", false,
+		"Experimental: if true, list the subjects sorted by a heuristic blast radius score instead of the bindings. "+
+			"Each binding scores (1 + 2 if its role has a wildcard rule + the number of create, update, patch, delete and deletecollection verbs of the role), "+
+			"multiplied by 3 for ClusterRoleBindings, and the score of a subject is the sum over its bindings.")
+	cmd.Flags().BoolVarP(&o.quiet, "quiet", "q", false,
+		"If true, print no table and only signal by the exit code whether any subject can perform the action. "+
+			"Warnings about missing permissions are still printed to the standard error.")
+	cmd.PersistentFlags().BoolVar(readOnly.enabled, "read-only", false,
+		"If true, refuse to send any request to the API server which could modify the cluster, "+
+			"so that only get and list requests, and the creation of access and token reviews, which are never persisted, are sent.")
+	cmd.Flags().DurationVar(&o.timeout, "timeout", 0,
+		"The length of time to wait for the whole operation, such as 30s or 2m. A value of zero means no deadline. "+
+			"Each request to the API server is further limited by --request-timeout, so that a single slow request fails early, "+
+			"while the remaining requests carry on until the deadline.")
+	cmd.Flags().BoolVar(&o.flagMasters, "flag-masters", false,
+		"If true, exit with an error when any matched binding grants access to the "+mastersGroup+" group.")
+	cmd.Flags().StringVar(&o.postCommand, "post-command", "",
+		"Shell command to which the output is piped on its standard input, such as 'aws s3 cp - s3://bucket/who-can.json'. "+
+			"If the command fails, the plugin fails with an error that tells the exit code of the command.")
 
 	flag.CommandLine.VisitAll(func(goflag *flag.Flag) {
 		cmd.PersistentFlags().AddGoFlag(goflag)
 	})
-	configFlags.AddFlags(cmd.Flags())
+	configFlags.AddFlags(cmd.PersistentFlags())
+
+	// Only meant for debugging the checks of the permissions of the current user, hence not shown in the usage.
+	cmd.PersistentFlags().BoolVar(&dump.enabled, "dump-access-reviews", false,
+		"If true, write the response of each SelfSubjectAccessReview as JSON to the standard error.")
+	_ = cmd.PersistentFlags().MarkHidden("dump-access-reviews")
+
+	cmd.AddCommand(NewCmdRBACManifest(configFlags, streams))
+	cmd.AddCommand(NewCmdServiceAccounts(client, configFlags, streams))
+	cmd.AddCommand(NewCmdNamespaces(client, streams))
+	cmd.AddCommand(NewCmdWhatRoles(client, configFlags, streams))
+	cmd.AddCommand(NewCmdMatrix(client, configFlags, resourceResolver, accessChecker, streams))
+	cmd.AddCommand(NewCmdCoverage(client, configFlags, resourceResolver, streams))
+	cmd.AddCommand(NewCmdForSubject(client, configFlags, streams))
+	cmd.AddCommand(NewCmdExec(client, o, streams))
 
 	return cmd, nil
 }
 
+// run runs the report selected by the flags, or checks who can perform the specified action.
+func (w *whoCan) run(args []string) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	if w.escalation {
+		return w.runEscalationReport(args)
+	}
+	if w.category != "" {
+		return w.runCategory(args)
+	}
+	if w.whoIsAdmin {
+		return w.runAdminReport(args)
+	}
+	if w.defaultSA != "" {
+		return w.runDefaultServiceAccount(args)
+	}
+	if w.listColumnPresets {
+		return w.printColumnPresets(args)
+	}
+	if err := w.Complete(args); err != nil {
+		return err
+	}
+	if err := w.Validate(); err != nil {
+		return err
+	}
+	return w.Check()
+}
+
+// start starts the deadline of the --timeout and applies the output flags, before the action is checked.
+func (w *whoCan) start() error {
+	if err := w.timeouts.start(w.timeout); err != nil {
+		return err
+	}
+	if err := w.applyReportTemplate(); err != nil {
+		return err
+	}
+	w.color = useColor(w.colorMode, w.Out)
+	return nil
+}
+
+// handleError prints the error as a JSON object to the standard output if the JSON output format is selected,
+// so that consumers of the output always get a JSON document. The error is then returned silenced, so the command
+// still fails. If the result has been printed already, the error is left to be printed to the standard error.
+func (w *whoCan) handleError(cmd *cobra.Command, err error) error {
+	if silenceExitError(cmd, err) {
+		return err
+	}
+	if err == nil || w.outputFormat != outputJSON || w.outputFile != "" || w.resultPrinted {
+		return err
+	}
+	data, marshalErr := json.MarshalIndent(struct {
+		Error string `json:"error"`
+	}{Error: err.Error()}, "", "  ")
+	if marshalErr != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(w.Out, string(data))
+	cmd.SilenceErrors = true
+	return err
+}
+
 // Complete sets all information required to check who can perform the specified action.
 func (w *whoCan) Complete(args []string) error {
 	err := w.resolveArgs(args)
@@ -174,10 +704,51 @@ func (w *whoCan) Complete(args []string) error {
 		return err
 	}
 
+	w.wide = w.outputFormat == outputWide
+
+	if w.resourceGroup != "" {
+		if w.resource == "" {
+			return errors.New("--apigroup cannot be used with a non-resource URL")
+		}
+		if strings.Contains(w.resource, ".") {
+			return fmt.Errorf("--apigroup cannot be used with the group-qualified resource \"%s\"", w.resource)
+		}
+		w.resource = qualifyResource(w.resource, w.resourceGroup)
+	}
+
 	if w.resource != "" {
-		w.resource, err = w.resourceResolver.Resolve(w.verb, w.resource, w.subResource)
+		if err := validateResourceArg(w.resource); err != nil {
+			return err
+		}
+		resource := w.resource
+		for _, verb := range w.verbs {
+			resolved, err := w.resourceResolver.Resolve(verb, resource, w.subResource)
+			w.resource, w.apiGroup, w.apiVersion, w.namespacedResource = resolved.Resource, resolved.Group, resolved.Version, resolved.Namespaced
+			if err != nil {
+				if w.interactive {
+					err = withSuggestions(err)
+				}
+				return fmt.Errorf("resolving resource: %v", err)
+			}
+		}
+	}
+
+	w.selectedColumns, err = expandColumns(w.columnsSpec)
+	if err != nil {
+		return err
+	}
+
+	if w.subjectKind != "" {
+		w.subjectKind, err = parseSubjectKind(w.subjectKind)
 		if err != nil {
-			return fmt.Errorf("resolving resource: %v", err)
+			return err
+		}
+	}
+
+	if w.resourceNameFile != "" {
+		w.resourceNames, err = readResourceNames(w.resourceNameFile)
+		if err != nil {
+			return fmt.Errorf("reading resource names: %v", err)
 		}
 	}
 
@@ -189,44 +760,121 @@ func (w *whoCan) Complete(args []string) error {
 	return nil
 }
 
+// resolveArgs parses the verb and the second argument, which is either a NONRESOURCEURL starting with `/`,
+// a path of the Kubernetes API starting with `api/` or `apis/`, or TYPE[/NAME]. TYPE/NAME is split at the first
+// slash, so that `secrets/a/b` refers to the secret named `a/b`, while sub-resources are never parsed from it.
 func (w *whoCan) resolveArgs(args []string) error {
 	if len(args) < 2 {
 		return errors.New("you must specify two or three arguments: verb, resource, and optional resourceName")
 	}
 
 	w.verb = args[0]
+	var err error
+	w.verbs, err = parseVerbs(args[0])
+	if err != nil {
+		return err
+	}
 	if strings.HasPrefix(args[1], "/") {
 		w.nonResourceURL = args[1]
+	} else if strings.HasPrefix(args[1], "api/") || strings.HasPrefix(args[1], "apis/") {
+		w.resource, w.resourceName, err = parseAPIPath(args[1])
+		if err != nil {
+			return err
+		}
 	} else {
 		resourceTokens := strings.SplitN(args[1], "/", 2)
 		w.resource = resourceTokens[0]
 		if len(resourceTokens) > 1 {
+			if resourceTokens[1] == "" {
+				return fmt.Errorf("invalid resource \"%s\", expected TYPE or TYPE/NAME with a non-empty NAME", args[1])
+			}
 			w.resourceName = resourceTokens[1]
 		}
 	}
 	return nil
 }
 
+// parseAPIPath parses a resource given as a path of the Kubernetes API, such as `apis/apps/v1/deployments/my-app`,
+// into the fully-qualified resource, e.g. `deployments.v1.apps`, and the optional resource name.
+func parseAPIPath(path string) (resource string, resourceName string, err error) {
+	segments := strings.Split(strings.TrimSuffix(path, "/"), "/")
+
+	var group, version string
+	var rest []string
+	switch {
+	case segments[0] == "api" && len(segments) >= 3:
+		version, rest = segments[1], segments[2:]
+	case segments[0] == "apis" && len(segments) >= 4:
+		group, version, rest = segments[1], segments[2], segments[3:]
+	}
+	if len(rest) == 0 || len(rest) > 2 || version == "" || rest[0] == "" {
+		return "", "", fmt.Errorf("invalid API path \"%s\", expected api/VERSION/TYPE[/NAME] or apis/GROUP/VERSION/TYPE[/NAME]", path)
+	}
+
+	resource = rest[0] + "." + version + "." + group
+	if len(rest) == 2 {
+		resourceName = rest[1]
+	}
+	return
+}
+
+// qualifyResource qualifies the resource with the given API group. Resources of the core API group, whose name is empty,
+// are qualified with its only version, e.g. `services.v1.`, which cannot be mistaken for a resource of another group.
+func qualifyResource(resource, group string) string {
+	if group == coreGroup {
+		return resource + ".v1."
+	}
+	return resource + "." + group
+}
+
+// apiVersionPattern matches the versions of API groups, such as v1, v1beta1 or v2alpha1.
+var apiVersionPattern = regexp.MustCompile(`^v[0-9]+((alpha|beta)[0-9]+)?$`)
+
+// validateResourceArg checks that the TYPE is given as a name, a group-qualified name such as `deployments.apps`, or
+// kubectl's fully-qualified form such as `deployments.v1.apps`, which the resolver validates against the versions
+// served by the API group. None of the parts may be empty, except for the empty core group such as in `services.v1.`.
+func validateResourceArg(resource string) error {
+	segments := strings.Split(resource, ".")
+	for i, segment := range segments {
+		omittedCoreGroup := i == 2 && len(segments) == 3 && apiVersionPattern.MatchString(segments[1])
+		if segment == "" && !omittedCoreGroup {
+			return fmt.Errorf("invalid resource \"%s\", expected TYPE, TYPE.GROUP or TYPE.VERSION.GROUP", resource)
+		}
+	}
+	return nil
+}
+
+// namespaceFlagSet returns `true` if the namespace is given by the --namespace flag
+// rather than taken from the current context.
+func (w *whoCan) namespaceFlagSet() bool {
+	return w.configFlags != nil && w.configFlags.Namespace != nil && *w.configFlags.Namespace != ""
+}
+
 func (w *whoCan) resolveNamespace() (err error) {
-	if w.allNamespaces {
-		w.namespace = core.NamespaceAll
-		glog.V(3).Infof("Resolved namespace `%s` from --all-namespaces flag", w.namespace)
-		return nil
+	w.namespace, err = resolveNamespace(w.configFlags, w.clientConfig, w.allNamespaces)
+	return
+}
+
+// resolveNamespace determines the namespace from the --all-namespaces and --namespace flags,
+// falling back to the namespace of the current context.
+func resolveNamespace(configFlags *clioptions.ConfigFlags, clientConfig clientcmd.ClientConfig, allNamespaces bool) (string, error) {
+	if allNamespaces {
+		glog.V(3).Infof("Resolved namespace `%s` from --all-namespaces flag", core.NamespaceAll)
+		return core.NamespaceAll, nil
 	}
 
-	if w.configFlags.Namespace != nil && *w.configFlags.Namespace != "" {
-		w.namespace = *w.configFlags.Namespace
-		glog.V(3).Infof("Resolved namespace `%s` from --namespace flag", w.namespace)
-		return nil
+	if configFlags.Namespace != nil && *configFlags.Namespace != "" {
+		glog.V(3).Infof("Resolved namespace `%s` from --namespace flag", *configFlags.Namespace)
+		return *configFlags.Namespace, nil
 	}
 
 	// Neither --all-namespaces nor --namespace flag was specified
-	w.namespace, _, err = w.clientConfig.Namespace()
+	namespace, _, err := clientConfig.Namespace()
 	if err != nil {
-		return fmt.Errorf("getting namespace from current context: %v", err)
+		return "", fmt.Errorf("getting namespace from current context: %v", err)
 	}
-	glog.V(3).Infof("Resolved namespace `%s` from current context", w.namespace)
-	return nil
+	glog.V(3).Infof("Resolved namespace `%s` from current context", namespace)
+	return namespace, nil
 }
 
 // Validate makes sure that provided args and flags are valid.
@@ -234,6 +882,122 @@ func (w *whoCan) Validate() error {
 	if w.nonResourceURL != "" && w.subResource != "" {
 		return fmt.Errorf("--subresource cannot be used with NONRESOURCEURL")
 	}
+	// NonResourceURLs can only be granted by ClusterRoleBindings, hence they are not scoped to a namespace.
+	if w.nonResourceURL != "" && w.namespaceFlagSet() {
+		return errors.New("--namespace cannot be used with NONRESOURCEURL, which is not namespaced")
+	}
+
+	if w.selector != "" {
+		if w.nonResourceURL != "" || w.resourceName != "" || w.resourceNameFile != "" || w.resource == rbac.ResourceAll {
+			return errors.New("--selector cannot be used with NONRESOURCEURL, TYPE/NAME, the * resource or --resource-name-file")
+		}
+		if len(w.verbs) > 1 {
+			return errors.New("--selector cannot be used with a comma-separated VERB")
+		}
+		if _, err := labels.Parse(w.selector); err != nil {
+			return fmt.Errorf("invalid selector \"%s\": %v", w.selector, err)
+		}
+	}
+	if w.verifyResourceName && (w.resourceName == "" || w.resource == rbac.ResourceAll) {
+		return errors.New("--verify-resource-name requires TYPE/NAME")
+	}
+
+	if w.resourceNameFile != "" || w.selector != "" {
+		if w.resourceNameFile != "" && (w.nonResourceURL != "" || w.resourceName != "") {
+			return errors.New("--resource-name-file cannot be used with NONRESOURCEURL or TYPE/NAME")
+		}
+		if w.resourceNameMatch != resourceNameMatchAny && w.resourceNameMatch != resourceNameMatchAll {
+			return fmt.Errorf("unsupported resource name match \"%s\", expected one of [%s %s]",
+				w.resourceNameMatch, resourceNameMatchAny, resourceNameMatchAll)
+		}
+	}
+
+	if w.requireAllVerbs && len(w.verbs) < 2 {
+		return errors.New("--require-all-verbs requires a comma-separated VERB, such as get,list,watch")
+	}
+	if len(w.verbs) > 1 && w.resourceNameFile != "" {
+		return errors.New("--resource-name-file cannot be used with a comma-separated VERB")
+	}
+
+	if w.namespaceSelector != "" {
+		if _, err := labels.Parse(w.namespaceSelector); err != nil {
+			return fmt.Errorf("invalid namespace selector \"%s\": %v", w.namespaceSelector, err)
+		}
+	}
+
+	if w.showNearMisses && w.resourceName == "" {
+		return errors.New("--show-near-misses requires TYPE/NAME")
+	}
+
+	if w.maxResults < 0 {
+		return fmt.Errorf("--max-results must not be negative, got %d", w.maxResults)
+	}
+
+	switch w.clusterRoleGrantsSection {
+	case "", clusterRoleGrantsByBindingKind, clusterRoleGrantsByRoleKind:
+	default:
+		return fmt.Errorf("unsupported ClusterRole grants section \"%s\", expected one of [%s %s]",
+			w.clusterRoleGrantsSection, clusterRoleGrantsByBindingKind, clusterRoleGrantsByRoleKind)
+	}
+
+	if w.quiet && !w.printsTable() && w.outputFile == "" {
+		return errors.New("--quiet cannot be used with --output unless --output-file is set")
+	}
+
+	if w.outputFile != "" && w.printsTable() {
+		return errors.New("--output-file requires --output")
+	}
+	// With --output-file the table is printed as well, hence it can be rendered with --columns or --group-by.
+	showsTable := w.printsTable() || w.outputFile != ""
+	if w.columnsSpec != "" && (!showsTable || w.groupBy != "") {
+		return errors.New("--columns cannot be used with --output or --group-by")
+	}
+
+	if w.score && (!showsTable || w.columnsSpec != "" || w.groupBy != "") {
+		return errors.New("--score cannot be used with --output, --columns or --group-by")
+	}
+
+	switch w.groupBy {
+	case "":
+	case groupByAPIGroup:
+		if w.nonResourceURL != "" {
+			return errors.New("--group-by=" + groupByAPIGroup + " cannot be used with NONRESOURCEURL")
+		}
+		if !showsTable {
+			return errors.New("--group-by cannot be used with --output")
+		}
+	default:
+		return fmt.Errorf("unsupported group by \"%s\", expected one of [%s]", w.groupBy, groupByAPIGroup)
+	}
+
+	if w.sortBy != "" && !isSupportedSortKey(w.sortBy) {
+		return fmt.Errorf("unsupported sort by \"%s\", expected one of %v", w.sortBy, sortKeys)
+	}
+
+	switch w.colorMode {
+	case "", colorAuto, colorAlways, colorNever:
+	default:
+		return fmt.Errorf("unsupported color \"%s\", expected one of %v", w.colorMode, colorModes)
+	}
+
+	var template bool
+	if spec, ok := customColumnsSpec(w.outputFormat); ok {
+		if _, err := parseCustomColumns(spec); err != nil {
+			return err
+		}
+	} else if !w.printsTable() && !isSupportedOutputFormat(w.outputFormat) {
+		_, ok, err := w.templatePrinter()
+		if err != nil {
+			return fmt.Errorf("invalid output template: %v", err)
+		}
+		if !ok {
+			return fmt.Errorf("unsupported output format \"%s\", expected one of %v", w.outputFormat, allOutputFormats())
+		}
+		template = true
+	}
+	if _, ok := outputVersions[w.outputVersion]; (w.outputFormat == outputJSON || w.outputFormat == outputYAML || w.outputFormat == outputConfigMap || template) && !ok {
+		return fmt.Errorf("unsupported output version \"%s\", expected one of %v", w.outputVersion, supportedOutputVersions())
+	}
 
 	err := w.namespaceValidator.Validate(w.namespace)
 	if err != nil {
@@ -245,49 +1009,167 @@ func (w *whoCan) Validate() error {
 
 // Check checks who can perform the action specified by WhoCanOptions and prints the results to the standard output.
 func (w *whoCan) Check() error {
+	if err := w.lookupObjects(); err != nil {
+		return err
+	}
+
 	warnings, err := w.checkAPIAccess()
 	if err != nil {
 		return fmt.Errorf("checking API access: %v", err)
 	}
 
+	w.rules = make(map[role][]rbac.PolicyRule, 10)
+	w.wildcards = make(map[role]bool, 10)
+	w.apiGroups = make(map[role]map[string]struct{}, 10)
+	w.nearMisses = make(map[role][]string)
+
+	var roleBindings []rbac.RoleBinding
+	var clusterRoleBindings []rbac.ClusterRoleBinding
+	if len(w.resourceNames) > 0 {
+		roleBindings, clusterRoleBindings, err = w.getBindingsForResourceNames()
+	} else if len(w.verbs) > 1 {
+		roleBindings, clusterRoleBindings, err = w.getBindingsForVerbs()
+	} else {
+		w.jsonLines = nil
+		if w.streamsJSONLines() {
+			w.jsonLines = w.newJSONLinesStream(warnings)
+		}
+		roleBindings, clusterRoleBindings, err = w.getBindings()
+	}
+	if err != nil {
+		return err
+	}
+	roleBindings, clusterRoleBindings = w.filterBindings(roleBindings, clusterRoleBindings)
+
+	// Flag bindings to the group that bypasses RBAC
+	masters := w.mastersBindings(roleBindings, clusterRoleBindings)
+
+	if w.outputFormat == outputRawJSON && w.outputFile == "" {
+		// Keep the standard output parseable as a List of API objects.
+		for _, warning := range warnings {
+			_, _ = fmt.Fprintf(w.ErrOut, "Warning: The list might not be complete due to missing permission: %s\n", warning)
+		}
+	}
+
+	if w.jsonLines != nil {
+		w.jsonLines.close(masters)
+	} else if !w.printsTable() && w.outputFile == "" {
+		err = w.printStructured(roleBindings, clusterRoleBindings, warnings, masters)
+		if err != nil {
+			return err
+		}
+	} else {
+		// The structured output, if any, is written to the file, while the table is still printed.
+		if w.outputFile != "" {
+			err = w.writeOutputFile(roleBindings, clusterRoleBindings, warnings, masters)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !w.quiet {
+			w.printResolved()
+		}
+
+		// Output warnings
+		w.printAPIAccessWarnings(warnings)
+
+		// Output the results
+		if !w.quiet {
+			w.output(roleBindings, clusterRoleBindings)
+			w.printNearMisses()
+			w.printRolesSummary(roleBindings, clusterRoleBindings)
+		}
+
+		w.printMastersWarnings(masters)
+	}
+
+	if w.flagMasters && len(masters) > 0 {
+		return fmt.Errorf("the %s group is bound by: %s", mastersGroup, strings.Join(masters, ", "))
+	}
+
+	if len(w.roleBindingRows(roleBindings))+len(w.clusterRoleBindingRows(clusterRoleBindings)) == 0 {
+		return &ExitError{Code: ExitCodeNoSubjects, Reason: fmt.Sprintf("no subjects found with permissions to %s", w.prettyPrintAction())}
+	}
+
+	return nil
+}
+
+// filterBindings normalizes the subjects of the matched bindings, and drops the subjects which are excluded by the flags.
+// Bindings without subjects are dropped.
+func (w *whoCan) filterBindings(roleBindings []rbac.RoleBinding, clusterRoleBindings []rbac.ClusterRoleBinding) ([]rbac.RoleBinding, []rbac.ClusterRoleBinding) {
+	roleBindings, clusterRoleBindings = w.normalizeBindingSubjects(roleBindings, clusterRoleBindings)
+	roleBindings, clusterRoleBindings = w.excludeServiceAccounts(roleBindings, clusterRoleBindings)
+	roleBindings, clusterRoleBindings = w.filterSubjects(roleBindings, clusterRoleBindings)
+	return w.excludeSelf(roleBindings, clusterRoleBindings)
+}
+
+// printsTable tells whether the output format selected by --output is the default or the wide table, rather than
+// a structured format.
+func (w *whoCan) printsTable() bool {
+	return w.outputFormat == "" || w.outputFormat == outputWide
+}
+
+// warningsOut returns the writer of the warnings printed with the table, which is the standard error in quiet mode,
+// so that an incomplete result is never silent.
+func (w *whoCan) warningsOut() io.Writer {
+	if w.quiet {
+		return w.ErrOut
+	}
+	return w.Out
+}
+
+// printStructured prints the matched bindings in the structured format selected by --output.
+func (w *whoCan) printStructured(roleBindings []rbac.RoleBinding, clusterRoleBindings []rbac.ClusterRoleBinding, warnings, masters []string) error {
+	if w.outputFormat == outputRawJSON {
+		return w.printRawBindings(roleBindings, clusterRoleBindings)
+	}
+	for _, binding := range masters {
+		warnings = append(warnings, fmt.Sprintf("%s grants access to the %s group, which bypasses RBAC authorization", binding, mastersGroup))
+	}
+	result := w.newResult(roleBindings, clusterRoleBindings, warnings)
+	result.Metadata = w.newMetadata()
+	return w.printResult(result)
+}
+
+// getBindings returns the RoleBindings and ClusterRoleBindings which grant the queried action.
+func (w *whoCan) getBindings() (roleBindings []rbac.RoleBinding, clusterRoleBindings []rbac.ClusterRoleBinding, err error) {
 	w.r = make(map[role]struct{}, 10)
 
 	// Get the Roles that relate to the Verbs and Resources we are interested in
 	err = w.getRoles()
 	if err != nil {
-		return fmt.Errorf("getting Roles: %v", err)
+		return nil, nil, fmt.Errorf("getting Roles: %v", err)
 	}
 
-	// Get the RoleBindings that relate to this set of Roles
-	roleBindings, err := w.getRoleBindings()
+	// Get the ClusterRoles that relate to the verbs and resources we are interested in
+	err = w.getClusterRoles()
 	if err != nil {
-		return fmt.Errorf("getting RoleBindings: %v", err)
+		return nil, nil, fmt.Errorf("getting ClusterRoles: %v", err)
 	}
 
-	// Get the ClusterRoles that relate to the verbs and resources we are interested in
-	err = w.getClusterRoles()
+	// Get the RoleBindings that relate to this set of Roles and ClusterRoles. They are matched only after the
+	// ClusterRoles, otherwise the RoleBindings which reference a ClusterRole would never match.
+	roleBindings, err = w.getRoleBindings()
 	if err != nil {
-		return fmt.Errorf("getting ClusterRoles: %v", err)
+		return nil, nil, fmt.Errorf("getting RoleBindings: %v", err)
 	}
 
 	// Get the ClusterRoleBindings that relate to this set of ClusterRoles
-	clusterRoleBindings, err := w.getClusterRoleBindings()
+	clusterRoleBindings, err = w.getClusterRoleBindings()
 	if err != nil {
-		return fmt.Errorf("getting ClusterRoleBindings: %v", err)
+		return nil, nil, fmt.Errorf("getting ClusterRoleBindings: %v", err)
 	}
 
-	// Output warnings
-	w.printAPIAccessWarnings(warnings)
-
-	// Output the results
-	w.output(roleBindings, clusterRoleBindings)
-
-	return nil
+	return roleBindings, clusterRoleBindings, nil
 }
 
+// checkAPIAccess checks whether the current user can list the RBAC objects, and returns a warning for each missing permission.
+// In all namespaces, only the namespaces matched by the --namespace-selector are checked, and recorded as selectedNamespaces.
 func (w *whoCan) checkAPIAccess() ([]string, error) {
 	type check struct {
 		verb      string
+		group     string
 		resource  string
 		namespace string
 	}
@@ -296,51 +1178,96 @@ func (w *whoCan) checkAPIAccess() ([]string, error) {
 	var warnings []string
 
 	// Determine which checks need to be executed.
+	w.selectedNamespaces = nil
 	if w.namespace == "" {
-		checks = append(checks, check{"list", "namespaces", ""})
+		checks = append(checks, check{"list", "", "namespaces", ""})
 
-		nsList, err := w.clientNamespace.List(meta.ListOptions{})
+		nsList, err := w.clientNamespace.List(meta.ListOptions{LabelSelector: w.namespaceSelector})
 		if err != nil {
 			return nil, fmt.Errorf("listing namespaces: %v", err)
 		}
+		if w.namespaceSelector != "" {
+			w.selectedNamespaces = make(map[string]struct{}, len(nsList.Items))
+		}
 		for _, ns := range nsList.Items {
-			checks = append(checks, check{"list", "roles", ns.Name})
-			checks = append(checks, check{"list", "rolebindings", ns.Name})
+			if w.selectedNamespaces != nil {
+				w.selectedNamespaces[ns.Name] = struct{}{}
+			}
+			for _, resource := range namespacedAPIAccess {
+				checks = append(checks, check{"list", rbac.GroupName, resource, ns.Name})
+			}
 		}
 	} else {
-		checks = append(checks, check{"list", "roles", w.namespace})
-		checks = append(checks, check{"list", "rolebindings", w.namespace})
+		for _, resource := range namespacedAPIAccess {
+			checks = append(checks, check{"list", rbac.GroupName, resource, w.namespace})
+		}
 	}
 
-	// Actually run the checks and collect warnings.
-	for _, check := range checks {
-		allowed, err := w.accessChecker.IsAllowedTo(check.verb, check.resource, check.namespace)
-		if err != nil {
-			return nil, err
-		}
-		if !allowed {
-			var msg string
+	// Actually run the checks concurrently and collect warnings in the order of checks.
+	type outcome struct {
+		allowed bool
+		err     error
+	}
+	outcomes := make([]outcome, len(checks))
+	sem := make(chan struct{}, maxConcurrentAccessChecks)
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, verb, group, resource, namespace string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			allowed, err := w.accessChecker.IsAllowedTo(verb, group, resource, namespace)
+			outcomes[i] = outcome{allowed: allowed, err: err}
+		}(i, check.verb, check.group, check.resource, check.namespace)
+	}
+	wg.Wait()
 
-			if check.namespace == "" {
-				msg = fmt.Sprintf("The user is not allowed to %s %s", check.verb, check.resource)
-			} else {
-				msg = fmt.Sprintf("The user is not allowed to %s %s in the %s namespace", check.verb, check.resource, check.namespace)
-			}
+	for i, check := range checks {
+		action := fmt.Sprintf("%s %s", check.verb, check.resource)
+		if check.namespace != "" {
+			action = fmt.Sprintf("%s in the %s namespace", action, check.namespace)
+		}
 
-			warnings = append(warnings, msg)
+		if err := outcomes[i].err; err != nil {
+			warnings = append(warnings, fmt.Sprintf("Failed to check whether the user is allowed to %s: %v", action, err))
+			continue
+		}
+		if !outcomes[i].allowed {
+			warnings = append(warnings, fmt.Sprintf("The user is not allowed to %s", action))
 		}
 	}
 
 	return warnings, nil
 }
 
+// printResolved prints the resource resolved from the TYPE argument, so that the user can confirm
+// that the query checks the intended resource.
+func (w *whoCan) printResolved() {
+	if !w.showResolved || w.resource == "" {
+		return
+	}
+	if w.resource == rbac.ResourceAll {
+		_, _ = fmt.Fprintf(w.Out, "Resolved resource: %s (all resources)\n\n", w.resource)
+		return
+	}
+	scope := "cluster-scoped"
+	if w.namespacedResource {
+		scope = "namespaced"
+	}
+	_, _ = fmt.Fprintf(w.Out, "Resolved resource: %s (%s)\n\n", qualifiedName(w.resource, w.apiGroup), scope)
+}
+
 func (w *whoCan) printAPIAccessWarnings(warnings []string) {
+	out := w.warningsOut()
 	if len(warnings) > 0 {
-		_, _ = fmt.Fprintln(w.Out, "Warning: The list might not be complete due to missing permission(s):")
+		_, _ = fmt.Fprintln(out, "Warning: The list might not be complete due to missing permission(s):")
 		for _, warning := range warnings {
-			_, _ = fmt.Fprintf(w.Out, "\t%s\n", warning)
+			_, _ = fmt.Fprintf(out, "\t%s\n", warning)
 		}
-		_, _ = fmt.Fprintln(w.Out)
+		_, _ = fmt.Fprintln(out)
 	}
 }
 
@@ -357,18 +1284,23 @@ func (w *whoCan) getRoles() error {
 func (w *whoCan) filterRoles(roles *rbac.RoleList) {
 	for _, item := range roles.Items {
 		for _, rule := range item.Rules {
+			newRole := role{
+				name:          item.Name,
+				isClusterRole: false,
+				namespace:     item.Namespace,
+			}
 			if !w.policyRuleMatches(rule) {
 				glog.V(3).Infof("Role [%s] doesn't match policy filter", item.Name)
+				w.recordNearMiss(newRole, rule)
 				continue
 			}
 
-			newRole := role{
-				name:          item.Name,
-				isClusterRole: false,
-			}
 			if _, ok := w.r[newRole]; !ok {
 				w.r[newRole] = struct{}{}
+				w.rules[newRole] = item.Rules
 			}
+			w.recordWildcard(newRole, rule)
+			w.recordAPIGroups(newRole, rule)
 
 		}
 	}
@@ -384,25 +1316,49 @@ func (w *whoCan) getClusterRoles() error {
 	return nil
 }
 
+// filterClusterRoles records the ClusterRoles with a rule that matches the queried action. The rules of aggregated
+// ClusterRoles include the rules of the ClusterRoles selected by their aggregationRule.
 func (w *whoCan) filterClusterRoles(roles *rbac.ClusterRoleList) {
+	aggregation := newAggregation(roles.Items)
 	for _, item := range roles.Items {
-		for _, rule := range item.Rules {
+		rules := item.Rules
+		if item.AggregationRule != nil {
+			rules = aggregation.rulesOf(item)
+		}
+		for _, rule := range rules {
+			newRole := role{
+				name:          item.Name,
+				isClusterRole: true,
+			}
 			if !w.policyRuleMatches(rule) {
 				glog.V(3).Infof("ClusterRole [%s] doesn't match policy filter", item.Name)
+				w.recordNearMiss(newRole, rule)
 				continue
 			}
 
-			newRole := role{
-				name:          item.Name,
-				isClusterRole: true,
-			}
 			if _, ok := w.r[newRole]; !ok {
 				w.r[newRole] = struct{}{}
+				w.rules[newRole] = rules
+				if item.AggregationRule != nil {
+					w.recordAggregated(newRole)
+				}
 			}
+			w.recordWildcard(newRole, rule)
+			w.recordAPIGroups(newRole, rule)
 		}
 	}
 }
 
+// recordAggregated records that the given matched ClusterRole is an aggregated ClusterRole. Its rules are maintained
+// by the controller manager, which combines the rules of the ClusterRoles selected by its aggregationRule, so that
+// editing its rules has no lasting effect.
+func (w *whoCan) recordAggregated(r role) {
+	if w.aggregatedRoles == nil {
+		w.aggregatedRoles = make(roles)
+	}
+	w.aggregatedRoles[r] = struct{}{}
+}
+
 func (w *whoCan) policyRuleMatches(rule rbac.PolicyRule) bool {
 	if w.nonResourceURL != "" {
 		return w.matchesVerb(rule) &&
@@ -410,10 +1366,40 @@ func (w *whoCan) policyRuleMatches(rule rbac.PolicyRule) bool {
 	}
 
 	return w.matchesVerb(rule) &&
+		w.matchesAPIGroup(rule) &&
 		w.matchesResource(rule) &&
 		w.matchesResourceName(rule)
 }
 
+// recordWildcard records whether the given matching rule grants the queried action only through a wildcard.
+// A role is considered to grant the action through a wildcard if none of its matching rules grants it explicitly.
+func (w *whoCan) recordWildcard(r role, rule rbac.PolicyRule) {
+	if wildcard, ok := w.wildcards[r]; !ok || wildcard {
+		w.wildcards[r] = w.matchesThroughWildcard(rule)
+	}
+}
+
+// matchesThroughWildcard returns `true` if the given matching rule grants the queried action
+// through a wildcard verb, resource or API group rather than explicitly.
+func (w *whoCan) matchesThroughWildcard(rule rbac.PolicyRule) bool {
+	explicit := func(values []string, queried string) bool {
+		for _, v := range values {
+			if v == queried {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !explicit(rule.Verbs, w.verb) {
+		return true
+	}
+	if w.nonResourceURL != "" {
+		return !explicit(rule.NonResourceURLs, w.nonResourceURL)
+	}
+	return !explicit(rule.Resources, w.resource) || explicit(rule.APIGroups, rbac.APIGroupAll)
+}
+
 func (w *whoCan) matchesVerb(rule rbac.PolicyRule) bool {
 	for _, verb := range rule.Verbs {
 		if verb == rbac.VerbAll || verb == w.verb {
@@ -423,11 +1409,35 @@ func (w *whoCan) matchesVerb(rule rbac.PolicyRule) bool {
 	return false
 }
 
+// matchesAPIGroup returns `true` if the given rule applies to the API group of the queried resource.
+// The API group is not known when querying all resources with `*`, in which case any API group matches.
+func (w *whoCan) matchesAPIGroup(rule rbac.PolicyRule) bool {
+	if w.resource == rbac.ResourceAll {
+		return true
+	}
+	for _, group := range rule.APIGroups {
+		if group == rbac.APIGroupAll || group == w.apiGroup {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesResource returns `true` if the given rule applies to the queried resource. Like the RBAC authorizer,
+// a sub-resource such as `deployments/scale` is also matched by a `*/scale` rule, which applies to the
+// sub-resource of all resources.
 func (w *whoCan) matchesResource(rule rbac.PolicyRule) bool {
+	var subResource string
+	if i := strings.Index(w.resource, "/"); i >= 0 {
+		subResource = w.resource[i+1:]
+	}
 	for _, resource := range rule.Resources {
 		if resource == rbac.ResourceAll || resource == w.resource {
 			return true
 		}
+		if subResource != "" && resource == rbac.ResourceAll+"/"+subResource {
+			return true
+		}
 	}
 	return false
 }
@@ -447,9 +1457,15 @@ func (w *whoCan) matchesResourceName(rule rbac.PolicyRule) bool {
 	return false
 }
 
+// matchesNonResourceURL returns `true` if one of the NonResourceURLs of the given rule matches the queried URL.
+// A URL ending with `*` matches every URL with the same prefix, including nested path segments,
+// the same way as the RBAC authorizer does, so that `/apis/*` matches `/apis/apps/v1`.
 func (w *whoCan) matchesNonResourceURL(rule rbac.PolicyRule) bool {
 	for _, URL := range rule.NonResourceURLs {
-		if URL == w.nonResourceURL {
+		if URL == rbac.NonResourceAll || URL == w.nonResourceURL {
+			return true
+		}
+		if strings.HasSuffix(URL, "*") && strings.HasPrefix(w.nonResourceURL, strings.TrimRight(URL, "*")) {
 			return true
 		}
 	}
@@ -463,15 +1479,33 @@ func (w *whoCan) getRoleBindings() (roleBindings []rbac.RoleBinding, err error)
 	}
 
 	for _, roleBinding := range rbl.Items {
-		if w.r.match(&roleBinding.RoleRef) {
+		if !w.isNamespaceSelected(roleBinding.Namespace) {
+			continue
+		}
+		if w.r.match(&roleBinding.RoleRef, roleBinding.Namespace) {
 			glog.V(1).Info(fmt.Sprintf("Match found: roleRef: %v", roleBinding.RoleRef))
 			roleBindings = append(roleBindings, roleBinding)
+			if err = w.jsonLines.writeRoleBinding(roleBinding); err != nil {
+				return
+			}
+		} else if w.isNearMiss(&roleBinding.RoleRef, roleBinding.Namespace) {
+			w.nearMissRoleBindings = append(w.nearMissRoleBindings, roleBinding)
 		}
 	}
 
 	return
 }
 
+// isNamespaceSelected returns `true` if the given namespace is matched by the --namespace-selector,
+// or if no namespaces have been selected by it.
+func (w *whoCan) isNamespaceSelected(namespace string) bool {
+	if w.selectedNamespaces == nil {
+		return true
+	}
+	_, ok := w.selectedNamespaces[namespace]
+	return ok
+}
+
 func (w *whoCan) getClusterRoleBindings() (clusterRoleBindings []rbac.ClusterRoleBinding, err error) {
 	rbl, err := w.clientRBAC.ClusterRoleBindings().List(meta.ListOptions{})
 	if err != nil {
@@ -479,20 +1513,35 @@ func (w *whoCan) getClusterRoleBindings() (clusterRoleBindings []rbac.ClusterRol
 	}
 
 	for _, roleBinding := range rbl.Items {
-		if w.r.match(&roleBinding.RoleRef) {
+		if w.r.match(&roleBinding.RoleRef, "") {
 			glog.V(1).Info(fmt.Sprintf("Match found: roleRef: %v", roleBinding.RoleRef))
 			clusterRoleBindings = append(clusterRoleBindings, roleBinding)
+			if err = w.jsonLines.writeClusterRoleBinding(roleBinding); err != nil {
+				return
+			}
+		} else if w.isNearMiss(&roleBinding.RoleRef, "") {
+			w.nearMissClusterRoleBindings = append(w.nearMissClusterRoleBindings, roleBinding)
 		}
 	}
 
 	return
 }
 
-func (r roles) match(roleRef *rbac.RoleRef) bool {
-	tempRole := role{
+// newRoleFromRef returns the role referenced by the given RoleRef of a binding in the given namespace.
+// The namespace is empty for ClusterRoleBindings. It is ignored if the RoleRef refers to a ClusterRole.
+func newRoleFromRef(roleRef *rbac.RoleRef, namespace string) role {
+	r := role{
 		name:          roleRef.Name,
 		isClusterRole: (roleRef.Kind == "ClusterRole"),
 	}
+	if !r.isClusterRole {
+		r.namespace = namespace
+	}
+	return r
+}
+
+func (r roles) match(roleRef *rbac.RoleRef, namespace string) bool {
+	tempRole := newRoleFromRef(roleRef, namespace)
 
 	glog.V(3).Info(fmt.Sprintf("Testing against roleRef: %v", tempRole))
 
@@ -501,44 +1550,375 @@ func (r roles) match(roleRef *rbac.RoleRef) bool {
 }
 
 func (w *whoCan) output(roleBindings []rbac.RoleBinding, clusterRoleBindings []rbac.ClusterRoleBinding) {
+	if w.score {
+		w.outputScores(roleBindings, clusterRoleBindings)
+		return
+	}
+	if w.clusterRoleGrantsSection == clusterRoleGrantsByRoleKind {
+		w.outputByRoleKind(roleBindings, clusterRoleBindings)
+		return
+	}
+	if w.groupBy == groupByAPIGroup {
+		w.outputByAPIGroup(roleBindings, clusterRoleBindings)
+		return
+	}
+	if len(w.selectedColumns) > 0 {
+		w.outputColumns(roleBindings, clusterRoleBindings)
+		return
+	}
+
 	wr := new(tabwriter.Writer)
 	wr.Init(w.Out, 0, 8, 2, ' ', 0)
 
 	action := w.prettyPrintAction()
+	limit := &rowLimit{max: w.maxResults}
 
 	if w.resource != "" {
 		// NonResourceURL permissions can only be granted through ClusterRoles. Hence no point in printing RoleBindings section.
-		if len(roleBindings) == 0 {
+		all := w.roleBindingRows(roleBindings)
+		rows := limit.take(all)
+		if len(all) == 0 {
 			fmt.Fprintf(w.Out, "No subjects found with permissions to %s assigned through RoleBindings\n", action)
-		} else {
-			fmt.Fprintln(wr, "ROLEBINDING\tNAMESPACE\tSUBJECT\tTYPE\tSA-NAMESPACE")
-			for _, rb := range roleBindings {
-				for _, s := range rb.Subjects {
-					fmt.Fprintf(wr, "%s\t%s\t%s\t%s\t%s\n", rb.Name, rb.GetNamespace(), s.Name, s.Kind, s.Namespace)
-				}
+		} else if len(rows) > 0 {
+			w.printHeader(wr, "ROLEBINDING\tNAMESPACE\tSUBJECT\tTYPE\tSA-NAMESPACE")
+			for _, r := range rows {
+				fmt.Fprintf(wr, "%s%s\t%s\t%s\t%s\t%s%s%s\n", w.rowColor(r), r.binding, r.namespace, r.subject.Name, r.subject.Kind, r.subject.Namespace, w.optionalColumns(r), w.endColor())
 			}
 		}
 
 		fmt.Fprintln(wr)
 	}
 
-	if len(clusterRoleBindings) == 0 {
+	all := w.clusterRoleBindingRows(clusterRoleBindings)
+	rows := limit.take(all)
+	if len(all) == 0 {
 		fmt.Fprintf(w.Out, "No subjects found with permissions to %s assigned through ClusterRoleBindings\n", action)
-	} else {
-		fmt.Fprintln(wr, "CLUSTERROLEBINDING\tSUBJECT\tTYPE\tSA-NAMESPACE")
-		for _, rb := range clusterRoleBindings {
-			for _, s := range rb.Subjects {
-				fmt.Fprintf(wr, "%s\t%s\t%s\t%s\n", rb.Name, s.Name, s.Kind, s.Namespace)
+	} else if len(rows) > 0 {
+		w.printHeader(wr, "CLUSTERROLEBINDING\tSUBJECT\tTYPE\tSA-NAMESPACE")
+		for _, r := range rows {
+			fmt.Fprintf(wr, "%s%s\t%s\t%s\t%s%s%s\n", w.rowColor(r), r.binding, r.subject.Name, r.subject.Kind, r.subject.Namespace, w.optionalColumns(r), w.endColor())
+		}
+	}
+	wr.Flush()
+	limit.printNotice(w.Out)
+}
+
+// outputByRoleKind prints the subjects grouped by the kind of the granted role rather than by the kind of the binding.
+// RoleBindings which reference ClusterRoles are shown together with ClusterRoleBindings and qualified
+// with their namespace, because they grant the action only in that namespace.
+func (w *whoCan) outputByRoleKind(roleBindings []rbac.RoleBinding, clusterRoleBindings []rbac.ClusterRoleBinding) {
+	wr := new(tabwriter.Writer)
+	wr.Init(w.Out, 0, 8, 2, ' ', 0)
+
+	action := w.prettyPrintAction()
+	limit := &rowLimit{max: w.maxResults}
+
+	var roleGrants, clusterRoleGrants []rbac.RoleBinding
+	for _, rb := range roleBindings {
+		if rb.RoleRef.Kind == "ClusterRole" {
+			clusterRoleGrants = append(clusterRoleGrants, rb)
+		} else {
+			roleGrants = append(roleGrants, rb)
+		}
+	}
+
+	if w.resource != "" {
+		all := w.roleBindingRows(roleGrants)
+		rows := limit.take(all)
+		if len(all) == 0 {
+			fmt.Fprintf(w.Out, "No subjects found with permissions to %s assigned through Roles\n", action)
+		} else if len(rows) > 0 {
+			w.printHeader(wr, "ROLEBINDING\tNAMESPACE\tSUBJECT\tTYPE\tSA-NAMESPACE")
+			for _, r := range rows {
+				fmt.Fprintf(wr, "%s%s\t%s\t%s\t%s\t%s%s%s\n", w.rowColor(r), r.binding, r.namespace, r.subject.Name, r.subject.Kind, r.subject.Namespace, w.optionalColumns(r), w.endColor())
 			}
 		}
+
+		fmt.Fprintln(wr)
+	}
+
+	all := w.roleBindingRows(clusterRoleGrants)
+	for i := range all {
+		all[i].bindingKind = "RoleBinding"
+	}
+	for _, r := range w.clusterRoleBindingRows(clusterRoleBindings) {
+		r.bindingKind = "ClusterRoleBinding"
+		all = append(all, r)
+	}
+	if w.sortBy != "" {
+		sortRows(all, w.sortBy)
+	}
+	rows := limit.take(all)
+	if len(all) == 0 {
+		fmt.Fprintf(w.Out, "No subjects found with permissions to %s assigned through ClusterRoles\n", action)
+	} else if len(rows) > 0 {
+		w.printHeader(wr, "BINDING\tNAMESPACE\tSUBJECT\tTYPE\tSA-NAMESPACE")
+		for _, r := range rows {
+			fmt.Fprintf(wr, "%s%s/%s\t%s\t%s\t%s\t%s%s%s\n", w.rowColor(r), r.bindingKind, r.binding, r.namespace, r.subject.Name, r.subject.Kind, r.subject.Namespace, w.optionalColumns(r), w.endColor())
+		}
 	}
 	wr.Flush()
+	limit.printNotice(w.Out)
+}
+
+// roleBindingRows flattens the subjects of the given RoleBindings into rows.
+func (w *whoCan) roleBindingRows(roleBindings []rbac.RoleBinding) []subjectRow {
+	var rows []subjectRow
+	for _, rb := range roleBindings {
+		for _, s := range rb.Subjects {
+			rows = append(rows, subjectRow{binding: rb.Name, namespace: rb.GetNamespace(), roleRef: rb.RoleRef, subject: s})
+		}
+	}
+	if w.sortBy != "" {
+		sortRows(rows, w.sortBy)
+	}
+	return rows
+}
+
+// clusterRoleBindingRows flattens the subjects of the given ClusterRoleBindings into rows.
+func (w *whoCan) clusterRoleBindingRows(clusterRoleBindings []rbac.ClusterRoleBinding) []subjectRow {
+	var rows []subjectRow
+	for _, rb := range clusterRoleBindings {
+		for _, s := range rb.Subjects {
+			rows = append(rows, subjectRow{binding: rb.Name, roleRef: rb.RoleRef, subject: s})
+		}
+	}
+	if w.sortBy != "" {
+		sortRows(rows, w.sortBy)
+	}
+	return rows
+}
+
+// mastersBindings returns the names of the matched bindings with the mastersGroup as a subject.
+// RoleBindings are qualified with their namespace.
+func (w *whoCan) mastersBindings(roleBindings []rbac.RoleBinding, clusterRoleBindings []rbac.ClusterRoleBinding) []string {
+	var names []string
+	for _, r := range w.roleBindingRows(roleBindings) {
+		if isMastersGroup(r.subject) {
+			names = append(names, fmt.Sprintf("RoleBinding %s/%s", r.namespace, r.binding))
+		}
+	}
+	for _, r := range w.clusterRoleBindingRows(clusterRoleBindings) {
+		if isMastersGroup(r.subject) {
+			names = append(names, fmt.Sprintf("ClusterRoleBinding %s", r.binding))
+		}
+	}
+	return names
+}
+
+func isMastersGroup(subject rbac.Subject) bool {
+	return subject.Kind == rbac.GroupKind && subject.Name == mastersGroup
+}
+
+func (w *whoCan) printMastersWarnings(bindings []string) {
+	out := w.warningsOut()
+	if len(bindings) > 0 {
+		_, _ = fmt.Fprintln(out)
+		_, _ = fmt.Fprintf(out, "Warning: Members of the %s group bypass RBAC authorization and can perform any action:\n", mastersGroup)
+		for _, binding := range bindings {
+			_, _ = fmt.Fprintf(out, "\t%s\n", binding)
+		}
+	}
+}
+
+// printHeader prints the given header row of a table followed by the headers of the optional columns,
+// unless --no-headers is set.
+func (w *whoCan) printHeader(wr io.Writer, header string) {
+	if w.noHeaders {
+		return
+	}
+	fmt.Fprintln(wr, w.headerColor()+header+w.optionalHeaders()+w.endColor())
+}
+
+// optionalHeaders returns the headers of the optional columns enabled by flags.
+func (w *whoCan) optionalHeaders() string {
+	var headers string
+	if w.wide {
+		headers += "\tROLE"
+		if w.nonResourceURL == "" {
+			headers += "\tAPIGROUP"
+		}
+	}
+	if w.showWildcard {
+		headers += "\tWILDCARD"
+	}
+	if w.showRisk {
+		headers += "\tRISK"
+	}
+	return headers
+}
+
+// optionalColumns returns the values of the optional columns enabled by flags for the given row.
+func (w *whoCan) optionalColumns(r subjectRow) string {
+	var columns string
+	if w.wide {
+		columns += "\t" + r.roleRef.Kind + "/" + r.roleRef.Name
+		if w.nonResourceURL == "" {
+			group := w.apiGroup
+			if w.resource == rbac.ResourceAll {
+				group = rbac.APIGroupAll
+			}
+			columns += "\t" + displayAPIGroup(group)
+		}
+	}
+	if w.showWildcard {
+		columns += fmt.Sprintf("\t%t", w.wildcards[newRoleFromRef(&r.roleRef, r.namespace)])
+	}
+	if w.showRisk {
+		columns += "\t" + w.risk(r)
+	}
+	return columns
+}
+
+// risk describes the number of rules in the role referenced by the given row,
+// and whether any of them grants a wildcard verb, resource or API group.
+func (w *whoCan) risk(r subjectRow) string {
+	rules := w.rules[newRoleFromRef(&r.roleRef, r.namespace)]
+	risk := fmt.Sprintf("%d rules", len(rules))
+	if len(rules) == 1 {
+		risk = "1 rule"
+	}
+	var notes []string
+	if hasWildcardRule(rules) {
+		notes = append(notes, "wildcard")
+	}
+	if w.aggregatedRoles.match(&r.roleRef, r.namespace) {
+		notes = append(notes, "aggregated")
+	}
+	if len(notes) > 0 {
+		risk += " (" + strings.Join(notes, ", ") + ")"
+	}
+	return risk
+}
+
+// hasWildcardRule returns `true` if any of the given rules grants all verbs, resources or API groups.
+func hasWildcardRule(rules []rbac.PolicyRule) bool {
+	for _, rule := range rules {
+		for _, values := range [][]string{rule.Verbs, rule.Resources, rule.APIGroups} {
+			for _, v := range values {
+				if v == rbac.VerbAll {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// resultPrinter writes the Result to the given writer in a structured output format.
+type resultPrinter func(out io.Writer, result Result) error
+
+// warningsInDocument lists the structured output formats whose document includes the warnings of the Result.
+// For the other formats, the warnings are written to the standard error.
+var warningsInDocument = map[string]bool{
+	outputJSON:      true,
+	outputYAML:      true,
+	outputRego:      true,
+	outputConfigMap: true,
+	outputHTML:      true,
+}
+
+// printResult writes the given Result to the standard output in the structured output format. The document is written
+// at once, so that nothing is written if it cannot be printed.
+func (w *whoCan) printResult(result Result) error {
+	printer, printsWarnings, err := w.resultPrinter()
+	if err != nil {
+		return err
+	}
+	if !printsWarnings {
+		for _, warning := range result.Warnings {
+			_, _ = fmt.Fprintf(w.ErrOut, "Warning: %s\n", warning)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := printer(&buf, result); err != nil {
+		return err
+	}
+	w.resultPrinted = true
+	_, err = w.Out.Write(buf.Bytes())
+	return err
+}
+
+// resultPrinter returns the printer of the structured output format selected by --output, and whether the printed
+// document includes the warnings of the Result. The --output-file is written in JSON unless a format is selected.
+func (w *whoCan) resultPrinter() (resultPrinter, bool, error) {
+	if spec, ok := customColumnsSpec(w.outputFormat); ok {
+		columns, err := parseCustomColumns(spec)
+		if err != nil {
+			return nil, false, err
+		}
+		return func(out io.Writer, result Result) error {
+			return w.printCustomColumns(out, result, columns)
+		}, false, nil
+	}
+	if printer, ok, err := w.templatePrinter(); ok {
+		if err != nil {
+			return nil, false, err
+		}
+		return func(out io.Writer, result Result) error {
+			return w.printTemplate(out, printer, result)
+		}, true, nil
+	}
+
+	printers := map[string]resultPrinter{
+		outputJSON:      w.printJSON,
+		outputYAML:      w.printYAML,
+		outputRego:      w.printRego,
+		outputLogfmt:    w.printLogfmt,
+		outputConfigMap: w.printConfigMap,
+		outputHTML:      w.printHTML,
+		outputReviewCSV: w.printReviewCSV,
+		outputCSV:       w.printCSV,
+		outputMarkdown:  w.printMarkdown,
+		outputDOT:       w.printDOT,
+		outputMermaid:   w.printMermaid,
+		outputSARIF:     w.printSARIF,
+		outputJUnit:     w.printJUnit,
+		outputJSONLines: w.printJSONLines,
+		outputName:      w.printNames,
+	}
+	if printer, ok := printers[w.outputFormat]; ok {
+		return printer, warningsInDocument[w.outputFormat], nil
+	}
+	return w.printJSON, true, nil
+}
+
+// printJSON writes the Result in the version selected by --output-version as indented JSON.
+func (w *whoCan) printJSON(out io.Writer, result Result) error {
+	doc, err := result.convert(w.outputVersion)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling result: %v", err)
+	}
+	_, err = fmt.Fprintln(out, string(data))
+	return err
+}
+
+// printYAML writes the same document as printJSON in YAML.
+func (w *whoCan) printYAML(out io.Writer, result Result) error {
+	doc, err := result.convert(w.outputVersion)
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("marshalling result: %v", err)
+	}
+	_, err = out.Write(data)
+	return err
 }
 
 func (w *whoCan) prettyPrintAction() string {
 	if w.nonResourceURL != "" {
 		return fmt.Sprintf("%s %s", w.verb, w.nonResourceURL)
 	}
+	if len(w.resourceNames) > 0 {
+		return fmt.Sprintf("%s %s with %s of the names %s", w.verb, w.resource, w.resourceNameMatch, strings.Join(w.resourceNames, ", "))
+	}
 	name := w.resourceName
 	if name != "" {
 		name = "/" + name
@@ -3,11 +3,13 @@ package cmd
 import (
 	"bytes"
 	"errors"
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/kubernetes/fake"
 	clientTesting "k8s.io/client-go/testing"
@@ -21,8 +23,8 @@ type accessCheckerMock struct {
 	mock.Mock
 }
 
-func (m *accessCheckerMock) IsAllowedTo(verb, resource, namespace string) (bool, error) {
-	args := m.Called(verb, resource, namespace)
+func (m *accessCheckerMock) IsAllowedTo(verb, group, resource, namespace string) (bool, error) {
+	args := m.Called(verb, group, resource, namespace)
 	return args.Bool(0), args.Error(1)
 }
 
@@ -39,9 +41,19 @@ type resourceResolverMock struct {
 	mock.Mock
 }
 
-func (r *resourceResolverMock) Resolve(verb, resource, subResource string) (string, error) {
+func (r *resourceResolverMock) Resolve(verb, resource, subResource string) (ResolvedResource, error) {
 	args := r.Called(verb, resource, subResource)
-	return args.String(0), args.Error(1)
+	return args.Get(0).(ResolvedResource), args.Error(1)
+}
+
+func (r *resourceResolverMock) SupportedVerbs(resource string) (schema.GroupResource, []string, error) {
+	args := r.Called(resource)
+	return args.Get(0).(schema.GroupResource), args.Get(1).([]string), args.Error(2)
+}
+
+func (r *resourceResolverMock) ResourcesInCategory(category string) ([]ResolvedResource, error) {
+	args := r.Called(category)
+	return args.Get(0).([]ResolvedResource), args.Error(1)
 }
 
 type clientConfigMock struct {
@@ -64,6 +76,8 @@ func TestComplete(t *testing.T) {
 	type flags struct {
 		namespace     string
 		allNamespaces bool
+		apiGroup      string
+		outputFormat  string
 	}
 
 	type resolution struct {
@@ -71,16 +85,21 @@ func TestComplete(t *testing.T) {
 		resource    string
 		subResource string
 
-		result      string
-		err         error
+		result     string
+		group      string
+		namespaced bool
+		err        error
 	}
 
 	type expected struct {
-		namespace    string
-		verb         string
-		resource     string
-		resourceName string
-		err          error
+		namespace          string
+		verb               string
+		resource           string
+		apiGroup           string
+		namespacedResource bool
+		resourceName       string
+		wide               bool
+		err                error
 	}
 
 	data := []struct {
@@ -173,6 +192,183 @@ func TestComplete(t *testing.T) {
 				err: errors.New("you must specify two or three arguments: verb, resource, and optional resourceName"),
 			},
 		},
+		{
+			scenario:   "H",
+			flags:      flags{namespace: "foo"},
+			args:       []string{"get", "apis/apps/v1/deployments/my-app"},
+			resolution: &resolution{verb: "get", resource: "deployments.v1.apps", result: "deployments", group: "apps", namespaced: true},
+			expected: expected{
+				namespace:          "foo",
+				verb:               "get",
+				resource:           "deployments",
+				apiGroup:           "apps",
+				namespacedResource: true,
+				resourceName:       "my-app",
+			},
+		},
+		{
+			scenario:   "I",
+			flags:      flags{namespace: "foo"},
+			args:       []string{"list", "api/v1/pods"},
+			resolution: &resolution{verb: "list", resource: "pods.v1.", result: "pods"},
+			expected: expected{
+				namespace: "foo",
+				verb:      "list",
+				resource:  "pods",
+			},
+		},
+		{
+			scenario: "J",
+			args:     []string{"list", "apis/apps/v1"},
+			expected: expected{
+				verb: "list",
+				err:  errors.New("invalid API path \"apis/apps/v1\", expected api/VERSION/TYPE[/NAME] or apis/GROUP/VERSION/TYPE[/NAME]"),
+			},
+		},
+		{
+			scenario:   "K",
+			flags:      flags{namespace: "foo", apiGroup: "mycompany.com"},
+			args:       []string{"get", "services/db"},
+			resolution: &resolution{verb: "get", resource: "services.mycompany.com", result: "services", group: "mycompany.com"},
+			expected: expected{
+				namespace:    "foo",
+				verb:         "get",
+				resource:     "services",
+				apiGroup:     "mycompany.com",
+				resourceName: "db",
+			},
+		},
+		{
+			scenario:   "L",
+			flags:      flags{namespace: "foo", apiGroup: "core"},
+			args:       []string{"list", "services"},
+			resolution: &resolution{verb: "list", resource: "services.v1.", result: "services"},
+			expected: expected{
+				namespace: "foo",
+				verb:      "list",
+				resource:  "services",
+			},
+		},
+		{
+			scenario: "M",
+			flags:    flags{namespace: "foo", apiGroup: "apps"},
+			args:     []string{"list", "deployments.apps"},
+			expected: expected{
+				namespace: "foo",
+				verb:      "list",
+				resource:  "deployments.apps",
+				err:       errors.New("--apigroup cannot be used with the group-qualified resource \"deployments.apps\""),
+			},
+		},
+		{
+			scenario: "N",
+			flags:    flags{namespace: "foo", apiGroup: "apps"},
+			args:     []string{"get", "/logs"},
+			expected: expected{
+				namespace: "foo",
+				verb:      "get",
+				err:       errors.New("--apigroup cannot be used with a non-resource URL"),
+			},
+		},
+		{
+			scenario:   "O",
+			flags:      flags{namespace: "foo"},
+			args:       []string{"get", "secrets/team/db"},
+			resolution: &resolution{verb: "get", resource: "secrets", result: "secrets", namespaced: true},
+			expected: expected{
+				namespace:          "foo",
+				verb:               "get",
+				resource:           "secrets",
+				namespacedResource: true,
+				resourceName:       "team/db",
+			},
+		},
+		{
+			scenario:   "P",
+			flags:      flags{namespace: "foo"},
+			args:       []string{"get", "pods/log"},
+			resolution: &resolution{verb: "get", resource: "pods", result: "pods", namespaced: true},
+			expected: expected{
+				namespace:          "foo",
+				verb:               "get",
+				resource:           "pods",
+				namespacedResource: true,
+				resourceName:       "log",
+			},
+		},
+		{
+			scenario: "Q",
+			flags:    flags{namespace: "foo"},
+			args:     []string{"get", "pods/"},
+			expected: expected{
+				namespace: "foo",
+				verb:      "get",
+				resource:  "pods",
+				err:       errors.New("invalid resource \"pods/\", expected TYPE or TYPE/NAME with a non-empty NAME"),
+			},
+		},
+		{
+			scenario:   "R",
+			flags:      flags{namespace: "foo"},
+			args:       []string{"get", "secrets//db"},
+			resolution: &resolution{verb: "get", resource: "secrets", result: "secrets", namespaced: true},
+			expected: expected{
+				namespace:          "foo",
+				verb:               "get",
+				resource:           "secrets",
+				namespacedResource: true,
+				resourceName:       "/db",
+			},
+		},
+		{
+			scenario:   "S",
+			flags:      flags{namespace: "foo"},
+			args:       []string{"get", "deployments.v1.apps/web"},
+			resolution: &resolution{verb: "get", resource: "deployments.v1.apps", result: "deployments", group: "apps", namespaced: true},
+			expected: expected{
+				namespace:          "foo",
+				verb:               "get",
+				resource:           "deployments",
+				apiGroup:           "apps",
+				namespacedResource: true,
+				resourceName:       "web",
+			},
+		},
+		{
+			scenario: "T",
+			flags:    flags{namespace: "foo"},
+			args:     []string{"get", "deployments..apps"},
+			expected: expected{
+				namespace: "foo",
+				verb:      "get",
+				resource:  "deployments..apps",
+				err:       errors.New("invalid resource \"deployments..apps\", expected TYPE, TYPE.GROUP or TYPE.VERSION.GROUP"),
+			},
+		},
+		{
+			scenario: "U",
+			flags:    flags{namespace: "foo"},
+			args:     []string{"get", "deployments.v1.apps."},
+			expected: expected{
+				namespace: "foo",
+				verb:      "get",
+				resource:  "deployments.v1.apps.",
+				err:       errors.New("invalid resource \"deployments.v1.apps.\", expected TYPE, TYPE.GROUP or TYPE.VERSION.GROUP"),
+			},
+		},
+		{
+			scenario:       "Should add the wide columns to the table with the wide output format",
+			currentContext: &currentContext{namespace: "foo"},
+			flags:          flags{outputFormat: outputWide},
+			args:           []string{"list", "pods"},
+			resolution:     &resolution{verb: "list", resource: "pods", result: "pods"},
+			expected: expected{
+				namespace: "foo",
+				verb:      "list",
+				resource:  "pods",
+				wide:      true,
+			},
+		},
 	}
 
 	for _, tt := range data {
@@ -190,7 +386,7 @@ func TestComplete(t *testing.T) {
 
 			if tt.resolution != nil {
 				resourceResolver.On("Resolve", tt.resolution.verb, tt.resolution.resource, tt.resolution.subResource).
-					Return(tt.resolution.result, tt.resolution.err)
+					Return(ResolvedResource{Group: tt.resolution.group, Resource: tt.resolution.result, Namespaced: tt.resolution.namespaced}, tt.resolution.err)
 			}
 			if tt.currentContext != nil {
 				clientConfig.On("Namespace").Return(tt.currentContext.namespace, false, tt.currentContext.err)
@@ -209,6 +405,8 @@ func TestComplete(t *testing.T) {
 			// and
 			o.namespace = tt.flags.namespace
 			o.allNamespaces = tt.flags.allNamespaces
+			o.resourceGroup = tt.flags.apiGroup
+			o.outputFormat = tt.flags.outputFormat
 
 			// when
 			err := o.Complete(tt.args)
@@ -218,7 +416,10 @@ func TestComplete(t *testing.T) {
 			assert.Equal(t, tt.expected.namespace, o.namespace)
 			assert.Equal(t, tt.expected.verb, o.verb)
 			assert.Equal(t, tt.expected.resource, o.resource)
+			assert.Equal(t, tt.expected.apiGroup, o.apiGroup)
+			assert.Equal(t, tt.expected.namespacedResource, o.namespacedResource)
 			assert.Equal(t, tt.expected.resourceName, o.resourceName)
+			assert.Equal(t, tt.expected.wide, o.wide)
 
 			clientConfig.AssertExpectations(t)
 			resourceResolver.AssertExpectations(t)
@@ -239,6 +440,29 @@ func TestValidate(t *testing.T) {
 		nonResourceURL string
 		subResource    string
 		namespace      string
+		namespaceFlag  string
+		resourceName   string
+		outputFormat   string
+		outputVersion  string
+
+		resourceNameFile   string
+		resourceNameMatch  string
+		selector           string
+		verifyResourceName bool
+		showNearMisses     bool
+
+		verbs           []string
+		requireAllVerbs bool
+
+		clusterRoleGrantsSection string
+		groupBy                  string
+		colorMode                string
+		sortBy                   sortKey
+		columnsSpec              string
+		outputFile               string
+		quiet                    bool
+		score                    bool
+		namespaceSelector        string
 
 		*namespaceValidation
 
@@ -261,6 +485,211 @@ func TestValidate(t *testing.T) {
 			subResource:    "logs",
 			expectedErr:    errors.New("--subresource cannot be used with NONRESOURCEURL"),
 		},
+		{
+			scenario:       "Should return error when --namespace flag is used with non-resource URL",
+			nonResourceURL: "/healthz",
+			namespace:      "foo",
+			namespaceFlag:  "foo",
+			expectedErr:    errors.New("--namespace cannot be used with NONRESOURCEURL, which is not namespaced"),
+		},
+		{
+			scenario:            "Should return nil when non-resource URL is checked in namespace of current context",
+			nonResourceURL:      "/healthz",
+			namespace:           "foo",
+			namespaceValidation: &namespaceValidation{returnedError: nil},
+		},
+		{
+			scenario:            "Should return nil when output format and version are valid",
+			namespace:           "foo",
+			outputFormat:        "json",
+			outputVersion:       "v1alpha1",
+			namespaceValidation: &namespaceValidation{returnedError: nil},
+		},
+		{
+			scenario:         "Should return error when --resource-name-file is used with resource name",
+			resourceName:     "foo",
+			resourceNameFile: "names.txt",
+			expectedErr:      errors.New("--resource-name-file cannot be used with NONRESOURCEURL or TYPE/NAME"),
+		},
+		{
+			scenario:          "Should return error when resource name match is not supported",
+			resourceNameFile:  "names.txt",
+			resourceNameMatch: "some",
+			expectedErr:       errors.New("unsupported resource name match \"some\", expected one of [any all]"),
+		},
+		{
+			scenario:          "Should return error when --selector is used with resource name",
+			resourceName:      "foo",
+			selector:          "tier=backend",
+			resourceNameMatch: resourceNameMatchAny,
+			expectedErr:       errors.New("--selector cannot be used with NONRESOURCEURL, TYPE/NAME, the * resource or --resource-name-file"),
+		},
+		{
+			scenario:          "Should return error when --selector is used with several verbs",
+			verbs:             []string{"get", "list"},
+			selector:          "tier=backend",
+			resourceNameMatch: resourceNameMatchAny,
+			expectedErr:       errors.New("--selector cannot be used with a comma-separated VERB"),
+		},
+		{
+			scenario:          "Should return error when --selector is not a valid label selector",
+			selector:          "tier in backend",
+			resourceNameMatch: resourceNameMatchAny,
+			expectedErr:       errors.New("invalid selector \"tier in backend\": unable to parse requirement: found 'backend' expected: '('"),
+		},
+		{
+			scenario:          "Should return error when resource name match is not supported with --selector",
+			selector:          "tier=backend",
+			resourceNameMatch: "some",
+			expectedErr:       errors.New("unsupported resource name match \"some\", expected one of [any all]"),
+		},
+		{
+			scenario:           "Should return error when --verify-resource-name is used without resource name",
+			verifyResourceName: true,
+			expectedErr:        errors.New("--verify-resource-name requires TYPE/NAME"),
+		},
+		{
+			scenario:        "Should return error when --require-all-verbs is used with a single verb",
+			verbs:           []string{"get"},
+			requireAllVerbs: true,
+			expectedErr:     errors.New("--require-all-verbs requires a comma-separated VERB, such as get,list,watch"),
+		},
+		{
+			scenario:          "Should return error when resource name file is used with several verbs",
+			verbs:             []string{"get", "list"},
+			resourceNameFile:  "names.txt",
+			resourceNameMatch: resourceNameMatchAny,
+			expectedErr:       errors.New("--resource-name-file cannot be used with a comma-separated VERB"),
+		},
+		{
+			scenario:       "Should return error when --show-near-misses is used without resource name",
+			showNearMisses: true,
+			expectedErr:    errors.New("--show-near-misses requires TYPE/NAME"),
+		},
+		{
+			scenario:                 "Should return error when ClusterRole grants section is not supported",
+			clusterRoleGrantsSection: "role",
+			expectedErr:              errors.New("unsupported ClusterRole grants section \"role\", expected one of [binding-kind role-kind]"),
+		},
+		{
+			scenario:    "Should return error when group by is not supported",
+			groupBy:     "kind",
+			expectedErr: errors.New("unsupported group by \"kind\", expected one of [apigroup]"),
+		},
+		{
+			scenario:    "Should return error when sort by is not supported",
+			sortBy:      "role",
+			expectedErr: errors.New("unsupported sort by \"role\", expected one of [subject kind namespace binding]"),
+		},
+		{
+			scenario:    "Should return error when color is not supported",
+			colorMode:   "sometimes",
+			expectedErr: errors.New("unsupported color \"sometimes\", expected one of [auto always never]"),
+		},
+		{
+			scenario:       "Should return error when --group-by=apigroup is used with non-resource URL",
+			nonResourceURL: "/healthz",
+			groupBy:        groupByAPIGroup,
+			expectedErr:    errors.New("--group-by=apigroup cannot be used with NONRESOURCEURL"),
+		},
+		{
+			scenario:     "Should return error when --group-by is used with output format",
+			groupBy:      groupByAPIGroup,
+			outputFormat: "json",
+			expectedErr:  errors.New("--group-by cannot be used with --output"),
+		},
+		{
+			scenario:    "Should return error when --output-file is used without output format",
+			outputFile:  "out.json",
+			expectedErr: errors.New("--output-file requires --output"),
+		},
+		{
+			scenario:            "Should return nil when --columns is used with output format written to file",
+			namespace:           "foo",
+			columnsSpec:         "audit",
+			outputFormat:        "json",
+			outputVersion:       "v1",
+			outputFile:          "out.json",
+			namespaceValidation: &namespaceValidation{returnedError: nil},
+		},
+		{
+			scenario:          "Should return error when namespace selector is malformed",
+			namespaceSelector: "team in (payments",
+			expectedErr:       errors.New("invalid namespace selector \"team in (payments\": unable to parse requirement: found '', expected: ',' or ')'"),
+		},
+		{
+			scenario:     "Should return error when --score is used with output format",
+			score:        true,
+			outputFormat: "json",
+			expectedErr:  errors.New("--score cannot be used with --output, --columns or --group-by"),
+		},
+		{
+			scenario:    "Should return error when --score is used with --columns",
+			score:       true,
+			columnsSpec: "audit",
+			expectedErr: errors.New("--score cannot be used with --output, --columns or --group-by"),
+		},
+		{
+			scenario:     "Should return error when --quiet is used with output format",
+			quiet:        true,
+			outputFormat: "json",
+			expectedErr:  errors.New("--quiet cannot be used with --output unless --output-file is set"),
+		},
+		{
+			scenario:            "Should return nil when --quiet is used with output format written to file",
+			namespace:           "foo",
+			quiet:               true,
+			outputFormat:        "json",
+			outputVersion:       "v1",
+			outputFile:          "out.json",
+			namespaceValidation: &namespaceValidation{returnedError: nil},
+		},
+		{
+			scenario:     "Should return error when --columns is used with output format",
+			columnsSpec:  "audit",
+			outputFormat: "json",
+			expectedErr:  errors.New("--columns cannot be used with --output or --group-by"),
+		},
+		{
+			scenario:     "Should return error when output format is not supported",
+			outputFormat: "xml",
+			expectedErr:  errors.New("unsupported output format \"xml\", expected one of [json yaml raw-json rego logfmt configmap html review-csv csv markdown dot mermaid sarif junit jsonl name wide custom-columns=SPEC jsonpath=TEMPLATE jsonpath-file=FILE go-template=TEMPLATE go-template-file=FILE]"),
+		},
+		{
+			scenario:            "Should return nil when output format is JSONPath template",
+			namespace:           "foo",
+			outputFormat:        "jsonpath={.roleBindings[*].name}",
+			outputVersion:       "v1",
+			namespaceValidation: &namespaceValidation{returnedError: nil},
+		},
+		{
+			scenario:            "Should return nil when output format is custom columns",
+			namespace:           "foo",
+			outputFormat:        "custom-columns=SUBJECT:.subject.name,KIND:.subject.kind",
+			outputVersion:       "v1",
+			namespaceValidation: &namespaceValidation{returnedError: nil},
+		},
+		{
+			scenario:     "Should return error when custom columns are malformed",
+			outputFormat: "custom-columns=SUBJECT",
+			expectedErr:  errors.New("invalid custom-columns \"SUBJECT\", expected HEADER:JSONPATH pairs separated by commas"),
+		},
+		{
+			scenario:     "Should return error when Go template cannot be parsed",
+			outputFormat: "go-template={{.query",
+			expectedErr:  errors.New("invalid output template: error parsing template {{.query, template: output:1: unclosed action\n"),
+		},
+		{
+			scenario:     "Should return error when JSONPath template is missing",
+			outputFormat: "jsonpath",
+			expectedErr:  errors.New("invalid output template: template format specified but no template given"),
+		},
+		{
+			scenario:      "Should return error when output version is not supported",
+			outputFormat:  "json",
+			outputVersion: "v2",
+			expectedErr:   errors.New("unsupported output version \"v2\", expected one of [v1 v1alpha1]"),
+		},
 	}
 
 	for _, tt := range data {
@@ -273,10 +702,30 @@ func TestValidate(t *testing.T) {
 			}
 
 			o := &whoCan{
-				nonResourceURL:     tt.nonResourceURL,
-				subResource:        tt.subResource,
-				namespace:          tt.namespace,
-				namespaceValidator: namespaceValidator,
+				configFlags:              &clioptions.ConfigFlags{Namespace: &tt.namespaceFlag},
+				nonResourceURL:           tt.nonResourceURL,
+				subResource:              tt.subResource,
+				namespace:                tt.namespace,
+				resourceName:             tt.resourceName,
+				resourceNameFile:         tt.resourceNameFile,
+				resourceNameMatch:        tt.resourceNameMatch,
+				selector:                 tt.selector,
+				verifyResourceName:       tt.verifyResourceName,
+				showNearMisses:           tt.showNearMisses,
+				verbs:                    tt.verbs,
+				requireAllVerbs:          tt.requireAllVerbs,
+				clusterRoleGrantsSection: tt.clusterRoleGrantsSection,
+				groupBy:                  tt.groupBy,
+				colorMode:                tt.colorMode,
+				sortBy:                   tt.sortBy,
+				columnsSpec:              tt.columnsSpec,
+				outputFile:               tt.outputFile,
+				quiet:                    tt.quiet,
+				score:                    tt.score,
+				namespaceSelector:        tt.namespaceSelector,
+				outputFormat:             tt.outputFormat,
+				outputVersion:            tt.outputVersion,
+				namespaceValidator:       namespaceValidator,
 			}
 
 			// when
@@ -289,6 +738,126 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestWhoCan_handleError(t *testing.T) {
+	data := []struct {
+		scenario string
+
+		outputFormat  string
+		resultPrinted bool
+		err           error
+
+		output        string
+		silenceErrors bool
+	}{
+		{
+			scenario:      "Should print error as JSON object in json output format",
+			outputFormat:  outputJSON,
+			err:           errors.New("resolving resource: the server doesn't have a resource type \"foos\""),
+			output:        "{\n  \"error\": \"resolving resource: the server doesn't have a resource type \\\"foos\\\"\"\n}\n",
+			silenceErrors: true,
+		},
+		{
+			scenario: "Should leave error to standard error in default output format",
+			err:      errors.New("resolving resource: failed"),
+		},
+		{
+			scenario:      "Should leave error to standard error when result has been printed",
+			outputFormat:  outputJSON,
+			err:           errors.New("the system:masters group is bound by: ClusterRoleBinding/admins"),
+			resultPrinted: true,
+		},
+		{
+			scenario:     "Should print nothing without error",
+			outputFormat: outputJSON,
+		},
+		{
+			scenario:      "Should silence exit error in json output format",
+			outputFormat:  outputJSON,
+			err:           &ExitError{Code: ExitCodeNoSubjects, Reason: "no subjects found with permissions to get pods"},
+			silenceErrors: true,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			streams, _, out, _ := clioptions.NewTestIOStreams()
+			cmd := &cobra.Command{}
+			wc := whoCan{outputFormat: tt.outputFormat, resultPrinted: tt.resultPrinted, IOStreams: streams}
+
+			// when
+			err := wc.handleError(cmd, tt.err)
+
+			// then
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.output, out.String())
+			assert.Equal(t, tt.silenceErrors, cmd.SilenceErrors)
+		})
+	}
+}
+
+func TestWhoCan_printResolved(t *testing.T) {
+	data := []struct {
+		scenario string
+
+		showResolved       bool
+		resource           string
+		apiGroup           string
+		namespacedResource bool
+
+		output string
+	}{
+		{
+			scenario:           "Should print namespaced resource",
+			showResolved:       true,
+			resource:           "deployments",
+			apiGroup:           "apps",
+			namespacedResource: true,
+			output:             "Resolved resource: deployments.apps (namespaced)\n\n",
+		},
+		{
+			scenario:     "Should print cluster-scoped resource",
+			showResolved: true,
+			resource:     "persistentvolumes",
+			output:       "Resolved resource: persistentvolumes (cluster-scoped)\n\n",
+		},
+		{
+			scenario:     "Should print all resources",
+			showResolved: true,
+			resource:     "*",
+			output:       "Resolved resource: * (all resources)\n\n",
+		},
+		{
+			scenario:     "Should print nothing for non-resource URL",
+			showResolved: true,
+		},
+		{
+			scenario: "Should print nothing unless --show-resolved is set",
+			resource: "pods",
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			streams, _, out, _ := clioptions.NewTestIOStreams()
+			wc := whoCan{
+				showResolved:       tt.showResolved,
+				resource:           tt.resource,
+				apiGroup:           tt.apiGroup,
+				namespacedResource: tt.namespacedResource,
+				IOStreams:          streams,
+			}
+
+			// when
+			wc.printResolved()
+
+			// then
+			assert.Equal(t, tt.output, out.String())
+		})
+	}
+}
+
 func TestMatch(t *testing.T) {
 	r := make(roles, 1)
 	entry := role{
@@ -301,12 +870,12 @@ func TestMatch(t *testing.T) {
 		Kind: "Something else",
 		Name: "hello",
 	}
-	if !r.match(&rr) {
+	if !r.match(&rr, "") {
 		t.Error("Expected match")
 	}
 
 	rr.Kind = "ClusterRole"
-	if r.match(&rr) {
+	if r.match(&rr, "") {
 		t.Error("Expected no match")
 	}
 }
@@ -319,9 +888,11 @@ func TestWhoCan_checkAPIAccess(t *testing.T) {
 
 	type permission struct {
 		verb      string
+		group     string
 		resource  string
 		namespace string
 		allowed   bool
+		err       error
 	}
 
 	client := fake.NewSimpleClientset()
@@ -355,11 +926,11 @@ func TestWhoCan_checkAPIAccess(t *testing.T) {
 				// Permissions to list all namespaces
 				{verb: "list", resource: "namespaces", namespace: core.NamespaceAll, allowed: false},
 				// Permissions in the foo namespace
-				{verb: "list", resource: "roles", namespace: FooNs, allowed: true},
-				{verb: "list", resource: "rolebindings", namespace: FooNs, allowed: true},
+				{verb: "list", group: rbac.GroupName, resource: "roles", namespace: FooNs, allowed: true},
+				{verb: "list", group: rbac.GroupName, resource: "rolebindings", namespace: FooNs, allowed: true},
 				// Permissions in the bar namespace
-				{verb: "list", resource: "roles", namespace: BarNs, allowed: false},
-				{verb: "list", resource: "rolebindings", namespace: BarNs, allowed: false},
+				{verb: "list", group: rbac.GroupName, resource: "roles", namespace: BarNs, allowed: false},
+				{verb: "list", group: rbac.GroupName, resource: "rolebindings", namespace: BarNs, allowed: false},
 			},
 			expectedWarnings: []string{
 				"The user is not allowed to list namespaces",
@@ -372,13 +943,33 @@ func TestWhoCan_checkAPIAccess(t *testing.T) {
 			namespace: FooNs,
 			permissions: []permission{
 				// Permissions in the foo namespace
-				{verb: "list", resource: "roles", namespace: FooNs, allowed: true},
-				{verb: "list", resource: "rolebindings", namespace: FooNs, allowed: false},
+				{verb: "list", group: rbac.GroupName, resource: "roles", namespace: FooNs, allowed: true},
+				{verb: "list", group: rbac.GroupName, resource: "rolebindings", namespace: FooNs, allowed: false},
 			},
 			expectedWarnings: []string{
 				"The user is not allowed to list rolebindings in the foo namespace",
 			},
 		},
+		{
+			scenario:  "C",
+			namespace: core.NamespaceAll,
+			permissions: []permission{
+				// Permissions to list all namespaces
+				{verb: "list", resource: "namespaces", namespace: core.NamespaceAll, err: errors.New("api is down")},
+				// Permissions in the foo namespace
+				{verb: "list", group: rbac.GroupName, resource: "roles", namespace: FooNs, allowed: false},
+				{verb: "list", group: rbac.GroupName, resource: "rolebindings", namespace: FooNs, err: errors.New("webhook timeout")},
+				// Permissions in the bar namespace
+				{verb: "list", group: rbac.GroupName, resource: "roles", namespace: BarNs, allowed: true},
+				{verb: "list", group: rbac.GroupName, resource: "rolebindings", namespace: BarNs, allowed: false},
+			},
+			expectedWarnings: []string{
+				"Failed to check whether the user is allowed to list namespaces: api is down",
+				"The user is not allowed to list roles in the foo namespace",
+				"Failed to check whether the user is allowed to list rolebindings in the foo namespace: webhook timeout",
+				"The user is not allowed to list rolebindings in the bar namespace",
+			},
+		},
 	}
 
 	for _, tt := range data {
@@ -388,8 +979,8 @@ func TestWhoCan_checkAPIAccess(t *testing.T) {
 			resourceResolver := new(resourceResolverMock)
 			accessChecker := new(accessCheckerMock)
 			for _, prm := range tt.permissions {
-				accessChecker.On("IsAllowedTo", prm.verb, prm.resource, prm.namespace).
-					Return(prm.allowed, nil)
+				accessChecker.On("IsAllowedTo", prm.verb, prm.group, prm.resource, prm.namespace).
+					Return(prm.allowed, prm.err)
 			}
 
 			// given
@@ -417,20 +1008,120 @@ func TestWhoCan_checkAPIAccess(t *testing.T) {
 
 }
 
-func TestWhoCan_printAPIAccessWarnings(t *testing.T) {
-
-	data := []struct {
-		scenario       string
-		warnings       []string
-		expectedOutput string
-	}{
-		{
-			scenario:       "A",
-			warnings:       []string{"w1", "w2"},
-			expectedOutput: "Warning: The list might not be complete due to missing permission(s):\n\tw1\n\tw2\n\n",
+func TestWhoCan_Check_NamespaceSelector(t *testing.T) {
+	// given
+	client := fake.NewSimpleClientset(
+		&core.Namespace{ObjectMeta: meta.ObjectMeta{Name: "payments", Labels: map[string]string{"team": "payments"}}},
+		&core.Namespace{ObjectMeta: meta.ObjectMeta{Name: "billing", Labels: map[string]string{"team": "payments"}}},
+		&core.Namespace{ObjectMeta: meta.ObjectMeta{Name: "search", Labels: map[string]string{"team": "search"}}},
+		&rbac.ClusterRole{
+			ObjectMeta: meta.ObjectMeta{Name: "view-pods"},
+			Rules:      []rbac.PolicyRule{{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"pods"}}},
 		},
-		{
-			scenario:       "B",
+		&rbac.RoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "Alice-can-view-pods", Namespace: "payments"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "view-pods"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "Alice"}},
+		},
+		&rbac.RoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "Bob-can-view-pods", Namespace: "search"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "view-pods"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "Bob"}},
+		},
+	)
+	accessChecker := new(accessCheckerMock)
+	accessChecker.On("IsAllowedTo", "list", "", "namespaces", "").Return(true, nil)
+	for _, resource := range namespacedAPIAccess {
+		accessChecker.On("IsAllowedTo", "list", rbac.GroupName, resource, "payments").Return(true, nil)
+		accessChecker.On("IsAllowedTo", "list", rbac.GroupName, resource, "billing").Return(false, nil)
+	}
+
+	streams, _, out, _ := clioptions.NewTestIOStreams()
+	wc := whoCan{
+		verb:               "get",
+		resource:           "pods",
+		namespacedResource: true,
+		namespaceSelector:  "team=payments",
+		clientNamespace:    client.CoreV1().Namespaces(),
+		clientRBAC:         client.RbacV1(),
+		accessChecker:      accessChecker,
+		IOStreams:          streams,
+	}
+
+	// when
+	err := wc.Check()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, `Warning: The list might not be complete due to missing permission(s):
+	The user is not allowed to list roles in the billing namespace
+	The user is not allowed to list rolebindings in the billing namespace
+
+ROLEBINDING          NAMESPACE  SUBJECT  TYPE  SA-NAMESPACE
+Alice-can-view-pods  payments   Alice    User  
+
+No subjects found with permissions to get pods assigned through ClusterRoleBindings
+`, out.String())
+	accessChecker.AssertExpectations(t)
+	accessChecker.AssertNotCalled(t, "IsAllowedTo", "list", rbac.GroupName, "roles", "search")
+}
+
+func TestWhoCan_Check_DuplicateSubjects(t *testing.T) {
+	// given
+	alice := rbac.Subject{Kind: rbac.UserKind, APIGroup: rbac.GroupName, Name: "Alice"}
+	client := fake.NewSimpleClientset(
+		&rbac.ClusterRole{
+			ObjectMeta: meta.ObjectMeta{Name: "view-pods"},
+			Rules:      []rbac.PolicyRule{{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"pods"}}},
+		},
+		&rbac.RoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "Alice-can-view-pods", Namespace: "foo"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "view-pods"},
+			Subjects:   []rbac.Subject{alice, alice},
+		},
+	)
+	accessChecker := new(accessCheckerMock)
+	for _, resource := range namespacedAPIAccess {
+		accessChecker.On("IsAllowedTo", "list", rbac.GroupName, resource, "foo").Return(true, nil)
+	}
+
+	streams, _, out, _ := clioptions.NewTestIOStreams()
+	wc := whoCan{
+		verb:               "get",
+		resource:           "pods",
+		namespacedResource: true,
+		namespace:          "foo",
+		clientRBAC:         client.RbacV1(),
+		accessChecker:      accessChecker,
+		IOStreams:          streams,
+	}
+
+	// when
+	err := wc.Check()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, `ROLEBINDING          NAMESPACE  SUBJECT  TYPE  SA-NAMESPACE
+Alice-can-view-pods  foo        Alice    User  
+
+No subjects found with permissions to get pods assigned through ClusterRoleBindings
+`, out.String())
+}
+
+func TestWhoCan_printAPIAccessWarnings(t *testing.T) {
+
+	data := []struct {
+		scenario       string
+		warnings       []string
+		expectedOutput string
+	}{
+		{
+			scenario:       "A",
+			warnings:       []string{"w1", "w2"},
+			expectedOutput: "Warning: The list might not be complete due to missing permission(s):\n\tw1\n\tw2\n\n",
+		},
+		{
+			scenario:       "B",
 			warnings:       []string{},
 			expectedOutput: "",
 		},
@@ -452,6 +1143,79 @@ func TestWhoCan_printAPIAccessWarnings(t *testing.T) {
 	}
 }
 
+func TestWhoCan_mastersBindings(t *testing.T) {
+	data := []struct {
+		scenario string
+
+		roleBindings        []rbac.RoleBinding
+		clusterRoleBindings []rbac.ClusterRoleBinding
+
+		expectedBindings []string
+		expectedOutput   string
+	}{
+		{
+			scenario: "Should flag ClusterRoleBinding to system:masters group",
+			clusterRoleBindings: []rbac.ClusterRoleBinding{
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "view-pods"},
+					Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "system:masters"}},
+				},
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "masters-can-view-pods"},
+					Subjects: []rbac.Subject{
+						{Kind: rbac.UserKind, Name: "Alice"},
+						{Kind: rbac.GroupKind, Name: "system:masters"},
+					},
+				},
+			},
+			expectedBindings: []string{"ClusterRoleBinding masters-can-view-pods"},
+			expectedOutput: `
+Warning: Members of the system:masters group bypass RBAC authorization and can perform any action:
+	ClusterRoleBinding masters-can-view-pods
+`,
+		},
+		{
+			scenario: "Should flag RoleBinding to system:masters group",
+			roleBindings: []rbac.RoleBinding{
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "masters-can-view-pods", Namespace: "foo"},
+					Subjects:   []rbac.Subject{{Kind: rbac.GroupKind, Name: "system:masters"}},
+				},
+			},
+			expectedBindings: []string{"RoleBinding foo/masters-can-view-pods"},
+			expectedOutput: `
+Warning: Members of the system:masters group bypass RBAC authorization and can perform any action:
+	RoleBinding foo/masters-can-view-pods
+`,
+		},
+		{
+			scenario: "Should not flag bindings without system:masters group",
+			roleBindings: []rbac.RoleBinding{
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "admins-can-view-pods", Namespace: "foo"},
+					Subjects:   []rbac.Subject{{Kind: rbac.GroupKind, Name: "admins"}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			streams, _, out, _ := clioptions.NewTestIOStreams()
+			wc := whoCan{IOStreams: streams}
+
+			// when
+			bindings := wc.mastersBindings(tt.roleBindings, tt.clusterRoleBindings)
+			wc.printMastersWarnings(bindings)
+
+			// then
+			assert.Equal(t, tt.expectedBindings, bindings)
+			assert.Equal(t, tt.expectedOutput, out.String())
+		})
+	}
+}
+
 func TestWhoCan_policyRuleMatches(t *testing.T) {
 
 	data := []struct {
@@ -459,6 +1223,7 @@ func TestWhoCan_policyRuleMatches(t *testing.T) {
 
 		verb           string
 		resource       string
+		apiGroup       string
 		resourceName   string
 		nonResourceURL string
 
@@ -471,6 +1236,7 @@ func TestWhoCan_policyRuleMatches(t *testing.T) {
 			verb:     "get", resource: "services", resourceName: "",
 			rule: rbac.PolicyRule{
 				Verbs:     []string{"get", "list"},
+				APIGroups: []string{""},
 				Resources: []string{"services"},
 			},
 			matches: true,
@@ -480,6 +1246,7 @@ func TestWhoCan_policyRuleMatches(t *testing.T) {
 			verb:     "get", resource: "services", resourceName: "",
 			rule: rbac.PolicyRule{
 				Verbs:     []string{"get", "list"},
+				APIGroups: []string{""},
 				Resources: []string{"*"},
 			},
 			matches: true,
@@ -489,6 +1256,7 @@ func TestWhoCan_policyRuleMatches(t *testing.T) {
 			verb:     "get", resource: "services", resourceName: "",
 			rule: rbac.PolicyRule{
 				Verbs:     []string{"*"},
+				APIGroups: []string{""},
 				Resources: []string{"services"},
 			},
 			matches: true,
@@ -498,6 +1266,7 @@ func TestWhoCan_policyRuleMatches(t *testing.T) {
 			verb:     "get", resource: "services", resourceName: "mongodb",
 			rule: rbac.PolicyRule{
 				Verbs:     []string{"get", "list"},
+				APIGroups: []string{""},
 				Resources: []string{"services"},
 			},
 			matches: true,
@@ -507,6 +1276,7 @@ func TestWhoCan_policyRuleMatches(t *testing.T) {
 			verb:     "get", resource: "services", resourceName: "mongodb",
 			rule: rbac.PolicyRule{
 				Verbs:         []string{"get", "list"},
+				APIGroups:     []string{""},
 				Resources:     []string{"services"},
 				ResourceNames: []string{"mongodb", "nginx"},
 			},
@@ -517,6 +1287,7 @@ func TestWhoCan_policyRuleMatches(t *testing.T) {
 			verb:     "get", resource: "services", resourceName: "mongodb",
 			rule: rbac.PolicyRule{
 				Verbs:         []string{"get", "list"},
+				APIGroups:     []string{""},
 				Resources:     []string{"services"},
 				ResourceNames: []string{"nginx"},
 			},
@@ -527,6 +1298,7 @@ func TestWhoCan_policyRuleMatches(t *testing.T) {
 			verb:     "get", resource: "services", resourceName: "",
 			rule: rbac.PolicyRule{
 				Verbs:         []string{"get", "list"},
+				APIGroups:     []string{""},
 				Resources:     []string{"services"},
 				ResourceNames: []string{"nginx"},
 			},
@@ -537,6 +1309,7 @@ func TestWhoCan_policyRuleMatches(t *testing.T) {
 			verb:     "get", resource: "pods", resourceName: "",
 			rule: rbac.PolicyRule{
 				Verbs:     []string{"create"},
+				APIGroups: []string{""},
 				Resources: []string{"pods"},
 			},
 			matches: false,
@@ -546,6 +1319,7 @@ func TestWhoCan_policyRuleMatches(t *testing.T) {
 			verb:     "get", resource: "persistentvolumes", resourceName: "",
 			rule: rbac.PolicyRule{
 				Verbs:     []string{"get"},
+				APIGroups: []string{""},
 				Resources: []string{"pods"},
 			},
 			matches: false,
@@ -577,6 +1351,167 @@ func TestWhoCan_policyRuleMatches(t *testing.T) {
 			},
 			matches: false,
 		},
+		{
+			scenario: "M",
+			verb:     "bind", resource: "clusterroles", apiGroup: "rbac.authorization.k8s.io", resourceName: "",
+			rule: rbac.PolicyRule{
+				Verbs:     []string{"bind"},
+				APIGroups: []string{"rbac.authorization.k8s.io"},
+				Resources: []string{"clusterroles"},
+			},
+			matches: true,
+		},
+		{
+			scenario: "N",
+			verb:     "bind", resource: "clusterroles", apiGroup: "rbac.authorization.k8s.io", resourceName: "admin",
+			rule: rbac.PolicyRule{
+				Verbs:         []string{"bind"},
+				APIGroups:     []string{"rbac.authorization.k8s.io"},
+				Resources:     []string{"clusterroles"},
+				ResourceNames: []string{"admin", "view"},
+			},
+			matches: true,
+		},
+		{
+			scenario: "O",
+			verb:     "bind", resource: "clusterroles", apiGroup: "rbac.authorization.k8s.io", resourceName: "cluster-admin",
+			rule: rbac.PolicyRule{
+				Verbs:         []string{"bind"},
+				APIGroups:     []string{"rbac.authorization.k8s.io"},
+				Resources:     []string{"clusterroles"},
+				ResourceNames: []string{"admin", "view"},
+			},
+			matches: false,
+		},
+		{
+			scenario: "P",
+			verb:     "bind", resource: "clusterroles", apiGroup: "rbac.authorization.k8s.io", resourceName: "",
+			rule: rbac.PolicyRule{
+				Verbs:         []string{"bind"},
+				APIGroups:     []string{"rbac.authorization.k8s.io"},
+				Resources:     []string{"clusterroles"},
+				ResourceNames: []string{"admin"},
+			},
+			matches: false,
+		},
+		{
+			scenario: "Q",
+			verb:     "bind", resource: "clusterroles", apiGroup: "rbac.authorization.k8s.io", resourceName: "",
+			rule: rbac.PolicyRule{
+				Verbs:     []string{"get", "list"},
+				APIGroups: []string{"rbac.authorization.k8s.io"},
+				Resources: []string{"clusterroles"},
+			},
+			matches: false,
+		},
+		{
+			scenario: "R",
+			verb:     "get", nonResourceURL: "/apis/apps/v1",
+			rule: rbac.PolicyRule{
+				Verbs:           []string{"get"},
+				NonResourceURLs: []string{"/apis/*"},
+			},
+			matches: true,
+		},
+		{
+			scenario: "S",
+			verb:     "get", nonResourceURL: "/apis",
+			rule: rbac.PolicyRule{
+				Verbs:           []string{"get"},
+				NonResourceURLs: []string{"/apis/*"},
+			},
+			matches: false,
+		},
+		{
+			scenario: "T",
+			verb:     "get", nonResourceURL: "/apis/apps/v1",
+			rule: rbac.PolicyRule{
+				Verbs:           []string{"get"},
+				NonResourceURLs: []string{"/apis/apps/*"},
+			},
+			matches: true,
+		},
+		{
+			scenario: "U",
+			verb:     "get", nonResourceURL: "/api/v1",
+			rule: rbac.PolicyRule{
+				Verbs:           []string{"get"},
+				NonResourceURLs: []string{"/apis/*"},
+			},
+			matches: false,
+		},
+		{
+			scenario: "V",
+			verb:     "get", nonResourceURL: "/healthz/etcd",
+			rule: rbac.PolicyRule{
+				Verbs:           []string{"get"},
+				NonResourceURLs: []string{"*"},
+			},
+			matches: true,
+		},
+		{
+			scenario: "W",
+			verb:     "get", nonResourceURL: "/apis/apps/v1",
+			rule: rbac.PolicyRule{
+				Verbs:           []string{"get"},
+				NonResourceURLs: []string{"/apis"},
+			},
+			matches: false,
+		},
+		{
+			scenario: "X",
+			verb:     "get", nonResourceURL: "/logs/foo",
+			rule: rbac.PolicyRule{
+				Verbs:           []string{"get"},
+				NonResourceURLs: []string{"/logs/*"},
+			},
+			matches: true,
+		},
+		{
+			scenario: "Y",
+			verb:     "get", nonResourceURL: "/logs/",
+			rule: rbac.PolicyRule{
+				Verbs:           []string{"get"},
+				NonResourceURLs: []string{"/logs/*"},
+			},
+			matches: true,
+		},
+		{
+			scenario: "Z",
+			verb:     "get", nonResourceURL: "/healthz/etcd",
+			rule: rbac.PolicyRule{
+				Verbs:           []string{"get"},
+				NonResourceURLs: []string{"/*"},
+			},
+			matches: true,
+		},
+		{
+			scenario: "AA",
+			verb:     "get", nonResourceURL: "/logs",
+			rule: rbac.PolicyRule{
+				Verbs:           []string{"get"},
+				NonResourceURLs: []string{"/logs*"},
+			},
+			matches: true,
+		},
+		{
+			scenario: "AB",
+			verb:     "get", nonResourceURL: "/logsfoo",
+			rule: rbac.PolicyRule{
+				Verbs:           []string{"get"},
+				NonResourceURLs: []string{"/logs*"},
+			},
+			matches: true,
+		},
+		{
+			scenario: "AC",
+			verb:     "get", nonResourceURL: "/log",
+			rule: rbac.PolicyRule{
+				Verbs:           []string{"get"},
+				NonResourceURLs: []string{"/logs*"},
+			},
+			matches: false,
+		},
 	}
 
 	for _, tt := range data {
@@ -585,6 +1520,7 @@ func TestWhoCan_policyRuleMatches(t *testing.T) {
 			wc := whoCan{
 				verb:           tt.verb,
 				resource:       tt.resource,
+				apiGroup:       tt.apiGroup,
 				resourceName:   tt.resourceName,
 				nonResourceURL: tt.nonResourceURL,
 			}
@@ -596,6 +1532,384 @@ func TestWhoCan_policyRuleMatches(t *testing.T) {
 
 }
 
+func TestWhoCan_policyRuleMatches_subResources(t *testing.T) {
+	data := []struct {
+		scenario string
+
+		resource string
+		rule     rbac.PolicyRule
+
+		matches bool
+	}{
+		{
+			scenario: "Should match sub-resource of all resources",
+			resource: "deployments/scale",
+			rule:     rbac.PolicyRule{Verbs: []string{"update"}, APIGroups: []string{"apps"}, Resources: []string{"*/scale"}},
+			matches:  true,
+		},
+		{
+			scenario: "Should not match other sub-resource of all resources",
+			resource: "deployments/status",
+			rule:     rbac.PolicyRule{Verbs: []string{"update"}, APIGroups: []string{"apps"}, Resources: []string{"*/scale"}},
+			matches:  false,
+		},
+		{
+			scenario: "Should not match resource with sub-resource of all resources",
+			resource: "deployments",
+			rule:     rbac.PolicyRule{Verbs: []string{"update"}, APIGroups: []string{"apps"}, Resources: []string{"*/scale"}},
+			matches:  false,
+		},
+		{
+			scenario: "Should match specific sub-resource",
+			resource: "deployments/scale",
+			rule:     rbac.PolicyRule{Verbs: []string{"update"}, APIGroups: []string{"apps"}, Resources: []string{"deployments/scale"}},
+			matches:  true,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			wc := whoCan{verb: "update", resource: tt.resource, apiGroup: "apps"}
+
+			// when
+			matches := wc.policyRuleMatches(tt.rule)
+
+			// then
+			assert.Equal(t, tt.matches, matches)
+		})
+	}
+}
+
+func TestWhoCan_policyRuleMatches_apiGroups(t *testing.T) {
+	data := []struct {
+		scenario string
+
+		resource string
+		apiGroup string
+		rule     rbac.PolicyRule
+
+		matches bool
+	}{
+		{
+			scenario: "Should match wildcard API group with specific resource",
+			resource: "deployments", apiGroup: "apps",
+			rule:    rbac.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"*"}, Resources: []string{"deployments"}},
+			matches: true,
+		},
+		{
+			scenario: "Should match specific API group with specific resource",
+			resource: "deployments", apiGroup: "apps",
+			rule:    rbac.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}},
+			matches: true,
+		},
+		{
+			scenario: "Should match specific API group among others",
+			resource: "deployments", apiGroup: "apps",
+			rule:    rbac.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"extensions", "apps"}, Resources: []string{"deployments"}},
+			matches: true,
+		},
+		{
+			scenario: "Should match specific API group with wildcard resource",
+			resource: "deployments", apiGroup: "apps",
+			rule:    rbac.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"apps"}, Resources: []string{"*"}},
+			matches: true,
+		},
+		{
+			scenario: "Should match wildcard API group with wildcard resource",
+			resource: "deployments", apiGroup: "apps",
+			rule:    rbac.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"*"}, Resources: []string{"*"}},
+			matches: true,
+		},
+		{
+			scenario: "Should not match wrong API group with matching resource",
+			resource: "deployments", apiGroup: "apps",
+			rule:    rbac.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"extensions"}, Resources: []string{"deployments"}},
+			matches: false,
+		},
+		{
+			scenario: "Should not match core API group with resource of named API group",
+			resource: "deployments", apiGroup: "apps",
+			rule:    rbac.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"deployments"}},
+			matches: false,
+		},
+		{
+			scenario: "Should not match matching API group with other resource",
+			resource: "deployments", apiGroup: "apps",
+			rule:    rbac.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"apps"}, Resources: []string{"statefulsets"}},
+			matches: false,
+		},
+		{
+			scenario: "Should not match rule without API groups",
+			resource: "deployments", apiGroup: "apps",
+			rule:    rbac.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{}, Resources: []string{"deployments"}},
+			matches: false,
+		},
+		{
+			scenario: "Should match core API group with core resource",
+			resource: "pods", apiGroup: "",
+			rule:    rbac.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods"}},
+			matches: true,
+		},
+		{
+			scenario: "Should match wildcard API group with core resource",
+			resource: "pods", apiGroup: "",
+			rule:    rbac.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"*"}, Resources: []string{"pods"}},
+			matches: true,
+		},
+		{
+			scenario: "Should not match named API group with core resource",
+			resource: "pods", apiGroup: "",
+			rule:    rbac.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"apps"}, Resources: []string{"pods"}},
+			matches: false,
+		},
+		{
+			scenario: "Should match first of several API groups",
+			resource: "deployments", apiGroup: "apps",
+			rule:    rbac.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"apps", "extensions"}, Resources: []string{"deployments"}},
+			matches: true,
+		},
+		{
+			scenario: "Should match second of several API groups",
+			resource: "deployments", apiGroup: "extensions",
+			rule:    rbac.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"apps", "extensions"}, Resources: []string{"deployments"}},
+			matches: true,
+		},
+		{
+			scenario: "Should not match API group missing from several API groups",
+			resource: "deployments", apiGroup: "example.com",
+			rule:    rbac.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"apps", "extensions"}, Resources: []string{"deployments"}},
+			matches: false,
+		},
+		{
+			scenario: "Should match core API group among several API groups",
+			resource: "pods", apiGroup: "",
+			rule:    rbac.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"apps", ""}, Resources: []string{"pods", "deployments"}},
+			matches: true,
+		},
+		{
+			scenario: "Should match named API group listed with core API group",
+			resource: "deployments", apiGroup: "apps",
+			rule:    rbac.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"", "apps"}, Resources: []string{"pods", "deployments"}},
+			matches: true,
+		},
+		{
+			scenario: "Should not match core resource with several named API groups",
+			resource: "pods", apiGroup: "",
+			rule:    rbac.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"apps", "extensions"}, Resources: []string{"pods"}},
+			matches: false,
+		},
+		{
+			scenario: "Should match any API group when querying all resources",
+			resource: "*", apiGroup: "",
+			rule:    rbac.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"apps"}, Resources: []string{"*"}},
+			matches: true,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			wc := whoCan{verb: "get", resource: tt.resource, apiGroup: tt.apiGroup}
+
+			assert.Equal(t, tt.matches, wc.policyRuleMatches(tt.rule))
+		})
+	}
+}
+
+func TestWhoCan_filterClusterRoles_wildcards(t *testing.T) {
+	// given
+	wc := whoCan{verb: "get", resource: "pods"}
+	wc.r = make(roles)
+	wc.rules = make(map[role][]rbac.PolicyRule)
+	wc.wildcards = make(map[role]bool)
+
+	roles := &rbac.ClusterRoleList{
+		Items: []rbac.ClusterRole{
+			{
+				ObjectMeta: meta.ObjectMeta{Name: "explicit"},
+				Rules: []rbac.PolicyRule{
+					{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"pods"}},
+				},
+			},
+			{
+				ObjectMeta: meta.ObjectMeta{Name: "wildcard-verb"},
+				Rules: []rbac.PolicyRule{
+					{APIGroups: []string{""}, Verbs: []string{"*"}, Resources: []string{"pods"}},
+				},
+			},
+			{
+				ObjectMeta: meta.ObjectMeta{Name: "wildcard-resource"},
+				Rules: []rbac.PolicyRule{
+					{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"*"}},
+				},
+			},
+			{
+				ObjectMeta: meta.ObjectMeta{Name: "wildcard-group"},
+				Rules: []rbac.PolicyRule{
+					{APIGroups: []string{"*"}, Verbs: []string{"get"}, Resources: []string{"pods"}},
+				},
+			},
+			{
+				ObjectMeta: meta.ObjectMeta{Name: "wildcard-and-explicit"},
+				Rules: []rbac.PolicyRule{
+					{APIGroups: []string{"*"}, Verbs: []string{"*"}, Resources: []string{"*"}},
+					{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"pods"}},
+				},
+			},
+		},
+	}
+
+	// when
+	wc.filterClusterRoles(roles)
+
+	// then
+	assert.Equal(t, map[role]bool{
+		{name: "explicit", isClusterRole: true}:              false,
+		{name: "wildcard-verb", isClusterRole: true}:         true,
+		{name: "wildcard-resource", isClusterRole: true}:     true,
+		{name: "wildcard-group", isClusterRole: true}:        true,
+		{name: "wildcard-and-explicit", isClusterRole: true}: false,
+	}, wc.wildcards)
+}
+
+func TestWhoCan_filterClusterRoles_aggregated(t *testing.T) {
+	// given
+	wc := whoCan{
+		verb:      "get",
+		resource:  "pods",
+		r:         make(map[role]struct{}),
+		rules:     make(map[role][]rbac.PolicyRule),
+		wildcards: make(map[role]bool),
+	}
+	rule := rbac.PolicyRule{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"pods"}}
+	clusterRoles := &rbac.ClusterRoleList{
+		Items: []rbac.ClusterRole{
+			{
+				ObjectMeta: meta.ObjectMeta{Name: "view"},
+				AggregationRule: &rbac.AggregationRule{ClusterRoleSelectors: []meta.LabelSelector{
+					{MatchLabels: map[string]string{"rbac.authorization.k8s.io/aggregate-to-view": "true"}},
+				}},
+				Rules: []rbac.PolicyRule{rule},
+			},
+			{
+				ObjectMeta: meta.ObjectMeta{Name: "pod-reader"},
+				Rules:      []rbac.PolicyRule{rule},
+			},
+		},
+	}
+
+	// when
+	wc.filterClusterRoles(clusterRoles)
+
+	// then
+	assert.Equal(t, roles{{name: "view", isClusterRole: true}: {}}, wc.aggregatedRoles)
+	assert.Equal(t, "1 rule (aggregated)", wc.risk(subjectRow{roleRef: rbac.RoleRef{Kind: "ClusterRole", Name: "view"}}))
+	assert.Equal(t, "1 rule", wc.risk(subjectRow{roleRef: rbac.RoleRef{Kind: "ClusterRole", Name: "pod-reader"}}))
+}
+
+func TestWhoCan_getBindings_RoleBindingReferencingClusterRole(t *testing.T) {
+	// given
+	client := fake.NewSimpleClientset(
+		&rbac.ClusterRole{
+			ObjectMeta: meta.ObjectMeta{Name: "view"},
+			Rules: []rbac.PolicyRule{
+				{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"pods"}},
+			},
+		},
+		&rbac.RoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "Alice-can-view", Namespace: "foo"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "view"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "Alice"}},
+		},
+		&rbac.ClusterRoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "Bob-can-view"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "view"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "Bob"}},
+		},
+	)
+	wc := whoCan{
+		verb:       "get",
+		resource:   "pods",
+		namespace:  "foo",
+		clientRBAC: client.RbacV1(),
+		rules:      make(map[role][]rbac.PolicyRule),
+		wildcards:  make(map[role]bool),
+	}
+
+	// when
+	roleBindings, clusterRoleBindings, err := wc.getBindings()
+
+	// then
+	assert.NoError(t, err)
+	if assert.Len(t, roleBindings, 1) {
+		assert.Equal(t, "Alice-can-view", roleBindings[0].Name)
+	}
+	if assert.Len(t, clusterRoleBindings, 1) {
+		assert.Equal(t, "Bob-can-view", clusterRoleBindings[0].Name)
+	}
+}
+
+func TestWhoCan_getBindings_ResourceNameInAllNamespaces(t *testing.T) {
+	// given
+	readerOf := func(namespace string, names ...string) *rbac.Role {
+		return &rbac.Role{
+			ObjectMeta: meta.ObjectMeta{Name: "service-reader", Namespace: namespace},
+			Rules: []rbac.PolicyRule{
+				{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"services"}, ResourceNames: names},
+			},
+		}
+	}
+	bindingIn := func(namespace string, roleRef rbac.RoleRef) *rbac.RoleBinding {
+		return &rbac.RoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "readers", Namespace: namespace},
+			RoleRef:    roleRef,
+			Subjects:   []rbac.Subject{{Kind: rbac.GroupKind, Name: namespace + "-readers"}},
+		}
+	}
+	serviceReader := rbac.RoleRef{Kind: "Role", Name: "service-reader"}
+	client := fake.NewSimpleClientset(
+		readerOf("foo", "mongodb"),
+		readerOf("bar", "nginx"),
+		readerOf("baz"),
+		&rbac.ClusterRole{
+			ObjectMeta: meta.ObjectMeta{Name: "mongodb-reader"},
+			Rules: []rbac.PolicyRule{
+				{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"services"}, ResourceNames: []string{"mongodb"}},
+			},
+		},
+		bindingIn("foo", serviceReader),
+		bindingIn("bar", serviceReader),
+		bindingIn("baz", serviceReader),
+		bindingIn("qux", rbac.RoleRef{Kind: "ClusterRole", Name: "mongodb-reader"}),
+	)
+	wc := whoCan{
+		verb:           "get",
+		resource:       "services",
+		resourceName:   "mongodb",
+		namespace:      meta.NamespaceAll,
+		showNearMisses: true,
+		clientRBAC:     client.RbacV1(),
+		rules:          make(map[role][]rbac.PolicyRule),
+		wildcards:      make(map[role]bool),
+		nearMisses:     make(map[role][]string),
+	}
+
+	// when
+	roleBindings, clusterRoleBindings, err := wc.getBindings()
+
+	// then
+	assert.NoError(t, err)
+	var namespaces []string
+	for _, rb := range roleBindings {
+		namespaces = append(namespaces, rb.Namespace)
+	}
+	assert.ElementsMatch(t, []string{"foo", "baz", "qux"}, namespaces)
+	assert.Empty(t, clusterRoleBindings)
+	if assert.Len(t, wc.nearMissRoleBindings, 1) {
+		assert.Equal(t, "bar", wc.nearMissRoleBindings[0].Namespace)
+	}
+}
+
 func TestWhoCan_output(t *testing.T) {
 	data := []struct {
 		scenario string
@@ -608,6 +1922,19 @@ func TestWhoCan_output(t *testing.T) {
 		roleBindings        []rbac.RoleBinding
 		clusterRoleBindings []rbac.ClusterRoleBinding
 
+		showRisk     bool
+		rules        map[role][]rbac.PolicyRule
+		showWildcard bool
+		wildcards    map[role]bool
+		wide         bool
+		apiGroup     string
+		noHeaders    bool
+
+		clusterRoleGrantsSection string
+
+		subject     string
+		subjectKind string
+
 		output string
 	}{
 		{
@@ -662,6 +1989,239 @@ Admins-can-view-pods  bar        Admins   Group
 CLUSTERROLEBINDING         SUBJECT  TYPE            SA-NAMESPACE
 Bob-and-Eve-can-view-pods  Bob      ServiceAccount  foo
 Bob-and-Eve-can-view-pods  Eve      User            
+`,
+		},
+		{
+			scenario: "E",
+			verb:     "get", resource: "pods",
+			showRisk: true,
+			rules: map[role][]rbac.PolicyRule{
+				{name: "view-pods", isClusterRole: false, namespace: "default"}: {
+					{Verbs: []string{"get"}, Resources: []string{"pods"}},
+				},
+				{name: "cluster-admin", isClusterRole: true}: {
+					{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}},
+					{Verbs: []string{"*"}, NonResourceURLs: []string{"*"}},
+				},
+			},
+			roleBindings: []rbac.RoleBinding{
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "Alice-can-view-pods", Namespace: "default"},
+					RoleRef:    rbac.RoleRef{Kind: "Role", Name: "view-pods"},
+					Subjects: []rbac.Subject{
+						{Name: "Alice", Kind: "User"},
+					}},
+			},
+			clusterRoleBindings: []rbac.ClusterRoleBinding{
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "Bob-is-admin"},
+					RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+					Subjects: []rbac.Subject{
+						{Name: "Bob", Kind: "User"},
+					},
+				},
+			},
+			output: `ROLEBINDING          NAMESPACE  SUBJECT  TYPE  SA-NAMESPACE  RISK
+Alice-can-view-pods  default    Alice    User                1 rule
+
+CLUSTERROLEBINDING  SUBJECT  TYPE  SA-NAMESPACE  RISK
+Bob-is-admin        Bob      User                2 rules (wildcard)
+`,
+		},
+		{
+			scenario: "F",
+			verb:     "get", resource: "pods",
+			showWildcard: true,
+			wildcards: map[role]bool{
+				{name: "view-pods", isClusterRole: false, namespace: "default"}: false,
+				{name: "cluster-admin", isClusterRole: true}:                    true,
+			},
+			roleBindings: []rbac.RoleBinding{
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "Alice-can-view-pods", Namespace: "default"},
+					RoleRef:    rbac.RoleRef{Kind: "Role", Name: "view-pods"},
+					Subjects: []rbac.Subject{
+						{Name: "Alice", Kind: "User"},
+					}},
+			},
+			clusterRoleBindings: []rbac.ClusterRoleBinding{
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "Bob-is-admin"},
+					RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+					Subjects: []rbac.Subject{
+						{Name: "Bob", Kind: "User"},
+					},
+				},
+			},
+			output: `ROLEBINDING          NAMESPACE  SUBJECT  TYPE  SA-NAMESPACE  WILDCARD
+Alice-can-view-pods  default    Alice    User                false
+
+CLUSTERROLEBINDING  SUBJECT  TYPE  SA-NAMESPACE  WILDCARD
+Bob-is-admin        Bob      User                true
+`,
+		},
+		{
+			scenario: "G",
+			verb:     "get", resource: "pods",
+			clusterRoleGrantsSection: clusterRoleGrantsByBindingKind,
+			roleBindings: []rbac.RoleBinding{
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "Alice-can-view-pods", Namespace: "default"},
+					RoleRef:    rbac.RoleRef{Kind: "Role", Name: "view-pods"},
+					Subjects: []rbac.Subject{
+						{Name: "Alice", Kind: "User"},
+					}},
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "Carol-can-view", Namespace: "bar"},
+					RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "view"},
+					Subjects: []rbac.Subject{
+						{Name: "Carol", Kind: "User"},
+					}},
+			},
+			clusterRoleBindings: []rbac.ClusterRoleBinding{
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "Bob-can-view"},
+					RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "view"},
+					Subjects: []rbac.Subject{
+						{Name: "Bob", Kind: "User"},
+					},
+				},
+			},
+			output: `ROLEBINDING          NAMESPACE  SUBJECT  TYPE  SA-NAMESPACE
+Alice-can-view-pods  default    Alice    User  
+Carol-can-view       bar        Carol    User  
+
+CLUSTERROLEBINDING  SUBJECT  TYPE  SA-NAMESPACE
+Bob-can-view        Bob      User  
+`,
+		},
+		{
+			scenario: "H",
+			verb:     "get", resource: "pods",
+			clusterRoleGrantsSection: clusterRoleGrantsByRoleKind,
+			roleBindings: []rbac.RoleBinding{
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "Alice-can-view-pods", Namespace: "default"},
+					RoleRef:    rbac.RoleRef{Kind: "Role", Name: "view-pods"},
+					Subjects: []rbac.Subject{
+						{Name: "Alice", Kind: "User"},
+					}},
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "Carol-can-view", Namespace: "bar"},
+					RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "view"},
+					Subjects: []rbac.Subject{
+						{Name: "Carol", Kind: "User"},
+					}},
+			},
+			clusterRoleBindings: []rbac.ClusterRoleBinding{
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "Bob-can-view"},
+					RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "view"},
+					Subjects: []rbac.Subject{
+						{Name: "Bob", Kind: "User"},
+					},
+				},
+			},
+			output: `ROLEBINDING          NAMESPACE  SUBJECT  TYPE  SA-NAMESPACE
+Alice-can-view-pods  default    Alice    User  
+
+BINDING                          NAMESPACE  SUBJECT  TYPE  SA-NAMESPACE
+RoleBinding/Carol-can-view       bar        Carol    User  
+ClusterRoleBinding/Bob-can-view             Bob      User  
+`,
+		},
+		{
+			scenario: "I",
+			verb:     "get", resource: "pods",
+			clusterRoleGrantsSection: clusterRoleGrantsByRoleKind,
+			output: `No subjects found with permissions to get pods assigned through Roles
+
+No subjects found with permissions to get pods assigned through ClusterRoles
+`,
+		},
+		{
+			scenario: "J",
+			verb:     "get", resource: "pods",
+			subject: "foo:ci-deployer", subjectKind: rbac.ServiceAccountKind,
+			roleBindings: []rbac.RoleBinding{
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "deployers-can-view-pods", Namespace: "foo"},
+					Subjects: []rbac.Subject{
+						{Name: "ci-deployer", Kind: "ServiceAccount"},
+						{Name: "ci-deployer", Kind: "User"},
+						{Name: "Alice", Kind: "User"},
+					}},
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "Admins-can-view-pods", Namespace: "bar"},
+					Subjects: []rbac.Subject{
+						{Name: "Admins", Kind: "Group"},
+						{Name: "ci-deployer", Kind: "ServiceAccount"},
+					}},
+			},
+			clusterRoleBindings: []rbac.ClusterRoleBinding{
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "Bob-can-view-pods"},
+					Subjects: []rbac.Subject{
+						{Name: "Bob", Kind: "User"},
+					},
+				},
+			},
+			output: `ROLEBINDING              NAMESPACE  SUBJECT      TYPE            SA-NAMESPACE
+deployers-can-view-pods  foo        ci-deployer  ServiceAccount  
+
+No subjects found with permissions to get pods assigned through ClusterRoleBindings
+`,
+		},
+		{
+			scenario: "K",
+			verb:     "get", resource: "deployments", apiGroup: "apps",
+			wide: true,
+			roleBindings: []rbac.RoleBinding{
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "Alice-can-view-deployments", Namespace: "default"},
+					RoleRef:    rbac.RoleRef{Kind: "Role", Name: "view-deployments"},
+					Subjects: []rbac.Subject{
+						{Name: "Alice", Kind: "User"},
+					}},
+			},
+			clusterRoleBindings: []rbac.ClusterRoleBinding{
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "Bob-is-admin"},
+					RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+					Subjects: []rbac.Subject{
+						{Name: "Bob", Kind: "User"},
+					},
+				},
+			},
+			output: `ROLEBINDING                 NAMESPACE  SUBJECT  TYPE  SA-NAMESPACE  ROLE                   APIGROUP
+Alice-can-view-deployments  default    Alice    User                Role/view-deployments  apps
+
+CLUSTERROLEBINDING  SUBJECT  TYPE  SA-NAMESPACE  ROLE                       APIGROUP
+Bob-is-admin        Bob      User                ClusterRole/cluster-admin  apps
+`,
+		},
+		{
+			scenario: "L",
+			verb:     "get", resource: "pods",
+			noHeaders: true,
+			roleBindings: []rbac.RoleBinding{
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "Alice-can-view-pods", Namespace: "default"},
+					Subjects: []rbac.Subject{
+						{Name: "Alice", Kind: "User"},
+					}},
+			},
+			clusterRoleBindings: []rbac.ClusterRoleBinding{
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "Bob-can-view-pods"},
+					Subjects: []rbac.Subject{
+						{Name: "Bob", Kind: "User"},
+					},
+				},
+			},
+			output: `Alice-can-view-pods  default  Alice  User  
+
+Bob-can-view-pods  Bob  User  
 `,
 		},
 	}
@@ -675,12 +2235,23 @@ Bob-and-Eve-can-view-pods  Eve      User
 				resource:       tt.resource,
 				nonResourceURL: tt.nonResourceURL,
 				resourceName:   tt.resourceName,
+				showRisk:       tt.showRisk,
+				rules:          tt.rules,
+				showWildcard:   tt.showWildcard,
+				wildcards:      tt.wildcards,
+				wide:           tt.wide,
+				apiGroup:       tt.apiGroup,
+				noHeaders:      tt.noHeaders,
+
+				clusterRoleGrantsSection: tt.clusterRoleGrantsSection,
+				subject:                  tt.subject,
+				subjectKind:              tt.subjectKind,
 
 				IOStreams: streams,
 			}
 
 			// when
-			wc.output(tt.roleBindings, tt.clusterRoleBindings)
+			wc.output(wc.filterSubjects(tt.roleBindings, tt.clusterRoleBindings))
 
 			// then
 			assert.Equal(t, tt.output, out.String())
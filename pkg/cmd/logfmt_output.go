@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// printLogfmt writes one line per subject of the Result in the logfmt style of key=value pairs, such as
+// `subject=alice kind=User binding=alice-can-view binding_kind=RoleBinding namespace=default ...`,
+// which can be ingested by log-based analysis pipelines.
+func (w *whoCan) printLogfmt(out io.Writer, result Result) error {
+	var buf bytes.Buffer
+	result.forEachBinding(func(bindingKind string, b Binding) {
+		for _, s := range b.Subjects {
+			writeLogfmt(&buf, [][2]string{
+				{"subject", s.Name},
+				{"kind", s.Kind},
+				{"sa_namespace", s.Namespace},
+				{"binding", b.Name},
+				{"binding_kind", bindingKind},
+				{"namespace", b.Namespace},
+				{"role", b.RoleRef.Name},
+				{"role_kind", b.RoleRef.Kind},
+				{"verb", result.Query.Verb},
+				{"resource", result.Query.Resource},
+				{"resource_name", result.Query.ResourceName},
+				{"non_resource_url", result.Query.NonResourceURL},
+			})
+		}
+	})
+	_, err := out.Write(buf.Bytes())
+	return err
+}
+
+// writeLogfmt writes the given key-value pairs as a single logfmt line. Pairs with empty values are omitted.
+func writeLogfmt(buf *bytes.Buffer, pairs [][2]string) {
+	first := true
+	for _, pair := range pairs {
+		if pair[1] == "" {
+			continue
+		}
+		if !first {
+			buf.WriteByte(' ')
+		}
+		first = false
+		buf.WriteString(pair[0])
+		buf.WriteByte('=')
+		buf.WriteString(logfmtValue(pair[1]))
+	}
+	buf.WriteByte('\n')
+}
+
+// logfmtValue returns the value quoted if it contains spaces, equal signs, quotes or non-printable characters,
+// which would otherwise break the key=value pairs.
+func logfmtValue(value string) string {
+	needsQuoting := strings.IndexFunc(value, func(r rune) bool {
+		return r <= ' ' || r == '=' || r == '"' || r == '\\' || !unicode.IsPrint(r)
+	}) >= 0
+	if needsQuoting {
+		return strconv.Quote(value)
+	}
+	return value
+}
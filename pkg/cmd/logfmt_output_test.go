@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"testing"
+)
+
+func TestWhoCan_printLogfmt(t *testing.T) {
+	// given
+	roleBindings := []rbac.RoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Alice-can-view-pods", Namespace: "default"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "view-pods"},
+			Subjects: []rbac.Subject{
+				{Name: "Alice Smith", Kind: "User"},
+				{Name: `team "ops"`, Kind: "Group"},
+			},
+		},
+	}
+	clusterRoleBindings := []rbac.ClusterRoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Bob-can-view-pods"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "view"},
+			Subjects: []rbac.Subject{
+				{Name: "Bob", Kind: "ServiceAccount", Namespace: "foo"},
+			},
+		},
+	}
+
+	streams, _, out, _ := clioptions.NewTestIOStreams()
+	wc := whoCan{
+		verb:         "get",
+		resource:     "pods",
+		namespace:    "default",
+		outputFormat: outputLogfmt,
+		IOStreams:    streams,
+	}
+
+	// when
+	err := wc.printResult(wc.newResult(roleBindings, clusterRoleBindings, []string{"list roles"}))
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, `subject="Alice Smith" kind=User binding=Alice-can-view-pods binding_kind=RoleBinding namespace=default role=view-pods role_kind=Role verb=get resource=pods
+subject="team \"ops\"" kind=Group binding=Alice-can-view-pods binding_kind=RoleBinding namespace=default role=view-pods role_kind=Role verb=get resource=pods
+subject=Bob kind=ServiceAccount sa_namespace=foo binding=Bob-can-view-pods binding_kind=ClusterRoleBinding role=view role_kind=ClusterRole verb=get resource=pods
+`, out.String())
+}
+
+func TestLogfmtValue(t *testing.T) {
+	data := []struct {
+		scenario string
+		value    string
+		expected string
+	}{
+		{scenario: "Should keep plain value", value: "system:serviceaccount:foo:bar", expected: "system:serviceaccount:foo:bar"},
+		{scenario: "Should quote value with spaces", value: "Alice Smith", expected: `"Alice Smith"`},
+		{scenario: "Should quote value with equal sign", value: "a=b", expected: `"a=b"`},
+		{scenario: "Should escape quotes and backslashes", value: `a"b\c`, expected: `"a\"b\\c"`},
+		{scenario: "Should escape control characters", value: "a\nb", expected: `"a\nb"`},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			assert.Equal(t, tt.expected, logfmtValue(tt.value))
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// markdownEscaper escapes the characters which would otherwise break a cell of a Markdown table.
+var markdownEscaper = strings.NewReplacer("|", "\\|", "\n", " ")
+
+// printMarkdown writes the Result as Markdown, with a table of the subjects for the RoleBinding and
+// ClusterRoleBinding grants each, which can be dropped into pull requests, runbooks and audit documents.
+func (w *whoCan) printMarkdown(out io.Writer, result Result) error {
+	var buf bytes.Buffer
+	_, _ = fmt.Fprintf(&buf, "# Who can %s\n", markdownEscaper.Replace(w.prettyPrintAction()))
+	section := func(title string, bindings []Binding, namespaced bool) {
+		_, _ = fmt.Fprintf(&buf, "\n## %s\n\n", title)
+		var rows [][]string
+		for _, b := range bindings {
+			for _, s := range b.Subjects {
+				row := []string{b.Name}
+				if namespaced {
+					row = append(row, b.Namespace)
+				}
+				rows = append(rows, append(row, s.Name, s.Kind, s.Namespace))
+			}
+		}
+		if len(rows) == 0 {
+			buf.WriteString("No subjects found.\n")
+			return
+		}
+		header := []string{"Binding"}
+		if namespaced {
+			header = append(header, "Namespace")
+		}
+		header = append(header, "Subject", "Type", "SA Namespace")
+		writeMarkdownRow(&buf, header)
+		separator := make([]string, len(header))
+		for i := range separator {
+			separator[i] = "---"
+		}
+		writeMarkdownRow(&buf, separator)
+		for _, row := range rows {
+			writeMarkdownRow(&buf, row)
+		}
+	}
+	section("Granted through RoleBindings", result.RoleBindings, true)
+	section("Granted through ClusterRoleBindings", result.ClusterRoleBindings, false)
+
+	_, err := out.Write(buf.Bytes())
+	return err
+}
+
+// writeMarkdownRow writes the given cells as a row of a Markdown table.
+func writeMarkdownRow(buf *bytes.Buffer, cells []string) {
+	buf.WriteString("|")
+	for _, cell := range cells {
+		buf.WriteString(" ")
+		buf.WriteString(markdownEscaper.Replace(cell))
+		buf.WriteString(" |")
+	}
+	buf.WriteString("\n")
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"testing"
+)
+
+func TestWhoCan_printMarkdown(t *testing.T) {
+	// given
+	roleBindings := []rbac.RoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Alice-can-view-pods", Namespace: "default"},
+			Subjects: []rbac.Subject{
+				{Name: "Alice", Kind: "User"},
+				{Name: "dev|ops", Kind: "Group"},
+				{Name: "bar", Kind: "ServiceAccount", Namespace: "foo"},
+			},
+		},
+	}
+
+	streams, _, out, _ := clioptions.NewTestIOStreams()
+	wc := whoCan{
+		verb:         "get",
+		resource:     "pods",
+		outputFormat: outputMarkdown,
+		IOStreams:    streams,
+	}
+
+	// when
+	err := wc.printResult(wc.newResult(roleBindings, nil, []string{"list roles"}))
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, `# Who can get pods
+
+## Granted through RoleBindings
+
+| Binding | Namespace | Subject | Type | SA Namespace |
+| --- | --- | --- | --- | --- |
+| Alice-can-view-pods | default | Alice | User |  |
+| Alice-can-view-pods | default | dev\|ops | Group |  |
+| Alice-can-view-pods | default | bar | ServiceAccount | foo |
+
+## Granted through ClusterRoleBindings
+
+No subjects found.
+`, out.String())
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	rbac "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	matrixUsage = `matrix (TYPE [--subjects] | --subject KIND/NAME)`
+	matrixLong  = `Shows the verbs supported by a resource type and whether the current user is allowed to perform each of them.
+
+The verbs are the ones advertised by the API discovery and the ones enforced by the RBAC authorizer only, such as
+'bind' and 'escalate' for roles. Each of them can be checked with 'kubectl who-can VERB TYPE'. Additionally, the user
+is warned if they are not allowed to list the RBAC objects, so that such checks might be incomplete.
+
+With --subjects, instead shows the verbs supported by the resource type granted to each subject of the RoleBindings
+and ClusterRoleBindings as a grid with a row per subject and scope and a column per verb. The scope is the namespace
+of the RoleBindings or cluster-wide for ClusterRoleBindings. A verb marked with 'x' is granted, and a verb marked with
+'~' is granted on named objects of the resource only.
+
+With --subject, instead shows the verbs granted to the subject as a grid with a row per namespace and a column per
+resource named by the rules granted to the subject, either directly or through a group which the subject implicitly
+belongs to. A verb prefixed with '~' is granted on named objects of the resource only. KIND is one of 'User', 'Group'
+or 'ServiceAccount'. The NAME of a ServiceAccount is given as NAMESPACE:NAME. With --all-namespaces the namespaces
+are printed in pages of --page-size namespaces.`
+	matrixExample = `  # List the verbs supported by pods and which of them the current user is allowed to perform in the current namespace
+  kubectl who-can matrix pods
+
+  # List the verbs supported by deployments and which of them the current user is allowed to perform in all namespaces
+  kubectl who-can matrix deployments.apps --all-namespaces
+
+  # List the verbs supported by secrets which each subject is granted in the namespace "foo"
+  kubectl who-can matrix secrets --subjects -n foo
+
+  # List the verbs granted to the user "jane" on each resource in all namespaces, showing the second page of namespaces
+  kubectl who-can matrix --subject User/jane --all-namespaces --page 2`
+)
+
+type matrixWhoCan struct {
+	resource      string
+	namespace     string
+	allNamespaces bool
+
+	// subjects prints the verbs granted to each subject of the bindings rather than to the current user.
+	subjects bool
+	// subject is given as KIND/NAME to print the verbs granted to it rather than to the current user.
+	subject  string
+	page     int
+	pageSize int
+
+	configFlags      *clioptions.ConfigFlags
+	client           kubernetes.Interface
+	clientConfig     clientcmd.ClientConfig
+	resourceResolver ResourceResolver
+	accessChecker    AccessChecker
+
+	clioptions.IOStreams
+}
+
+// verbAccess tells whether the current user is allowed to perform a verb.
+type verbAccess struct {
+	verb    string
+	allowed bool
+	err     error
+}
+
+func NewCmdMatrix(client kubernetes.Interface, configFlags *clioptions.ConfigFlags, resourceResolver ResourceResolver, accessChecker AccessChecker, streams clioptions.IOStreams) *cobra.Command {
+	o := &matrixWhoCan{
+		configFlags:      configFlags,
+		clientConfig:     configFlags.ToRawKubeConfigLoader(),
+		client:           client,
+		resourceResolver: resourceResolver,
+		accessChecker:    accessChecker,
+		IOStreams:        streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:          matrixUsage,
+		Short:        "Show the verbs supported by a resource type and which of them the current user is allowed to perform",
+		Long:         matrixLong,
+		Example:      matrixExample,
+		SilenceUsage: true,
+		Args:         cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (o.subject == "") == (len(args) == 0) {
+				return errors.New("you must specify either TYPE or --subject")
+			}
+			if o.subjects && o.subject != "" {
+				return errors.New("--subjects cannot be used with --subject")
+			}
+			var err error
+			o.namespace, err = resolveNamespace(o.configFlags, o.clientConfig, o.allNamespaces)
+			if err != nil {
+				return err
+			}
+			if o.subject != "" {
+				return o.runSubject()
+			}
+			o.resource = args[0]
+			if o.subjects {
+				return o.runSubjects()
+			}
+			return o.run()
+		},
+	}
+
+	cmd.Flags().BoolVarP(&o.allNamespaces, "all-namespaces", "A", false,
+		"If true, check whether the current user is allowed to perform the verbs in all namespaces.")
+	cmd.Flags().BoolVar(&o.subjects, "subjects", false,
+		"If true, show the verbs of TYPE granted to each subject of the bindings instead of the current user.")
+	cmd.Flags().StringVar(&o.subject, "subject", "",
+		"If set, show the verbs granted to this subject, given as KIND/NAME such as User/jane or ServiceAccount/ci:deployer, on each resource instead of TYPE.")
+	cmd.Flags().IntVar(&o.page, "page", 1,
+		"Used with --subject. The page of namespaces to show.")
+	cmd.Flags().IntVar(&o.pageSize, "page-size", defaultMatrixPageSize,
+		"Used with --subject. The number of namespaces shown per page. Only the namespaces of the --page are matched.")
+
+	return cmd
+}
+
+func (o *matrixWhoCan) run() error {
+	groupResource, verbs, err := o.resourceResolver.SupportedVerbs(o.resource)
+	if err != nil {
+		return fmt.Errorf("resolving resource: %v", err)
+	}
+
+	access := o.checkVerbs(groupResource, verbs)
+	warnings := o.checkRBACAccess()
+
+	o.print(groupResource, access, warnings)
+	return nil
+}
+
+// checkVerbs checks whether the current user is allowed to perform each of the verbs on the resource.
+func (o *matrixWhoCan) checkVerbs(groupResource schema.GroupResource, verbs []string) []verbAccess {
+	access := make([]verbAccess, len(verbs))
+	for i, verb := range verbs {
+		allowed, err := o.accessChecker.IsAllowedTo(verb, groupResource.Group, groupResource.Resource, o.namespace)
+		access[i] = verbAccess{verb: verb, allowed: allowed, err: err}
+	}
+	return access
+}
+
+// checkRBACAccess returns the names of the RBAC resources which the current user is not allowed to list,
+// so that who-can checks of the resource might be incomplete.
+func (o *matrixWhoCan) checkRBACAccess() []string {
+	var missing []string
+	check := func(resource, namespace string) {
+		if allowed, err := o.accessChecker.IsAllowedTo("list", rbac.GroupName, resource, namespace); err != nil || !allowed {
+			missing = append(missing, "list "+resource)
+		}
+	}
+	for _, resource := range namespacedAPIAccess {
+		check(resource, o.namespace)
+	}
+	for _, resource := range clusterAPIAccess {
+		check(resource, "")
+	}
+	return missing
+}
+
+func (o *matrixWhoCan) print(groupResource schema.GroupResource, access []verbAccess, warnings []string) {
+	_, _ = fmt.Fprintf(o.Out, "Verbs supported by %s:\n\n", groupResource)
+
+	wr := new(tabwriter.Writer)
+	wr.Init(o.Out, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintln(wr, "VERB\tALLOWED")
+	for _, a := range access {
+		allowed := "no"
+		switch {
+		case a.err != nil:
+			allowed = "unknown"
+		case a.allowed:
+			allowed = "yes"
+		}
+		_, _ = fmt.Fprintf(wr, "%s\t%s\n", a.verb, allowed)
+	}
+	_ = wr.Flush()
+
+	if len(warnings) > 0 {
+		_, _ = fmt.Fprintln(o.Out)
+		_, _ = fmt.Fprintln(o.Out, "Warning: who-can checks of these verbs might not be complete due to missing permission(s):")
+		for _, warning := range warnings {
+			_, _ = fmt.Fprintf(o.Out, "\t%s\n", warning)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authz "k8s.io/api/authorization/v1"
+	rbac "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"testing"
+)
+
+func TestMatrixWhoCan_run(t *testing.T) {
+	data := []struct {
+		scenario string
+
+		allowedVerbs map[string]bool
+		verbErr      error
+		canListRBAC  bool
+
+		output string
+	}{
+		{
+			scenario:     "Should print verbs the user is allowed to perform",
+			allowedVerbs: map[string]bool{"get": true, "list": true},
+			canListRBAC:  true,
+			output: `Verbs supported by deployments.apps:
+
+VERB    ALLOWED
+get     yes
+list    yes
+delete  no
+`,
+		},
+		{
+			scenario:     "Should warn about missing permissions to list RBAC objects",
+			allowedVerbs: map[string]bool{"get": true},
+			output: `Verbs supported by deployments.apps:
+
+VERB    ALLOWED
+get     yes
+list    no
+delete  no
+
+Warning: who-can checks of these verbs might not be complete due to missing permission(s):
+	list roles
+	list rolebindings
+	list clusterroles
+	list clusterrolebindings
+`,
+		},
+		{
+			scenario:    "Should print unknown access when check fails",
+			verbErr:     errors.New("forbidden"),
+			canListRBAC: true,
+			output: `Verbs supported by deployments.apps:
+
+VERB    ALLOWED
+get     unknown
+list    unknown
+delete  unknown
+`,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			streams, _, out, _ := clioptions.NewTestIOStreams()
+			resourceResolver := new(resourceResolverMock)
+			resourceResolver.On("SupportedVerbs", "deploy").
+				Return(schema.GroupResource{Group: "apps", Resource: "deployments"}, []string{"get", "list", "delete"}, nil)
+
+			accessChecker := new(accessCheckerMock)
+			for _, verb := range []string{"get", "list", "delete"} {
+				accessChecker.On("IsAllowedTo", verb, "apps", "deployments", "foo").Return(tt.allowedVerbs[verb], tt.verbErr)
+			}
+			for _, resource := range namespacedAPIAccess {
+				accessChecker.On("IsAllowedTo", "list", rbac.GroupName, resource, "foo").Return(tt.canListRBAC, nil)
+			}
+			for _, resource := range clusterAPIAccess {
+				accessChecker.On("IsAllowedTo", "list", rbac.GroupName, resource, "").Return(tt.canListRBAC, nil)
+			}
+
+			o := &matrixWhoCan{
+				resource:         "deploy",
+				namespace:        "foo",
+				resourceResolver: resourceResolver,
+				accessChecker:    accessChecker,
+				IOStreams:        streams,
+			}
+
+			// when
+			err := o.run()
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.output, out.String())
+			accessChecker.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMatrixWhoCan_run_UnknownResource(t *testing.T) {
+	// given
+	resourceResolver := new(resourceResolverMock)
+	resourceResolver.On("SupportedVerbs", "foos").
+		Return(schema.GroupResource{}, []string(nil), &resourceNotFoundError{resource: "foos"})
+
+	o := &matrixWhoCan{
+		resource:         "foos",
+		resourceResolver: resourceResolver,
+		IOStreams:        clioptions.NewTestIOStreamsDiscard(),
+	}
+
+	// when
+	err := o.run()
+
+	// then
+	assert.EqualError(t, err, "resolving resource: the server doesn't have a resource type \"foos\"")
+}
+
+func TestMatrixWhoCan_run_AccessReviewGroups(t *testing.T) {
+	// given
+	streams, _, _, _ := clioptions.NewTestIOStreams()
+	resourceResolver := new(resourceResolverMock)
+	resourceResolver.On("SupportedVerbs", "deploy").
+		Return(schema.GroupResource{Group: "apps", Resource: "deployments"}, []string{"get"}, nil)
+
+	client := fake.NewSimpleClientset()
+	client.Fake.PrependReactor("create", "selfsubjectaccessreviews", newSelfSubjectAccessReviewsReactionFunc(true, nil))
+
+	o := &matrixWhoCan{
+		resource:         "deploy",
+		namespace:        "foo",
+		resourceResolver: resourceResolver,
+		accessChecker:    NewAccessChecker(client.AuthorizationV1().SelfSubjectAccessReviews()),
+		IOStreams:        streams,
+	}
+
+	// when
+	err := o.run()
+
+	// then
+	require.NoError(t, err)
+	var attributes []authz.ResourceAttributes
+	for _, action := range client.Actions() {
+		review := action.(clienttesting.CreateAction).GetObject().(*authz.SelfSubjectAccessReview)
+		attributes = append(attributes, *review.Spec.ResourceAttributes)
+	}
+	assert.Equal(t, []authz.ResourceAttributes{
+		{Verb: "get", Group: "apps", Resource: "deployments", Namespace: "foo"},
+		{Verb: "list", Group: rbac.GroupName, Resource: "roles", Namespace: "foo"},
+		{Verb: "list", Group: rbac.GroupName, Resource: "rolebindings", Namespace: "foo"},
+		{Verb: "list", Group: rbac.GroupName, Resource: "clusterroles"},
+		{Verb: "list", Group: rbac.GroupName, Resource: "clusterrolebindings"},
+	}, attributes)
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// rowLimit caps the number of rows printed across the sections of the output.
+type rowLimit struct {
+	// max is the maximum number of rows to print. Zero means no limit.
+	max     int
+	shown   int
+	omitted int
+}
+
+// take returns the leading rows which fit into the limit and counts the remaining rows as omitted.
+func (l *rowLimit) take(rows []subjectRow) []subjectRow {
+	if l.max <= 0 {
+		return rows
+	}
+	remaining := l.max - l.shown
+	if remaining < 0 {
+		remaining = 0
+	}
+	if len(rows) > remaining {
+		l.omitted += len(rows) - remaining
+		rows = rows[:remaining]
+	}
+	l.shown += len(rows)
+	return rows
+}
+
+func (l *rowLimit) printNotice(out io.Writer) {
+	if l.omitted > 0 {
+		_, _ = fmt.Fprintf(out, "(truncated, %d more)\n", l.omitted)
+	}
+}
+
+// truncate caps the number of subjects in the RoleBindings and ClusterRoleBindings of the Result to max,
+// dropping the bindings which are left without subjects. Like the table rows, the subjects of the RoleBindings are
+// kept first, and within each kind of binding the subjects which come first when ordered by the given key, if any.
+// It records the total number of subjects and whether any of them was dropped.
+func (r *Result) truncate(max int, key sortKey) {
+	if max <= 0 {
+		return
+	}
+	remaining := max
+	take := func(bindings []Binding) []Binding {
+		type position struct{ binding, subject int }
+		var positions []position
+		for i, b := range bindings {
+			r.Total += len(b.Subjects)
+			for j := range b.Subjects {
+				positions = append(positions, position{binding: i, subject: j})
+			}
+		}
+		if key != "" {
+			order := sortOrder(key)
+			row := func(p position) subjectRow {
+				return bindingRow(bindings[p.binding], bindings[p.binding].Subjects[p.subject])
+			}
+			sort.SliceStable(positions, func(i, j int) bool {
+				return rowLess(row(positions[i]), row(positions[j]), order)
+			})
+		}
+		if len(positions) > remaining {
+			positions = positions[:remaining]
+		}
+		remaining -= len(positions)
+
+		shown := make(map[position]bool, len(positions))
+		for _, p := range positions {
+			shown[p] = true
+		}
+		kept := []Binding{}
+		for i, b := range bindings {
+			subjects := []Subject{}
+			for j, s := range b.Subjects {
+				if shown[position{binding: i, subject: j}] {
+					subjects = append(subjects, s)
+				}
+			}
+			if len(subjects) > 0 || len(b.Subjects) == 0 {
+				b.Subjects = subjects
+				kept = append(kept, b)
+			}
+		}
+		return kept
+	}
+	r.RoleBindings = take(r.RoleBindings)
+	r.ClusterRoleBindings = take(r.ClusterRoleBindings)
+	r.Truncated = r.Total > max
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"testing"
+)
+
+func TestWhoCan_output_maxResults(t *testing.T) {
+	roleBindings := []rbac.RoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Eve-can-view-pods", Namespace: "default"},
+			Subjects:   []rbac.Subject{{Name: "Eve", Kind: "User"}},
+		},
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Alice-can-view-pods", Namespace: "default"},
+			Subjects:   []rbac.Subject{{Name: "Alice", Kind: "User"}},
+		},
+	}
+	clusterRoleBindings := []rbac.ClusterRoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Bob-and-Carol-can-view-pods"},
+			Subjects: []rbac.Subject{
+				{Name: "Carol", Kind: "User"},
+				{Name: "Bob", Kind: "User"},
+			},
+		},
+	}
+
+	data := []struct {
+		scenario   string
+		maxResults int
+		output     string
+	}{
+		{
+			scenario:   "Should print all rows without limit",
+			maxResults: 0,
+			output: `ROLEBINDING          NAMESPACE  SUBJECT  TYPE  SA-NAMESPACE
+Alice-can-view-pods  default    Alice    User  
+Eve-can-view-pods    default    Eve      User  
+
+CLUSTERROLEBINDING           SUBJECT  TYPE  SA-NAMESPACE
+Bob-and-Carol-can-view-pods  Bob      User  
+Bob-and-Carol-can-view-pods  Carol    User  
+`,
+		},
+		{
+			scenario:   "Should truncate sorted rows across sections",
+			maxResults: 3,
+			output: `ROLEBINDING          NAMESPACE  SUBJECT  TYPE  SA-NAMESPACE
+Alice-can-view-pods  default    Alice    User  
+Eve-can-view-pods    default    Eve      User  
+
+CLUSTERROLEBINDING           SUBJECT  TYPE  SA-NAMESPACE
+Bob-and-Carol-can-view-pods  Bob      User  
+(truncated, 1 more)
+`,
+		},
+		{
+			scenario:   "Should omit section header when all its rows are truncated",
+			maxResults: 1,
+			output: `ROLEBINDING          NAMESPACE  SUBJECT  TYPE  SA-NAMESPACE
+Alice-can-view-pods  default    Alice    User  
+
+(truncated, 3 more)
+`,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			streams, _, out, _ := clioptions.NewTestIOStreams()
+			wc := whoCan{
+				verb:       "get",
+				resource:   "pods",
+				sortBy:     sortBySubject,
+				maxResults: tt.maxResults,
+				IOStreams:  streams,
+			}
+
+			// when
+			wc.output(roleBindings, clusterRoleBindings)
+
+			// then
+			assert.Equal(t, tt.output, out.String())
+		})
+	}
+}
+
+func TestResult_truncate(t *testing.T) {
+	alice := Subject{Kind: "User", Name: "Alice"}
+	bob := Subject{Kind: "User", Name: "Bob"}
+	carol := Subject{Kind: "User", Name: "Carol"}
+	eve := Subject{Kind: "User", Name: "Eve"}
+
+	data := []struct {
+		scenario string
+		key      sortKey
+
+		roleBindings        []Binding
+		clusterRoleBindings []Binding
+	}{
+		{
+			scenario: "Should keep the leading subjects in listing order",
+			roleBindings: []Binding{
+				{Name: "rb", Namespace: "foo", Subjects: []Subject{alice, eve}},
+			},
+			clusterRoleBindings: []Binding{
+				{Name: "crb-1", Subjects: []Subject{carol}},
+			},
+		},
+		{
+			scenario: "Should keep the leading subjects in sort order across bindings",
+			key:      sortBySubject,
+			roleBindings: []Binding{
+				{Name: "rb", Namespace: "foo", Subjects: []Subject{alice, eve}},
+			},
+			clusterRoleBindings: []Binding{
+				{Name: "crb-2", Subjects: []Subject{bob}},
+			},
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			result := Result{
+				RoleBindings: []Binding{
+					{Name: "rb", Namespace: "foo", Subjects: []Subject{alice, eve}},
+				},
+				ClusterRoleBindings: []Binding{
+					{Name: "crb-1", Subjects: []Subject{carol, eve}},
+					{Name: "crb-2", Subjects: []Subject{bob}},
+				},
+			}
+
+			// when
+			result.truncate(3, tt.key)
+
+			// then
+			assert.True(t, result.Truncated)
+			assert.Equal(t, 5, result.Total)
+			assert.Equal(t, tt.roleBindings, result.RoleBindings)
+			assert.Equal(t, tt.clusterRoleBindings, result.ClusterRoleBindings)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// mermaidEscaper escapes the characters which would otherwise end a quoted Mermaid label or be read as markup.
+var mermaidEscaper = strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;", "\n", " ")
+
+// mermaidGraph collects the nodes and the links of a Mermaid flowchart, each written once in the order they are added.
+// Nodes get generated IDs, as the names of subjects, bindings and roles may contain characters not allowed in IDs.
+type mermaidGraph struct {
+	buf   bytes.Buffer
+	nodes map[string]string
+	links map[string]bool
+}
+
+// printMermaid writes the Result as a Mermaid flowchart with the links subject --> binding --> role for each grant,
+// which is rendered by Markdown viewers and wikis that support Mermaid.
+func (w *whoCan) printMermaid(out io.Writer, result Result) error {
+	g := &mermaidGraph{nodes: make(map[string]string), links: make(map[string]bool)}
+	g.buf.WriteString("flowchart LR\n")
+	result.forEachBinding(func(bindingKind string, b Binding) {
+		binding := g.node(bindingKind, b.Namespace, b.Name, "[", "]")
+		roleNamespace := b.Namespace
+		if b.RoleRef.Kind == "ClusterRole" {
+			roleNamespace = ""
+		}
+		role := g.node(b.RoleRef.Kind, roleNamespace, b.RoleRef.Name, "[[", "]]")
+		for _, s := range b.Subjects {
+			g.link(g.node(s.Kind, s.Namespace, s.Name, "([", "])"), binding)
+		}
+		g.link(binding, role)
+	})
+
+	_, err := out.Write(g.buf.Bytes())
+	return err
+}
+
+// node adds the node of the given kind, namespace and name with the given shape unless it was already added,
+// and returns its ID.
+func (g *mermaidGraph) node(kind, namespace, name, open, close string) string {
+	key := kind + "/" + name
+	if namespace != "" {
+		key = kind + "/" + namespace + "/" + name
+		name = namespace + "/" + name
+	}
+	if id, ok := g.nodes[key]; ok {
+		return id
+	}
+	id := fmt.Sprintf("n%d", len(g.nodes))
+	g.nodes[key] = id
+	_, _ = fmt.Fprintf(&g.buf, "  %s%s\"%s<br/>%s\"%s\n", id, open, mermaidEscaper.Replace(kind), mermaidEscaper.Replace(name), close)
+	return id
+}
+
+// link adds the link between the nodes of the given IDs unless it was already added.
+func (g *mermaidGraph) link(from, to string) {
+	key := from + " " + to
+	if g.links[key] {
+		return
+	}
+	g.links[key] = true
+	_, _ = fmt.Fprintf(&g.buf, "  %s --> %s\n", from, to)
+}
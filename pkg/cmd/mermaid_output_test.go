@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"testing"
+)
+
+func TestWhoCan_printMermaid(t *testing.T) {
+	// given
+	roleBindings := []rbac.RoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "view-pods", Namespace: "default"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "view-pods"},
+			Subjects: []rbac.Subject{
+				{Name: "alice", Kind: "User"},
+				{Name: "bar", Kind: "ServiceAccount", Namespace: "foo"},
+			},
+		},
+	}
+	clusterRoleBindings := []rbac.ClusterRoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "admins"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+			Subjects: []rbac.Subject{
+				{Name: "alice", Kind: "User"},
+				{Name: `"ops"`, Kind: "Group"},
+			},
+		},
+	}
+
+	streams, _, out, _ := clioptions.NewTestIOStreams()
+	wc := whoCan{
+		verb:         "get",
+		resource:     "pods",
+		outputFormat: outputMermaid,
+		IOStreams:    streams,
+	}
+
+	// when
+	err := wc.printResult(wc.newResult(roleBindings, clusterRoleBindings, []string{"list roles"}))
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, `flowchart LR
+  n0["RoleBinding<br/>default/view-pods"]
+  n1[["Role<br/>default/view-pods"]]
+  n2(["User<br/>alice"])
+  n2 --> n0
+  n3(["ServiceAccount<br/>foo/bar"])
+  n3 --> n0
+  n0 --> n1
+  n4["ClusterRoleBinding<br/>admins"]
+  n5[["ClusterRole<br/>cluster-admin"]]
+  n2 --> n4
+  n6(["Group<br/>#quot;ops#quot;"])
+  n6 --> n4
+  n4 --> n5
+`, out.String())
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+// Metadata describes when and against which cluster a Result was generated, so that saved results are self-describing.
+// It is part of the v1 structured output only.
+type Metadata struct {
+	// Timestamp is the time at which the result was generated in RFC 3339 format in UTC.
+	Timestamp string `json:"timestamp"`
+	// Context is the name of the kubeconfig context, and Server is the URL of the API server it refers to.
+	Context string `json:"context,omitempty"`
+	Server  string `json:"server,omitempty"`
+}
+
+// newMetadata returns the Metadata of a result generated now against the cluster of the resolved kubeconfig context.
+// The context and the server are left empty if they cannot be resolved.
+func (w *whoCan) newMetadata() *Metadata {
+	var c clock.Clock = clock.RealClock{}
+	if w.clock != nil {
+		c = w.clock
+	}
+	metadata := &Metadata{Timestamp: c.Now().UTC().Format(time.RFC3339)}
+
+	if w.configFlags != nil && w.configFlags.Context != nil && *w.configFlags.Context != "" {
+		metadata.Context = *w.configFlags.Context
+	}
+	if w.clientConfig == nil {
+		return metadata
+	}
+	if metadata.Context == "" {
+		raw, err := w.clientConfig.RawConfig()
+		if err != nil {
+			glog.V(3).Infof("Failed to get kubeconfig context: %v", err)
+		} else {
+			metadata.Context = raw.CurrentContext
+		}
+	}
+	config, err := w.clientConfig.ClientConfig()
+	if err != nil {
+		glog.V(3).Infof("Failed to get API server: %v", err)
+	} else {
+		metadata.Server = config.Host
+	}
+	return metadata
+}
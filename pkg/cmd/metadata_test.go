@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/clock"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"testing"
+	"time"
+)
+
+func TestWhoCan_newMetadata(t *testing.T) {
+	kubeConfig := clientcmdapi.Config{
+		CurrentContext: "prod",
+		Contexts: map[string]*clientcmdapi.Context{
+			"prod":    {Cluster: "prod-cluster"},
+			"staging": {Cluster: "staging-cluster"},
+		},
+		Clusters: map[string]*clientcmdapi.Cluster{
+			"prod-cluster":    {Server: "https://prod.example.com:6443"},
+			"staging-cluster": {Server: "https://staging.example.com:6443"},
+		},
+	}
+	fakeClock := clock.NewFakeClock(time.Date(2019, 6, 12, 14, 30, 0, 0, time.FixedZone("CEST", 2*60*60)))
+
+	data := []struct {
+		scenario     string
+		context      string
+		clientConfig clientcmd.ClientConfig
+
+		metadata *Metadata
+	}{
+		{
+			scenario:     "Should describe current context",
+			clientConfig: clientcmd.NewDefaultClientConfig(kubeConfig, &clientcmd.ConfigOverrides{}),
+			metadata:     &Metadata{Timestamp: "2019-06-12T12:30:00Z", Context: "prod", Server: "https://prod.example.com:6443"},
+		},
+		{
+			scenario:     "Should describe context given by --context flag",
+			context:      "staging",
+			clientConfig: clientcmd.NewDefaultClientConfig(kubeConfig, &clientcmd.ConfigOverrides{CurrentContext: "staging"}),
+			metadata:     &Metadata{Timestamp: "2019-06-12T12:30:00Z", Context: "staging", Server: "https://staging.example.com:6443"},
+		},
+		{
+			scenario: "Should describe only time without kubeconfig",
+			metadata: &Metadata{Timestamp: "2019-06-12T12:30:00Z"},
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			configFlags := clioptions.NewConfigFlags(true)
+			configFlags.Context = &tt.context
+			wc := whoCan{configFlags: configFlags, clientConfig: tt.clientConfig, clock: fakeClock}
+
+			// when
+			metadata := wc.newMetadata()
+
+			// then
+			assert.Equal(t, tt.metadata, metadata)
+		})
+	}
+}
+
+func TestWhoCan_newMetadata_FakeClock(t *testing.T) {
+	// given
+	fakeClock := clock.NewFakeClock(time.Date(2019, 6, 12, 12, 30, 0, 0, time.UTC))
+	wc := whoCan{clock: fakeClock}
+
+	// when
+	first := wc.newMetadata()
+	fakeClock.Step(90 * time.Second)
+	second := wc.newMetadata()
+
+	// then
+	assert.Equal(t, "2019-06-12T12:30:00Z", first.Timestamp)
+	assert.Equal(t, "2019-06-12T12:31:30Z", second.Timestamp)
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	rbac "k8s.io/api/rbac/v1"
+)
+
+// printNames writes one canonical identifier per subject of the Result, such as `user/alice`, `group/devs` or
+// `serviceaccount/foo/bar`, which can be piped to xargs or other kubectl invocations. A subject bound by several
+// bindings is written once.
+func (w *whoCan) printNames(out io.Writer, result Result) error {
+	for _, s := range result.Subjects() {
+		if _, err := fmt.Fprintln(out, subjectName(s)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// subjectName returns the canonical identifier of the subject, which is its lowercase kind followed by its name,
+// and by the namespace of a ServiceAccount before its name.
+func subjectName(s Subject) string {
+	kind := strings.ToLower(s.Kind)
+	if s.Kind == rbac.ServiceAccountKind && s.Namespace != "" {
+		return kind + "/" + s.Namespace + "/" + s.Name
+	}
+	return kind + "/" + s.Name
+}
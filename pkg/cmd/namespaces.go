@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	namespacesUsage = `namespaces --subject KIND/NAME`
+	namespacesLong  = `Shows the namespaces in which a subject has any access.
+
+A namespace is listed if a RoleBinding in it binds the subject, either directly or through a group which the subject
+implicitly belongs to. If a ClusterRoleBinding binds the subject, 'cluster-wide' is listed as well.
+
+KIND is one of 'User', 'Group' or 'ServiceAccount'. The NAME of a ServiceAccount is given as NAMESPACE:NAME.`
+	namespacesExample = `  # List the namespaces in which the user "jane" has any access
+  kubectl who-can namespaces --subject User/jane
+
+  # List the namespaces in which the service account "deployer" of the namespace "ci" has any access
+  kubectl who-can namespaces --subject ServiceAccount/ci:deployer`
+
+	// clusterWide is listed when a subject is bound by a ClusterRoleBinding.
+	clusterWide = "cluster-wide"
+)
+
+type namespacesWhoCan struct {
+	subject string
+
+	client kubernetes.Interface
+
+	clioptions.IOStreams
+}
+
+func NewCmdNamespaces(client kubernetes.Interface, streams clioptions.IOStreams) *cobra.Command {
+	o := &namespacesWhoCan{
+		client:    client,
+		IOStreams: streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:          namespacesUsage,
+		Short:        "Show the namespaces in which a subject has any access",
+		Long:         namespacesLong,
+		Example:      namespacesExample,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if o.subject == "" {
+				return errors.New("--subject is required")
+			}
+			return o.run()
+		},
+	}
+
+	cmd.Flags().StringVar(&o.subject, "subject", "", "Subject given as KIND/NAME, such as User/jane or ServiceAccount/ci:deployer")
+
+	return cmd
+}
+
+func (o *namespacesWhoCan) run() error {
+	subject, err := parseSubject(o.subject)
+	if err != nil {
+		return err
+	}
+
+	rbl, err := o.client.RbacV1().RoleBindings(meta.NamespaceAll).List(meta.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("listing RoleBindings: %v", err)
+	}
+	crbl, err := o.client.RbacV1().ClusterRoleBindings().List(meta.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("listing ClusterRoleBindings: %v", err)
+	}
+
+	namespaces := namespacesOf(subject, rbl.Items, crbl.Items)
+	if len(namespaces) == 0 {
+		_, _ = fmt.Fprintf(o.Out, "No RoleBindings or ClusterRoleBindings found for %s\n", o.subject)
+		return nil
+	}
+	for _, namespace := range namespaces {
+		_, _ = fmt.Fprintln(o.Out, namespace)
+	}
+	return nil
+}
+
+// namespacesOf returns the sorted namespaces of the RoleBindings which bind the given subject,
+// followed by `cluster-wide` if any ClusterRoleBinding binds it.
+func namespacesOf(subject rbac.Subject, roleBindings []rbac.RoleBinding, clusterRoleBindings []rbac.ClusterRoleBinding) []string {
+	seen := make(map[string]struct{})
+	var namespaces []string
+	for _, rb := range roleBindings {
+		if _, ok := seen[rb.Namespace]; ok || !bindsSubject(rb.Subjects, subject) {
+			continue
+		}
+		seen[rb.Namespace] = struct{}{}
+		namespaces = append(namespaces, rb.Namespace)
+	}
+	sort.Strings(namespaces)
+
+	for _, crb := range clusterRoleBindings {
+		if bindsSubject(crb.Subjects, subject) {
+			namespaces = append(namespaces, clusterWide)
+			break
+		}
+	}
+	return namespaces
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+)
+
+func TestNamespacesOf(t *testing.T) {
+	jane := rbac.Subject{Kind: rbac.UserKind, Name: "jane"}
+	deployer := rbac.Subject{Kind: rbac.ServiceAccountKind, Name: "deployer", Namespace: "ci"}
+
+	roleBindings := []rbac.RoleBinding{
+		{ObjectMeta: meta.ObjectMeta{Name: "jane-edit", Namespace: "foo"}, Subjects: []rbac.Subject{jane}},
+		{ObjectMeta: meta.ObjectMeta{Name: "jane-view", Namespace: "bar"}, Subjects: []rbac.Subject{jane}},
+		{ObjectMeta: meta.ObjectMeta{Name: "jane-admin", Namespace: "foo"}, Subjects: []rbac.Subject{jane}},
+		{ObjectMeta: meta.ObjectMeta{Name: "ci-deploy", Namespace: "prod"},
+			Subjects: []rbac.Subject{{Kind: rbac.GroupKind, Name: "system:serviceaccounts:ci"}}},
+		{ObjectMeta: meta.ObjectMeta{Name: "authenticated-view", Namespace: "docs"},
+			Subjects: []rbac.Subject{{Kind: rbac.GroupKind, Name: "system:authenticated"}}},
+	}
+	clusterRoleBindings := []rbac.ClusterRoleBinding{
+		{ObjectMeta: meta.ObjectMeta{Name: "deployer-view"}, Subjects: []rbac.Subject{deployer}},
+	}
+
+	data := []struct {
+		scenario   string
+		subject    rbac.Subject
+		namespaces []string
+	}{
+		{
+			scenario:   "Should return sorted distinct namespaces of RoleBindings",
+			subject:    jane,
+			namespaces: []string{"bar", "docs", "foo"},
+		},
+		{
+			scenario:   "Should return namespaces of groups and cluster-wide access of service account",
+			subject:    deployer,
+			namespaces: []string{"docs", "prod", "cluster-wide"},
+		},
+		{
+			scenario:   "Should return namespaces of group of authenticated users for group",
+			subject:    rbac.Subject{Kind: rbac.GroupKind, Name: "devs"},
+			namespaces: []string{"docs"},
+		},
+		{
+			scenario: "Should return no namespaces for unbound anonymous user",
+			subject:  rbac.Subject{Kind: rbac.UserKind, Name: "system:anonymous"},
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			assert.Equal(t, tt.namespaces, namespacesOf(tt.subject, roleBindings, clusterRoleBindings))
+		})
+	}
+}
+
+func TestNamespacesWhoCan_run(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&rbac.RoleBinding{ObjectMeta: meta.ObjectMeta{Name: "jane-edit", Namespace: "foo"},
+			Subjects: []rbac.Subject{{Kind: rbac.UserKind, Name: "jane"}}},
+		&rbac.ClusterRoleBinding{ObjectMeta: meta.ObjectMeta{Name: "jane-view"},
+			Subjects: []rbac.Subject{{Kind: rbac.UserKind, Name: "jane"}}},
+	)
+
+	data := []struct {
+		scenario string
+		subject  string
+		output   string
+		err      string
+	}{
+		{
+			scenario: "Should print namespaces of subject",
+			subject:  "User/jane",
+			output:   "foo\ncluster-wide\n",
+		},
+		{
+			scenario: "Should print message when subject is not bound",
+			subject:  "User/bob",
+			output:   "No RoleBindings or ClusterRoleBindings found for User/bob\n",
+		},
+		{
+			scenario: "Should return error when subject is invalid",
+			subject:  "bob",
+			err:      "invalid subject \"bob\", expected KIND/NAME",
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			streams, _, out, _ := clioptions.NewTestIOStreams()
+			o := &namespacesWhoCan{subject: tt.subject, client: client, IOStreams: streams}
+
+			// when
+			err := o.run()
+
+			// then
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.output, out.String())
+		})
+	}
+}
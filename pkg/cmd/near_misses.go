@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	rbac "k8s.io/api/rbac/v1"
+)
+
+// recordNearMiss records the resource names of the given rule if it grants the queried verb and resource,
+// but only for resource names other than the queried one.
+func (w *whoCan) recordNearMiss(r role, rule rbac.PolicyRule) {
+	if !w.showNearMisses || w.resourceName == "" || w.nonResourceURL != "" {
+		return
+	}
+	if len(rule.ResourceNames) == 0 || !w.matchesVerb(rule) || !w.matchesAPIGroup(rule) || !w.matchesResource(rule) {
+		return
+	}
+	w.nearMisses[r] = append(w.nearMisses[r], rule.ResourceNames...)
+}
+
+// isNearMiss returns `true` if the given RoleRef refers to a near miss role, which does not match the queried action.
+func (w *whoCan) isNearMiss(roleRef *rbac.RoleRef, namespace string) bool {
+	if w.r.match(roleRef, namespace) {
+		return false
+	}
+	_, ok := w.nearMisses[newRoleFromRef(roleRef, namespace)]
+	return ok
+}
+
+// nearMissBindings returns the bindings to near miss roles with the resource names they are restricted to.
+func (w *whoCan) nearMissBindings() []Binding {
+	var bindings []Binding
+	for _, rb := range w.nearMissRoleBindings {
+		binding := w.newBinding(rb.Name, rb.Namespace, rb.RoleRef, rb.Subjects)
+		binding.ResourceNames = w.nearMisses[newRoleFromRef(&rb.RoleRef, rb.Namespace)]
+		bindings = append(bindings, binding)
+	}
+	for _, crb := range w.nearMissClusterRoleBindings {
+		binding := w.newBinding(crb.Name, "", crb.RoleRef, crb.Subjects)
+		binding.ResourceNames = w.nearMisses[newRoleFromRef(&crb.RoleRef, "")]
+		bindings = append(bindings, binding)
+	}
+	return bindings
+}
+
+// printNearMisses prints the bindings which grant the queried action only for other resource names,
+// separately from the matched bindings.
+func (w *whoCan) printNearMisses() {
+	if !w.showNearMisses {
+		return
+	}
+
+	_, _ = fmt.Fprintln(w.Out)
+	bindings := w.nearMissBindings()
+	if len(bindings) == 0 {
+		_, _ = fmt.Fprintf(w.Out, "No near misses found with permissions to %s %s restricted to other resource names\n", w.verb, w.resource)
+		return
+	}
+
+	_, _ = fmt.Fprintf(w.Out, "Near misses with permissions to %s %s restricted to other resource names:\n", w.verb, w.resource)
+	wr := new(tabwriter.Writer)
+	wr.Init(w.Out, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintln(wr, "BINDING\tNAMESPACE\tSUBJECT\tTYPE\tSA-NAMESPACE\tRESOURCE-NAMES")
+	for _, b := range bindings {
+		kind := "ClusterRoleBinding"
+		if b.Namespace != "" {
+			kind = "RoleBinding"
+		}
+		for _, s := range b.Subjects {
+			_, _ = fmt.Fprintf(wr, "%s/%s\t%s\t%s\t%s\t%s\t%s\n", kind, b.Name, b.Namespace, s.Name, s.Kind, s.Namespace, strings.Join(b.ResourceNames, ","))
+		}
+	}
+	_ = wr.Flush()
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"testing"
+)
+
+func TestWhoCan_filterClusterRoles_nearMisses(t *testing.T) {
+	// given
+	wc := whoCan{verb: "get", resource: "secrets", resourceName: "db-password", showNearMisses: true}
+	wc.r = make(roles)
+	wc.rules = make(map[role][]rbac.PolicyRule)
+	wc.wildcards = make(map[role]bool)
+	wc.nearMisses = make(map[role][]string)
+
+	clusterRoles := &rbac.ClusterRoleList{
+		Items: []rbac.ClusterRole{
+			{
+				ObjectMeta: meta.ObjectMeta{Name: "matching"},
+				Rules: []rbac.PolicyRule{
+					{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"secrets"}, ResourceNames: []string{"db-password"}},
+				},
+			},
+			{
+				ObjectMeta: meta.ObjectMeta{Name: "other-names"},
+				Rules: []rbac.PolicyRule{
+					{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"secrets"}, ResourceNames: []string{"api-token", "tls"}},
+				},
+			},
+			{
+				ObjectMeta: meta.ObjectMeta{Name: "other-verb"},
+				Rules: []rbac.PolicyRule{
+					{APIGroups: []string{""}, Verbs: []string{"list"}, Resources: []string{"secrets"}, ResourceNames: []string{"api-token"}},
+				},
+			},
+		},
+	}
+
+	// when
+	wc.filterClusterRoles(clusterRoles)
+
+	// then
+	assert.Equal(t, roles{{name: "matching", isClusterRole: true}: struct{}{}}, wc.r)
+	assert.Equal(t, map[role][]string{
+		{name: "other-names", isClusterRole: true}: {"api-token", "tls"},
+	}, wc.nearMisses)
+	assert.True(t, wc.isNearMiss(&rbac.RoleRef{Kind: "ClusterRole", Name: "other-names"}, ""))
+	assert.False(t, wc.isNearMiss(&rbac.RoleRef{Kind: "ClusterRole", Name: "matching"}, ""))
+}
+
+func TestWhoCan_printNearMisses(t *testing.T) {
+	data := []struct {
+		scenario                    string
+		nearMissRoleBindings        []rbac.RoleBinding
+		nearMissClusterRoleBindings []rbac.ClusterRoleBinding
+		output                      string
+	}{
+		{
+			scenario: "A",
+			output: `
+No near misses found with permissions to get secrets restricted to other resource names
+`,
+		},
+		{
+			scenario: "B",
+			nearMissRoleBindings: []rbac.RoleBinding{
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "Alice-can-get-tokens", Namespace: "foo"},
+					RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "other-names"},
+					Subjects:   []rbac.Subject{{Kind: "User", Name: "Alice"}},
+				},
+			},
+			nearMissClusterRoleBindings: []rbac.ClusterRoleBinding{
+				{
+					ObjectMeta: meta.ObjectMeta{Name: "Bob-can-get-tokens"},
+					RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "other-names"},
+					Subjects:   []rbac.Subject{{Kind: "ServiceAccount", Name: "Bob", Namespace: "bar"}},
+				},
+			},
+			output: `
+Near misses with permissions to get secrets restricted to other resource names:
+BINDING                                NAMESPACE  SUBJECT  TYPE            SA-NAMESPACE  RESOURCE-NAMES
+RoleBinding/Alice-can-get-tokens       foo        Alice    User                          api-token,tls
+ClusterRoleBinding/Bob-can-get-tokens             Bob      ServiceAccount  bar           api-token,tls
+`,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			streams, _, out, _ := clioptions.NewTestIOStreams()
+			wc := whoCan{
+				verb:                        "get",
+				resource:                    "secrets",
+				resourceName:                "db-password",
+				showNearMisses:              true,
+				nearMisses:                  map[role][]string{{name: "other-names", isClusterRole: true}: {"api-token", "tls"}},
+				nearMissRoleBindings:        tt.nearMissRoleBindings,
+				nearMissClusterRoleBindings: tt.nearMissClusterRoleBindings,
+				IOStreams:                   streams,
+			}
+
+			// when
+			wc.printNearMisses()
+
+			// then
+			assert.Equal(t, tt.output, out.String())
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"strings"
+
+	rbac "k8s.io/api/rbac/v1"
+)
+
+// normalizeSubject returns the canonical representation of a subject of a binding in the given namespace.
+// A User named system:serviceaccount:NAMESPACE:NAME is the ServiceAccount NAME in NAMESPACE, and a ServiceAccount
+// without a namespace refers to the namespace of the binding. The API group is dropped because it is implied by the kind.
+// Groups, including system:serviceaccounts:NAMESPACE, are kept as they are, because they stand for all their members
+// rather than for a single ServiceAccount.
+func normalizeSubject(s rbac.Subject, bindingNamespace string) rbac.Subject {
+	switch s.Kind {
+	case rbac.UserKind:
+		if strings.HasPrefix(s.Name, serviceAccountUserPrefix) {
+			tokens := strings.SplitN(strings.TrimPrefix(s.Name, serviceAccountUserPrefix), ":", 2)
+			if len(tokens) == 2 && tokens[0] != "" && tokens[1] != "" {
+				return rbac.Subject{Kind: rbac.ServiceAccountKind, Namespace: tokens[0], Name: tokens[1]}
+			}
+		}
+		return rbac.Subject{Kind: rbac.UserKind, Name: s.Name}
+	case rbac.GroupKind:
+		return rbac.Subject{Kind: rbac.GroupKind, Name: s.Name}
+	case rbac.ServiceAccountKind:
+		return rbac.Subject{Kind: rbac.ServiceAccountKind, Namespace: serviceAccountNamespace(s, bindingNamespace), Name: s.Name}
+	}
+	return s
+}
+
+// normalizeBindingSubjects lists identical subjects of a binding once, because a malformed binding may list the same
+// subject twice. When --normalize-subjects is set, the subjects are rewritten into their canonical representations
+// first, so that equivalent subjects are listed once per binding and counted once across bindings.
+func (w *whoCan) normalizeBindingSubjects(roleBindings []rbac.RoleBinding, clusterRoleBindings []rbac.ClusterRoleBinding) ([]rbac.RoleBinding, []rbac.ClusterRoleBinding) {
+	normalized := func(subjects []rbac.Subject, bindingNamespace string) []rbac.Subject {
+		var normalized []rbac.Subject
+		seen := make(map[rbac.Subject]struct{}, len(subjects))
+		for _, s := range subjects {
+			if w.normalizeSubjects {
+				s = normalizeSubject(s, bindingNamespace)
+			}
+			if _, ok := seen[s]; ok {
+				continue
+			}
+			seen[s] = struct{}{}
+			normalized = append(normalized, s)
+		}
+		return normalized
+	}
+
+	var normalizedRoleBindings []rbac.RoleBinding
+	for _, rb := range roleBindings {
+		rb.Subjects = normalized(rb.Subjects, rb.Namespace)
+		normalizedRoleBindings = append(normalizedRoleBindings, rb)
+	}
+	var normalizedClusterRoleBindings []rbac.ClusterRoleBinding
+	for _, crb := range clusterRoleBindings {
+		crb.Subjects = normalized(crb.Subjects, "")
+		normalizedClusterRoleBindings = append(normalizedClusterRoleBindings, crb)
+	}
+	return normalizedRoleBindings, normalizedClusterRoleBindings
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+func TestNormalizeSubject(t *testing.T) {
+	deployer := rbac.Subject{Kind: rbac.ServiceAccountKind, Name: "deployer", Namespace: "ci"}
+
+	data := []struct {
+		scenario         string
+		subject          rbac.Subject
+		bindingNamespace string
+
+		normalized rbac.Subject
+	}{
+		{
+			scenario:   "Should keep ServiceAccount with namespace",
+			subject:    deployer,
+			normalized: deployer,
+		},
+		{
+			scenario:         "Should default namespace of ServiceAccount to namespace of RoleBinding",
+			subject:          rbac.Subject{Kind: rbac.ServiceAccountKind, Name: "deployer"},
+			bindingNamespace: "ci",
+			normalized:       deployer,
+		},
+		{
+			scenario:   "Should convert User of ServiceAccount to ServiceAccount",
+			subject:    rbac.Subject{Kind: rbac.UserKind, APIGroup: rbac.GroupName, Name: "system:serviceaccount:ci:deployer"},
+			normalized: deployer,
+		},
+		{
+			scenario:   "Should keep User with incomplete ServiceAccount name",
+			subject:    rbac.Subject{Kind: rbac.UserKind, Name: "system:serviceaccount:ci"},
+			normalized: rbac.Subject{Kind: rbac.UserKind, Name: "system:serviceaccount:ci"},
+		},
+		{
+			scenario:   "Should drop API group of User",
+			subject:    rbac.Subject{Kind: rbac.UserKind, APIGroup: rbac.GroupName, Name: "alice"},
+			normalized: rbac.Subject{Kind: rbac.UserKind, Name: "alice"},
+		},
+		{
+			scenario:   "Should keep group of ServiceAccounts of namespace as Group",
+			subject:    rbac.Subject{Kind: rbac.GroupKind, APIGroup: rbac.GroupName, Name: "system:serviceaccounts:ci"},
+			normalized: rbac.Subject{Kind: rbac.GroupKind, Name: "system:serviceaccounts:ci"},
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			assert.Equal(t, tt.normalized, normalizeSubject(tt.subject, tt.bindingNamespace))
+		})
+	}
+}
+
+func TestWhoCan_normalizeBindingSubjects(t *testing.T) {
+	deployer := rbac.Subject{Kind: rbac.ServiceAccountKind, Name: "deployer", Namespace: "ci"}
+	deployerUser := rbac.Subject{Kind: rbac.UserKind, APIGroup: rbac.GroupName, Name: "system:serviceaccount:ci:deployer"}
+	implicitDeployer := rbac.Subject{Kind: rbac.ServiceAccountKind, Name: "deployer"}
+	ciGroup := rbac.Subject{Kind: rbac.GroupKind, APIGroup: rbac.GroupName, Name: "system:serviceaccounts:ci"}
+
+	roleBindings := []rbac.RoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "deployers", Namespace: "ci"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "edit"},
+			Subjects:   []rbac.Subject{deployer, deployerUser, implicitDeployer, ciGroup},
+		},
+	}
+	clusterRoleBindings := []rbac.ClusterRoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "deployers"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "edit"},
+			Subjects:   []rbac.Subject{deployerUser},
+		},
+	}
+
+	t.Run("Should keep subjects as they are when not enabled", func(t *testing.T) {
+		// given
+		wc := whoCan{}
+
+		// when
+		rbs, crbs := wc.normalizeBindingSubjects(roleBindings, clusterRoleBindings)
+
+		// then
+		assert.Equal(t, roleBindings, rbs)
+		assert.Equal(t, clusterRoleBindings, crbs)
+	})
+
+	t.Run("Should deduplicate identical subjects when not enabled", func(t *testing.T) {
+		// given
+		wc := whoCan{}
+		duplicated := []rbac.ClusterRoleBinding{
+			{
+				ObjectMeta: meta.ObjectMeta{Name: "deployers"},
+				RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "edit"},
+				Subjects:   []rbac.Subject{deployerUser, ciGroup, deployerUser},
+			},
+		}
+
+		// when
+		_, crbs := wc.normalizeBindingSubjects(nil, duplicated)
+
+		// then
+		assert.Equal(t, []rbac.Subject{deployerUser, ciGroup}, crbs[0].Subjects)
+		// The given bindings are not modified.
+		assert.Equal(t, []rbac.Subject{deployerUser, ciGroup, deployerUser}, duplicated[0].Subjects)
+	})
+
+	t.Run("Should deduplicate equivalent representations of ServiceAccount", func(t *testing.T) {
+		// given
+		wc := whoCan{normalizeSubjects: true}
+
+		// when
+		rbs, crbs := wc.normalizeBindingSubjects(roleBindings, clusterRoleBindings)
+
+		// then
+		assert.Equal(t, []rbac.Subject{deployer, {Kind: rbac.GroupKind, Name: "system:serviceaccounts:ci"}}, rbs[0].Subjects)
+		assert.Equal(t, []rbac.Subject{deployer}, crbs[0].Subjects)
+		assert.Equal(t, []roleSummary{{kind: "ClusterRole", name: "edit", subjects: 2}}, rolesSummary(rbs, crbs))
+		// The given bindings are not modified.
+		assert.Equal(t, []rbac.Subject{deployerUser}, clusterRoleBindings[0].Subjects)
+	})
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// ObjectLookup wraps methods to look up the objects of a resource type.
+//
+// The objects are looked up with the dynamic client, hence any resource served by the API server
+// can be looked up by its GroupVersionResource, including custom resources which have no typed clients.
+//
+// Exists returns `true` if the object with the given `name` exists in the given `namespace`.
+// Names returns the sorted names of the objects in the given `namespace` that match the label `selector`.
+// Specifying "" as namespace of a namespaced resource lists the objects in all namespaces.
+type ObjectLookup interface {
+	Exists(gvr schema.GroupVersionResource, namespace, name string) (bool, error)
+	Names(gvr schema.GroupVersionResource, namespace, selector string) ([]string, error)
+}
+
+type objectLookup struct {
+	client dynamic.Interface
+}
+
+func NewObjectLookup(client dynamic.Interface) ObjectLookup {
+	return &objectLookup{
+		client: client,
+	}
+}
+
+func (ol *objectLookup) Exists(gvr schema.GroupVersionResource, namespace, name string) (bool, error) {
+	_, err := ol.client.Resource(gvr).Namespace(namespace).Get(name, meta.GetOptions{})
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (ol *objectLookup) Names(gvr schema.GroupVersionResource, namespace, selector string) ([]string, error) {
+	list, err := ol.client.Resource(gvr).Namespace(namespace).List(meta.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]struct{}, len(list.Items))
+	var names []string
+	for _, item := range list.Items {
+		if _, ok := seen[item.GetName()]; ok {
+			continue
+		}
+		seen[item.GetName()] = struct{}{}
+		names = append(names, item.GetName())
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// lookupObjects verifies that the object named by TYPE/NAME exists if --verify-resource-name is set, and replaces
+// the --selector by the names of the objects which it matches. Objects of a namespaced resource are looked up in
+// the checked namespace, or in all namespaces.
+func (w *whoCan) lookupObjects() error {
+	if !w.verifyResourceName && w.selector == "" {
+		return nil
+	}
+	// The objects of a sub-resource are the objects of its parent resource.
+	gvr := schema.GroupVersionResource{
+		Group:    w.apiGroup,
+		Version:  w.apiVersion,
+		Resource: strings.SplitN(w.resource, "/", 2)[0],
+	}
+	namespace := ""
+	if w.namespacedResource {
+		namespace = w.namespace
+	}
+	resource := qualifiedName(gvr.Resource, gvr.Group)
+
+	if w.verifyResourceName {
+		var exists bool
+		var err error
+		if w.namespacedResource && namespace == "" {
+			var names []string
+			names, err = w.objectLookup.Names(gvr, "", "")
+			i := sort.SearchStrings(names, w.resourceName)
+			exists = i < len(names) && names[i] == w.resourceName
+		} else {
+			exists, err = w.objectLookup.Exists(gvr, namespace, w.resourceName)
+		}
+		if err != nil {
+			return fmt.Errorf("verifying resource name: %v", err)
+		}
+		if !exists {
+			if namespace != "" {
+				return fmt.Errorf("%s \"%s\" not found in the %s namespace", resource, w.resourceName, namespace)
+			}
+			return fmt.Errorf("%s \"%s\" not found", resource, w.resourceName)
+		}
+	}
+
+	if w.selector != "" {
+		names, err := w.objectLookup.Names(gvr, namespace, w.selector)
+		if err != nil {
+			return fmt.Errorf("listing %s: %v", resource, err)
+		}
+		if len(names) == 0 {
+			return fmt.Errorf("no %s matched the selector \"%s\"", resource, w.selector)
+		}
+		w.resourceNames = names
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func newDatabase(namespace, name string, labels map[string]string) *unstructured.Unstructured {
+	database := &unstructured.Unstructured{}
+	database.SetAPIVersion("example.com/v1")
+	database.SetKind("Database")
+	database.SetNamespace(namespace)
+	database.SetName(name)
+	database.SetLabels(labels)
+	return database
+}
+
+func newDatabaseLookup() ObjectLookup {
+	return NewObjectLookup(fake.NewSimpleDynamicClient(runtime.NewScheme(),
+		newDatabase("foo", "orders", map[string]string{"tier": "backend"}),
+		newDatabase("foo", "users", map[string]string{"tier": "backend"}),
+		newDatabase("foo", "cache", map[string]string{"tier": "frontend"}),
+		newDatabase("bar", "payments", map[string]string{"tier": "backend"}),
+		newDatabase("bar", "orders", map[string]string{"tier": "backend"}),
+	))
+}
+
+func TestObjectLookup(t *testing.T) {
+	// given
+	gvr := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "databases"}
+	lookup := newDatabaseLookup()
+
+	t.Run("Should return true when custom resource object exists", func(t *testing.T) {
+		// when
+		exists, err := lookup.Exists(gvr, "foo", "orders")
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("Should return false when custom resource object does not exist in namespace", func(t *testing.T) {
+		// when
+		exists, err := lookup.Exists(gvr, "foo", "payments")
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("Should return sorted names of custom resource objects matching selector", func(t *testing.T) {
+		// when
+		names, err := lookup.Names(gvr, "foo", "tier=backend")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"orders", "users"}, names)
+	})
+
+	t.Run("Should return each name once when listing all namespaces", func(t *testing.T) {
+		// when
+		names, err := lookup.Names(gvr, "", "tier=backend")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"orders", "payments", "users"}, names)
+	})
+}
+
+type objectLookupMock struct {
+	err error
+}
+
+func (m *objectLookupMock) Exists(_ schema.GroupVersionResource, _, _ string) (bool, error) {
+	return false, m.err
+}
+
+func (m *objectLookupMock) Names(_ schema.GroupVersionResource, _, _ string) ([]string, error) {
+	return nil, m.err
+}
+
+func TestWhoCan_lookupObjects(t *testing.T) {
+	data := []struct {
+		scenario string
+
+		namespace          string
+		namespacedResource bool
+		resourceName       string
+		verifyResourceName bool
+		selector           string
+
+		expectedResourceNames []string
+		expectedErr           error
+	}{
+		{
+			scenario:           "Should accept existing object in namespace",
+			namespace:          "foo",
+			namespacedResource: true,
+			resourceName:       "orders",
+			verifyResourceName: true,
+		},
+		{
+			scenario:           "Should accept object existing in any namespace",
+			namespacedResource: true,
+			resourceName:       "payments",
+			verifyResourceName: true,
+		},
+		{
+			scenario:           "Should not verify resource name unless requested",
+			namespace:          "foo",
+			namespacedResource: true,
+			resourceName:       "payments",
+		},
+		{
+			scenario:           "Should return error when object does not exist in namespace",
+			namespace:          "foo",
+			namespacedResource: true,
+			resourceName:       "payments",
+			verifyResourceName: true,
+			expectedErr:        errors.New("databases.example.com \"payments\" not found in the foo namespace"),
+		},
+		{
+			scenario:           "Should return error when object does not exist in any namespace",
+			namespacedResource: true,
+			resourceName:       "accounts",
+			verifyResourceName: true,
+			expectedErr:        errors.New("databases.example.com \"accounts\" not found"),
+		},
+		{
+			scenario:              "Should replace selector by names of matching objects in namespace",
+			namespace:             "foo",
+			namespacedResource:    true,
+			selector:              "tier=backend",
+			expectedResourceNames: []string{"orders", "users"},
+		},
+		{
+			scenario:              "Should replace selector by names of matching objects in all namespaces",
+			namespacedResource:    true,
+			selector:              "tier=backend",
+			expectedResourceNames: []string{"orders", "payments", "users"},
+		},
+		{
+			scenario:           "Should return error when no object matches selector",
+			namespace:          "foo",
+			namespacedResource: true,
+			selector:           "tier=cache",
+			expectedErr:        errors.New("no databases.example.com matched the selector \"tier=cache\""),
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			wc := whoCan{
+				resource:           "databases",
+				apiGroup:           "example.com",
+				apiVersion:         "v1",
+				namespace:          tt.namespace,
+				namespacedResource: tt.namespacedResource,
+				resourceName:       tt.resourceName,
+				verifyResourceName: tt.verifyResourceName,
+				selector:           tt.selector,
+				objectLookup:       newDatabaseLookup(),
+			}
+
+			// when
+			err := wc.lookupObjects()
+
+			// then
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedResourceNames, wc.resourceNames)
+		})
+	}
+
+	t.Run("Should return error when objects cannot be listed", func(t *testing.T) {
+		// given
+		wc := whoCan{
+			resource:     "databases",
+			apiGroup:     "example.com",
+			apiVersion:   "v1",
+			selector:     "tier=backend",
+			objectLookup: &objectLookupMock{err: errors.New("forbidden")},
+		}
+
+		// when
+		err := wc.lookupObjects()
+
+		// then
+		assert.EqualError(t, err, "listing databases.example.com: forbidden")
+	})
+}
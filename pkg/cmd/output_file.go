@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	rbac "k8s.io/api/rbac/v1"
+)
+
+// writeOutputFile writes the matched bindings in the format selected by --output to the --output-file.
+func (w *whoCan) writeOutputFile(roleBindings []rbac.RoleBinding, clusterRoleBindings []rbac.ClusterRoleBinding, warnings, masters []string) error {
+	f, err := os.Create(w.outputFile)
+	if err != nil {
+		return fmt.Errorf("creating output file: %v", err)
+	}
+
+	out := w.Out
+	w.Out = f
+	err = w.printStructured(roleBindings, clusterRoleBindings, warnings, masters)
+	w.Out = out
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("writing output file: %v", err)
+	}
+	return nil
+}
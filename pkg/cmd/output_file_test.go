@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWhoCan_Check_OutputFile(t *testing.T) {
+	// given
+	dir, err := ioutil.TempDir("", "who-can")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	outputFile := filepath.Join(dir, "out.json")
+
+	client := fake.NewSimpleClientset(
+		&rbac.Role{
+			ObjectMeta: meta.ObjectMeta{Name: "view-pods", Namespace: "foo"},
+			Rules:      []rbac.PolicyRule{{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"pods"}}},
+		},
+		&rbac.RoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "Alice-can-view-pods", Namespace: "foo"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "view-pods"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "Alice"}},
+		},
+	)
+	accessChecker := new(accessCheckerMock)
+	for _, resource := range namespacedAPIAccess {
+		accessChecker.On("IsAllowedTo", "list", rbac.GroupName, resource, "foo").Return(true, nil)
+	}
+
+	streams, _, out, _ := clioptions.NewTestIOStreams()
+	wc := whoCan{
+		verb:               "get",
+		resource:           "pods",
+		namespacedResource: true,
+		namespace:          "foo",
+		outputFormat:       outputJSON,
+		outputVersion:      outputVersionV1,
+		outputFile:         outputFile,
+		clientRBAC:         client.RbacV1(),
+		accessChecker:      accessChecker,
+		clock:              clock.NewFakeClock(time.Date(2019, 6, 12, 12, 30, 0, 0, time.UTC)),
+		IOStreams:          streams,
+	}
+
+	// when
+	err = wc.Check()
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, `ROLEBINDING          NAMESPACE  SUBJECT  TYPE  SA-NAMESPACE
+Alice-can-view-pods  foo        Alice    User  
+
+No subjects found with permissions to get pods assigned through ClusterRoleBindings
+`, out.String())
+
+	data, err := ioutil.ReadFile(outputFile)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "apiVersion": "whocan.dev/v1",
+  "kind": "WhoCanResult",
+  "metadata": {"timestamp": "2019-06-12T12:30:00Z"},
+  "query": {
+    "verb": "get",
+    "resource": "pods",
+    "namespace": "foo",
+    "resolved": {"resource": "pods", "apiGroup": "", "namespaced": true}
+  },
+  "roleBindings": [
+    {
+      "name": "Alice-can-view-pods",
+      "namespace": "foo",
+      "roleRef": {"kind": "Role", "name": "view-pods"},
+      "wildcard": false,
+      "subjects": [{"kind": "User", "name": "Alice"}]
+    }
+  ],
+  "clusterRoleBindings": []
+}`, string(data))
+}
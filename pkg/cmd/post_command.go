@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// runWithPostCommand runs the query with its standard output captured, and then pipes the captured output to the
+// standard input of the --post-command, such as `aws s3 cp - s3://bucket/who-can.json`. This ships the results to
+// any storage without building its SDK into the plugin. The command is run by the shell with the standard output and
+// the standard error of the plugin.
+//
+// The command is not run if the query fails, in which case the captured output is printed as is. A failing command
+// fails the plugin with the exit code of errors, which is never mistaken for the ExitCodeNoSubjects of the query.
+func (w *whoCan) runWithPostCommand(run func() error) error {
+	if w.postCommand == "" {
+		return run()
+	}
+
+	var captured bytes.Buffer
+	out := w.Out
+	w.Out = &captured
+	err := run()
+	w.Out = out
+
+	// A query which finds no subjects still renders its output, hence it is piped to the command as well.
+	if _, ok := err.(*ExitError); err != nil && !ok {
+		_, _ = out.Write(captured.Bytes())
+		return err
+	}
+
+	c := shellCommand(w.postCommand)
+	c.Stdin = &captured
+	c.Stdout = out
+	c.Stderr = w.ErrOut
+	if runErr := c.Run(); runErr != nil {
+		if exitErr, ok := runErr.(*exec.ExitError); ok {
+			return fmt.Errorf("--post-command exited with code %d", exitErr.ExitCode())
+		}
+		return fmt.Errorf("running --post-command: %v", runErr)
+	}
+	return err
+}
+
+// shellCommand returns the command which runs the given command line with the shell of the platform.
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
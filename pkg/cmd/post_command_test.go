@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"runtime"
+	"testing"
+)
+
+func TestWhoCan_runWithPostCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the post commands of the test require a POSIX shell")
+	}
+
+	data := []struct {
+		scenario    string
+		postCommand string
+		runErr      error
+
+		expectedOut    string
+		expectedErrOut string
+		expectedErr    error
+	}{
+		{
+			scenario:    "Should print output without post command",
+			expectedOut: "ROLEBINDING  NAMESPACE\n",
+		},
+		{
+			scenario:    "Should pipe output to post command",
+			postCommand: "echo piped: && cat",
+			expectedOut: "piped:\nROLEBINDING  NAMESPACE\n",
+		},
+		{
+			scenario:    "Should pipe output to post command when no subjects are found",
+			postCommand: "echo piped: && cat",
+			runErr:      &ExitError{Code: ExitCodeNoSubjects, Reason: "no subjects found"},
+			expectedOut: "piped:\nROLEBINDING  NAMESPACE\n",
+			expectedErr: &ExitError{Code: ExitCodeNoSubjects, Reason: "no subjects found"},
+		},
+		{
+			scenario:       "Should return error with exit code of failing post command",
+			postCommand:    "cat > /dev/null; echo upload failed >&2; exit 2",
+			expectedErrOut: "upload failed\n",
+			expectedErr:    errors.New("--post-command exited with code 2"),
+		},
+		{
+			scenario:    "Should print output without running post command when query fails",
+			postCommand: "echo piped:",
+			runErr:      errors.New("checking API access: boom"),
+			expectedOut: "ROLEBINDING  NAMESPACE\n",
+			expectedErr: errors.New("checking API access: boom"),
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			streams, _, out, errOut := clioptions.NewTestIOStreams()
+			wc := whoCan{postCommand: tt.postCommand, IOStreams: streams}
+
+			// when
+			err := wc.runWithPostCommand(func() error {
+				_, _ = fmt.Fprintln(wc.Out, "ROLEBINDING  NAMESPACE")
+				return tt.runErr
+			})
+
+			// then
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedOut, out.String())
+			assert.Equal(t, tt.expectedErrOut, errOut.String())
+		})
+	}
+}
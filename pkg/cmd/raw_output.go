@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// rawList is a List of API objects, such as printed by `kubectl get -o json`.
+type rawList struct {
+	APIVersion string        `json:"apiVersion"`
+	Kind       string        `json:"kind"`
+	Items      []interface{} `json:"items"`
+}
+
+// printRawBindings writes the given bindings to the standard output as a List of API objects.
+// Unless --keep-managed-fields is set, the metadata which changes with every update of an object
+// is removed so that the output of consecutive runs can be archived and compared.
+func (w *whoCan) printRawBindings(roleBindings []rbac.RoleBinding, clusterRoleBindings []rbac.ClusterRoleBinding) error {
+	list := rawList{APIVersion: "v1", Kind: "List", Items: []interface{}{}}
+	for _, rb := range roleBindings {
+		rb.TypeMeta = meta.TypeMeta{APIVersion: rbac.SchemeGroupVersion.String(), Kind: "RoleBinding"}
+		w.cleanupMetadata(&rb.ObjectMeta)
+		list.Items = append(list.Items, rb)
+	}
+	for _, crb := range clusterRoleBindings {
+		crb.TypeMeta = meta.TypeMeta{APIVersion: rbac.SchemeGroupVersion.String(), Kind: "ClusterRoleBinding"}
+		w.cleanupMetadata(&crb.ObjectMeta)
+		list.Items = append(list.Items, crb)
+	}
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling bindings: %v", err)
+	}
+	_, err = fmt.Fprintln(w.Out, string(data))
+	return err
+}
+
+func (w *whoCan) cleanupMetadata(m *meta.ObjectMeta) {
+	if w.keepManagedFields {
+		return
+	}
+	m.ManagedFields = nil
+	m.Generation = 0
+	m.ResourceVersion = ""
+}
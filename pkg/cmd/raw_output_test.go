@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"testing"
+)
+
+func TestWhoCan_printRawBindings(t *testing.T) {
+	metadata := meta.ObjectMeta{
+		Name:            "Alice-can-view-pods",
+		Namespace:       "default",
+		ResourceVersion: "4711",
+		Generation:      3,
+		ManagedFields:   []meta.ManagedFieldsEntry{{Manager: "kubectl", Operation: meta.ManagedFieldsOperationApply}},
+	}
+	roleBindings := []rbac.RoleBinding{
+		{
+			ObjectMeta: metadata,
+			RoleRef:    rbac.RoleRef{APIGroup: rbac.GroupName, Kind: "Role", Name: "view-pods"},
+			Subjects:   []rbac.Subject{{Kind: "User", Name: "Alice"}},
+		},
+	}
+
+	data := []struct {
+		scenario          string
+		keepManagedFields bool
+		output            string
+	}{
+		{
+			scenario: "Should remove managed metadata by default",
+			output: `{
+  "apiVersion": "v1",
+  "kind": "List",
+  "items": [
+    {
+      "kind": "RoleBinding",
+      "apiVersion": "rbac.authorization.k8s.io/v1",
+      "metadata": {
+        "name": "Alice-can-view-pods",
+        "namespace": "default",
+        "creationTimestamp": null
+      },
+      "subjects": [
+        {
+          "kind": "User",
+          "name": "Alice"
+        }
+      ],
+      "roleRef": {
+        "apiGroup": "rbac.authorization.k8s.io",
+        "kind": "Role",
+        "name": "view-pods"
+      }
+    }
+  ]
+}
+`,
+		},
+		{
+			scenario:          "Should keep managed metadata when requested",
+			keepManagedFields: true,
+			output: `{
+  "apiVersion": "v1",
+  "kind": "List",
+  "items": [
+    {
+      "kind": "RoleBinding",
+      "apiVersion": "rbac.authorization.k8s.io/v1",
+      "metadata": {
+        "name": "Alice-can-view-pods",
+        "namespace": "default",
+        "resourceVersion": "4711",
+        "generation": 3,
+        "creationTimestamp": null,
+        "managedFields": [
+          {
+            "manager": "kubectl",
+            "operation": "Apply"
+          }
+        ]
+      },
+      "subjects": [
+        {
+          "kind": "User",
+          "name": "Alice"
+        }
+      ],
+      "roleRef": {
+        "apiGroup": "rbac.authorization.k8s.io",
+        "kind": "Role",
+        "name": "view-pods"
+      }
+    }
+  ]
+}
+`,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			streams, _, out, _ := clioptions.NewTestIOStreams()
+			wc := whoCan{keepManagedFields: tt.keepManagedFields, IOStreams: streams}
+
+			// when
+			err := wc.printRawBindings(roleBindings, nil)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.output, out.String())
+			assert.Equal(t, "4711", roleBindings[0].ResourceVersion)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	core "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	rbacManifestUsage = `rbac-manifest`
+	rbacManifestLong  = `Prints the Role and ClusterRole manifests required by the current identity to run complete who-can queries.
+
+The manifests grant the same permissions that are checked by who-can before it lists Roles and RoleBindings.`
+	rbacManifestExample = `  # Print the manifests required to run who-can in the current namespace
+  kubectl who-can rbac-manifest
+
+  # Print the manifests required to run who-can in namespace "foo"
+  kubectl who-can rbac-manifest -n foo
+
+  # Print the manifest required to run who-can in all namespaces
+  kubectl who-can rbac-manifest --all-namespaces | kubectl apply -f -`
+
+	rbacManifestName = "kubectl-who-can"
+)
+
+var (
+	// namespacedAPIAccess lists the namespaced resources that who-can lists in each queried namespace.
+	namespacedAPIAccess = []string{"roles", "rolebindings"}
+	// clusterAPIAccess lists the cluster-scoped resources that who-can lists.
+	clusterAPIAccess = []string{"clusterroles", "clusterrolebindings"}
+)
+
+type rbacManifest struct {
+	namespace     string
+	allNamespaces bool
+
+	configFlags  *clioptions.ConfigFlags
+	clientConfig clientcmd.ClientConfig
+
+	clioptions.IOStreams
+}
+
+func NewCmdRBACManifest(configFlags *clioptions.ConfigFlags, streams clioptions.IOStreams) *cobra.Command {
+	o := &rbacManifest{
+		configFlags:  configFlags,
+		clientConfig: configFlags.ToRawKubeConfigLoader(),
+		IOStreams:    streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:          rbacManifestUsage,
+		Short:        "Print the RBAC manifests required to run who-can",
+		Long:         rbacManifestLong,
+		Example:      rbacManifestExample,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			o.namespace, err = resolveNamespace(o.configFlags, o.clientConfig, o.allNamespaces)
+			if err != nil {
+				return err
+			}
+			return o.print()
+		},
+	}
+
+	cmd.Flags().BoolVarP(&o.allNamespaces, "all-namespaces", "A", false,
+		"If true, print the manifest required to run who-can in all namespaces.")
+
+	return cmd
+}
+
+// print writes the manifests as a multi-document YAML to the standard output.
+func (m *rbacManifest) print() error {
+	for i, obj := range m.manifests() {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("marshalling manifest: %v", err)
+		}
+		if i > 0 {
+			_, _ = fmt.Fprintln(m.Out, "---")
+		}
+		_, _ = m.Out.Write(data)
+	}
+	return nil
+}
+
+// manifests returns the ClusterRole, and the Role unless all namespaces are queried,
+// which grant the permissions that who-can requires.
+func (m *rbacManifest) manifests() []interface{} {
+	listRule := func(resources []string) rbac.PolicyRule {
+		return rbac.PolicyRule{
+			APIGroups: []string{rbac.GroupName},
+			Resources: resources,
+			Verbs:     []string{"list"},
+		}
+	}
+
+	clusterRole := &rbac.ClusterRole{
+		TypeMeta:   meta.TypeMeta{APIVersion: rbac.SchemeGroupVersion.String(), Kind: "ClusterRole"},
+		ObjectMeta: meta.ObjectMeta{Name: rbacManifestName},
+	}
+
+	if m.namespace == core.NamespaceAll {
+		clusterRole.Rules = []rbac.PolicyRule{
+			{APIGroups: []string{core.GroupName}, Resources: []string{"namespaces"}, Verbs: []string{"get", "list"}},
+			listRule(append(append([]string{}, namespacedAPIAccess...), clusterAPIAccess...)),
+		}
+		return []interface{}{clusterRole}
+	}
+
+	clusterRole.Rules = []rbac.PolicyRule{
+		{APIGroups: []string{core.GroupName}, Resources: []string{"namespaces"}, Verbs: []string{"get"}},
+		listRule(clusterAPIAccess),
+	}
+	role := &rbac.Role{
+		TypeMeta:   meta.TypeMeta{APIVersion: rbac.SchemeGroupVersion.String(), Kind: "Role"},
+		ObjectMeta: meta.ObjectMeta{Name: rbacManifestName, Namespace: m.namespace},
+		Rules:      []rbac.PolicyRule{listRule(namespacedAPIAccess)},
+	}
+	return []interface{}{clusterRole, role}
+}
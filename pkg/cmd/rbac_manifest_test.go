@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	core "k8s.io/api/core/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"testing"
+)
+
+func TestRBACManifest_print(t *testing.T) {
+	data := []struct {
+		scenario  string
+		namespace string
+		output    string
+	}{
+		{
+			scenario:  "Should print ClusterRole and Role when namespace is specified",
+			namespace: "foo",
+			output: `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  creationTimestamp: null
+  name: kubectl-who-can
+rules:
+- apiGroups:
+  - ""
+  resources:
+  - namespaces
+  verbs:
+  - get
+- apiGroups:
+  - rbac.authorization.k8s.io
+  resources:
+  - clusterroles
+  - clusterrolebindings
+  verbs:
+  - list
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  creationTimestamp: null
+  name: kubectl-who-can
+  namespace: foo
+rules:
+- apiGroups:
+  - rbac.authorization.k8s.io
+  resources:
+  - roles
+  - rolebindings
+  verbs:
+  - list
+`,
+		},
+		{
+			scenario:  "Should print ClusterRole when all namespaces are specified",
+			namespace: core.NamespaceAll,
+			output: `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  creationTimestamp: null
+  name: kubectl-who-can
+rules:
+- apiGroups:
+  - ""
+  resources:
+  - namespaces
+  verbs:
+  - get
+  - list
+- apiGroups:
+  - rbac.authorization.k8s.io
+  resources:
+  - roles
+  - rolebindings
+  - clusterroles
+  - clusterrolebindings
+  verbs:
+  - list
+`,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			streams, _, out, _ := clioptions.NewTestIOStreams()
+			m := rbacManifest{
+				namespace: tt.namespace,
+				IOStreams: streams,
+			}
+
+			// when
+			err := m.print()
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.output, out.String())
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// reviewGroups are the API groups of the review resources, which are created to evaluate a request,
+// but are never persisted, hence creating them does not modify the cluster.
+var reviewGroups = map[string]struct{}{
+	"authorization.k8s.io":  {},
+	"authentication.k8s.io": {},
+}
+
+// reviewResources are the review resources served by the reviewGroups.
+var reviewResources = map[string]struct{}{
+	"selfsubjectaccessreviews":  {},
+	"subjectaccessreviews":      {},
+	"localsubjectaccessreviews": {},
+	"selfsubjectrulesreviews":   {},
+	"tokenreviews":              {},
+}
+
+// readOnlyGuard rejects the requests to the API server which could modify the cluster when --read-only is set,
+// so that only get and list requests, and the creation of access and token reviews, are sent.
+//
+// The clients are created before the flags are parsed, hence the flag is read when a request is sent.
+type readOnlyGuard struct {
+	enabled *bool
+}
+
+// wrap returns a RoundTripper which guards the requests sent with the given RoundTripper.
+// It is meant to be set as the WrapTransport of the REST config used to build the clients.
+func (g *readOnlyGuard) wrap(rt http.RoundTripper) http.RoundTripper {
+	return &readOnlyRoundTripper{delegate: rt, guard: g}
+}
+
+// allows returns `true` if the given request may be sent.
+func (g *readOnlyGuard) allows(req *http.Request) bool {
+	if g.enabled == nil || !*g.enabled {
+		return true
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	case http.MethodPost:
+		return isReviewPath(req.URL.Path)
+	}
+	return false
+}
+
+// isReviewPath returns `true` if the given URL path refers to a review resource, such as
+// /apis/authorization.k8s.io/v1/selfsubjectaccessreviews or
+// /apis/authorization.k8s.io/v1/namespaces/foo/localsubjectaccessreviews.
+func isReviewPath(path string) bool {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) < 4 || parts[0] != "apis" {
+		return false
+	}
+	if _, ok := reviewGroups[parts[1]]; !ok {
+		return false
+	}
+	_, ok := reviewResources[parts[len(parts)-1]]
+	return ok
+}
+
+type readOnlyRoundTripper struct {
+	delegate http.RoundTripper
+	guard    *readOnlyGuard
+}
+
+func (rt *readOnlyRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !rt.guard.allows(req) {
+		return nil, fmt.Errorf("refusing to send %s %s in read-only mode", req.Method, req.URL.Path)
+	}
+	return rt.delegate.RoundTrip(req)
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authz "k8s.io/api/authorization/v1"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReadOnlyGuard_Mutation(t *testing.T) {
+	data := []struct {
+		scenario string
+		readOnly bool
+
+		expectedErr      string
+		expectedRequests []string
+	}{
+		{
+			scenario:    "Should block mutation in read-only mode",
+			readOnly:    true,
+			expectedErr: "refusing to send POST /api/v1/namespaces/default/events in read-only mode",
+			expectedRequests: []string{
+				"GET /api/v1/namespaces",
+				"POST /apis/authorization.k8s.io/v1/selfsubjectaccessreviews",
+			},
+		},
+		{
+			scenario: "Should send mutation without read-only mode",
+			readOnly: false,
+			expectedRequests: []string{
+				"GET /api/v1/namespaces",
+				"POST /apis/authorization.k8s.io/v1/selfsubjectaccessreviews",
+				"POST /api/v1/namespaces/default/events",
+			},
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			var requests []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			guard := &readOnlyGuard{enabled: &tt.readOnly}
+			client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL, WrapTransport: guard.wrap})
+			require.NoError(t, err)
+
+			// when
+			_, listErr := client.CoreV1().Namespaces().List(meta.ListOptions{})
+			_, reviewErr := client.AuthorizationV1().SelfSubjectAccessReviews().Create(&authz.SelfSubjectAccessReview{})
+			_, createErr := client.CoreV1().Events("default").Create(&core.Event{ObjectMeta: meta.ObjectMeta{Namespace: "default"}})
+
+			// then
+			assert.NoError(t, listErr)
+			assert.NoError(t, reviewErr)
+			if tt.expectedErr != "" {
+				assert.Error(t, createErr)
+				assert.Contains(t, createErr.Error(), tt.expectedErr)
+			} else {
+				assert.NoError(t, createErr)
+			}
+			assert.Equal(t, tt.expectedRequests, requests)
+		})
+	}
+}
+
+func TestIsReviewPath(t *testing.T) {
+	data := []struct {
+		path     string
+		expected bool
+	}{
+		{path: "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews", expected: true},
+		{path: "/apis/authorization.k8s.io/v1/namespaces/foo/localsubjectaccessreviews", expected: true},
+		{path: "/apis/authentication.k8s.io/v1/tokenreviews", expected: true},
+		{path: "/apis/rbac.authorization.k8s.io/v1/clusterroles", expected: false},
+		{path: "/apis/example.com/v1/selfsubjectaccessreviews", expected: false},
+		{path: "/api/v1/namespaces/default/events", expected: false},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isReviewPath(tt.path))
+		})
+	}
+}
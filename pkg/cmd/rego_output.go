@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// regoPackage is the package of the Rego module printed by the rego output format.
+const regoPackage = "whocan"
+
+// regoGrant is a single fact of the rego output: a subject granted the queried action by a binding.
+// The namespace is empty for ClusterRoleBindings, which grant the action in all namespaces.
+type regoGrant struct {
+	BindingKind      string `json:"bindingKind"`
+	Binding          string `json:"binding"`
+	Namespace        string `json:"namespace"`
+	RoleKind         string `json:"roleKind"`
+	Role             string `json:"role"`
+	SubjectKind      string `json:"subjectKind"`
+	Subject          string `json:"subject"`
+	SubjectNamespace string `json:"subjectNamespace"`
+	Wildcard         bool   `json:"wildcard"`
+}
+
+// printRego writes the Result as a Rego module which can be loaded into OPA, e.g. with `opa eval -d`.
+// The module defines the `query` that was checked, the `grants` that match it and the `warnings` about
+// missing permissions, so that policies can refer to them as `data.whocan.grants` and so on.
+func (w *whoCan) printRego(out io.Writer, result Result) error {
+	grants := result.regoGrants()
+	warnings := result.Warnings
+	if warnings == nil {
+		warnings = []string{}
+	}
+
+	_, _ = fmt.Fprintln(out, "# Generated by kubectl who-can. Each grant is a subject granted the queried action by a binding.")
+	_, _ = fmt.Fprintf(out, "package %s\n", regoPackage)
+	for _, rule := range []struct {
+		name  string
+		value interface{}
+	}{
+		{"query", result.Query},
+		{"grants", grants},
+		{"warnings", warnings},
+	} {
+		data, err := json.MarshalIndent(rule.value, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshalling %s: %v", rule.name, err)
+		}
+		_, _ = fmt.Fprintf(out, "\n%s = %s\n", rule.name, data)
+	}
+	return nil
+}
+
+// regoGrants flattens the bindings of the Result into one grant per subject.
+func (r Result) regoGrants() []regoGrant {
+	grants := []regoGrant{}
+	add := func(bindingKind string, bindings []Binding) {
+		for _, b := range bindings {
+			for _, s := range b.Subjects {
+				grants = append(grants, regoGrant{
+					BindingKind:      bindingKind,
+					Binding:          b.Name,
+					Namespace:        b.Namespace,
+					RoleKind:         b.RoleRef.Kind,
+					Role:             b.RoleRef.Name,
+					SubjectKind:      s.Kind,
+					Subject:          s.Name,
+					SubjectNamespace: s.Namespace,
+					Wildcard:         b.Wildcard,
+				})
+			}
+		}
+	}
+	add("RoleBinding", r.RoleBindings)
+	add("ClusterRoleBinding", r.ClusterRoleBindings)
+	return grants
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"testing"
+)
+
+func TestWhoCan_printRego(t *testing.T) {
+	roleBindings := []rbac.RoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Alice-can-view-pods", Namespace: "default"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "view-pods"},
+			Subjects: []rbac.Subject{
+				{Name: "Alice", Kind: "User"},
+			},
+		},
+	}
+	clusterRoleBindings := []rbac.ClusterRoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Bob-can-view-pods"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "view"},
+			Subjects: []rbac.Subject{
+				{Name: "Bob", Kind: "ServiceAccount", Namespace: "foo"},
+			},
+		},
+	}
+
+	data := []struct {
+		scenario string
+
+		roleBindings        []rbac.RoleBinding
+		clusterRoleBindings []rbac.ClusterRoleBinding
+		warnings            []string
+
+		output string
+	}{
+		{
+			scenario:            "Should print grants as Rego facts",
+			roleBindings:        roleBindings,
+			clusterRoleBindings: clusterRoleBindings,
+			warnings:            []string{"The user is not allowed to list roles in the default namespace"},
+			output: `# Generated by kubectl who-can. Each grant is a subject granted the queried action by a binding.
+package whocan
+
+query = {
+  "verb": "get",
+  "resource": "pods",
+  "namespace": "default",
+  "resolved": {
+    "resource": "pods",
+    "apiGroup": "",
+    "namespaced": true
+  }
+}
+
+grants = [
+  {
+    "bindingKind": "RoleBinding",
+    "binding": "Alice-can-view-pods",
+    "namespace": "default",
+    "roleKind": "Role",
+    "role": "view-pods",
+    "subjectKind": "User",
+    "subject": "Alice",
+    "subjectNamespace": "",
+    "wildcard": false
+  },
+  {
+    "bindingKind": "ClusterRoleBinding",
+    "binding": "Bob-can-view-pods",
+    "namespace": "",
+    "roleKind": "ClusterRole",
+    "role": "view",
+    "subjectKind": "ServiceAccount",
+    "subject": "Bob",
+    "subjectNamespace": "foo",
+    "wildcard": true
+  }
+]
+
+warnings = [
+  "The user is not allowed to list roles in the default namespace"
+]
+`,
+		},
+		{
+			scenario: "Should print empty facts",
+			output: `# Generated by kubectl who-can. Each grant is a subject granted the queried action by a binding.
+package whocan
+
+query = {
+  "verb": "get",
+  "resource": "pods",
+  "namespace": "default",
+  "resolved": {
+    "resource": "pods",
+    "apiGroup": "",
+    "namespaced": true
+  }
+}
+
+grants = []
+
+warnings = []
+`,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			streams, _, out, _ := clioptions.NewTestIOStreams()
+			wc := whoCan{
+				verb:               "get",
+				resource:           "pods",
+				namespacedResource: true,
+				namespace:          "default",
+				outputFormat:       outputRego,
+				wildcards:          map[role]bool{{name: "view", isClusterRole: true}: true},
+				IOStreams:          streams,
+			}
+
+			// when
+			err := wc.printResult(wc.newResult(tt.roleBindings, tt.clusterRoleBindings, tt.warnings))
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.output, out.String())
+		})
+	}
+}
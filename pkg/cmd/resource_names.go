@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	rbac "k8s.io/api/rbac/v1"
+)
+
+const (
+	resourceNameMatchAny = "any"
+	resourceNameMatchAll = "all"
+)
+
+// readResourceNames reads the resource names listed one per line in the given file.
+// Blank lines and lines starting with # are ignored.
+func readResourceNames(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var names []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		name := strings.TrimSpace(scanner.Text())
+		if name == "" || strings.HasPrefix(name, "#") {
+			continue
+		}
+		names = append(names, name)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// getBindingsForResourceNames matches the bindings for each of the resource names separately and aggregates them.
+//
+// With the `any` match every subject granted access to at least one of the names is kept.
+// With the `all` match only the subjects granted access to every name, possibly through different bindings, are kept.
+func (w *whoCan) getBindingsForResourceNames() ([]rbac.RoleBinding, []rbac.ClusterRoleBinding, error) {
+	defer func(name string) { w.resourceName = name }(w.resourceName)
+
+	return w.aggregateBindings(len(w.resourceNames), func(i int) {
+		w.resourceName = w.resourceNames[i]
+	}, w.resourceNameMatch == resourceNameMatchAll)
+}
+
+// aggregateBindings matches the bindings for each of the n queries selected by the given function and aggregates
+// them. If all is true, only the subjects matched by every query, possibly through different bindings, are kept.
+func (w *whoCan) aggregateBindings(n int, query func(i int), all bool) ([]rbac.RoleBinding, []rbac.ClusterRoleBinding, error) {
+	var roleBindings []rbac.RoleBinding
+	var clusterRoleBindings []rbac.ClusterRoleBinding
+	seenRoleBindings := make(map[string]struct{})
+	seenClusterRoleBindings := make(map[string]struct{})
+	// grants counts the number of queries by which each subject is matched.
+	grants := make(map[rbac.Subject]int)
+
+	for i := 0; i < n; i++ {
+		query(i)
+		rbs, crbs, err := w.getBindings()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		granted := make(map[rbac.Subject]struct{})
+		for _, rb := range rbs {
+			for _, s := range rb.Subjects {
+				granted[s] = struct{}{}
+			}
+			key := rb.Namespace + "/" + rb.Name
+			if _, ok := seenRoleBindings[key]; !ok {
+				seenRoleBindings[key] = struct{}{}
+				roleBindings = append(roleBindings, rb)
+			}
+		}
+		for _, crb := range crbs {
+			for _, s := range crb.Subjects {
+				granted[s] = struct{}{}
+			}
+			if _, ok := seenClusterRoleBindings[crb.Name]; !ok {
+				seenClusterRoleBindings[crb.Name] = struct{}{}
+				clusterRoleBindings = append(clusterRoleBindings, crb)
+			}
+		}
+		for s := range granted {
+			grants[s]++
+		}
+	}
+
+	if !all {
+		return roleBindings, clusterRoleBindings, nil
+	}
+
+	grantedAll := func(subjects []rbac.Subject) []rbac.Subject {
+		var kept []rbac.Subject
+		for _, s := range subjects {
+			if grants[s] == n {
+				kept = append(kept, s)
+			}
+		}
+		return kept
+	}
+
+	var allRoleBindings []rbac.RoleBinding
+	for _, rb := range roleBindings {
+		if rb.Subjects = grantedAll(rb.Subjects); len(rb.Subjects) > 0 {
+			allRoleBindings = append(allRoleBindings, rb)
+		}
+	}
+	var allClusterRoleBindings []rbac.ClusterRoleBinding
+	for _, crb := range clusterRoleBindings {
+		if crb.Subjects = grantedAll(crb.Subjects); len(crb.Subjects) > 0 {
+			allClusterRoleBindings = append(allClusterRoleBindings, crb)
+		}
+	}
+	return allRoleBindings, allClusterRoleBindings, nil
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"os"
+	"testing"
+
+	rbac "k8s.io/api/rbac/v1"
+)
+
+func TestReadResourceNames(t *testing.T) {
+	// given
+	f, err := ioutil.TempFile("", "names")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString("# sensitive secrets\nfoo\n\n  bar  \n#baz\nqux\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	// when
+	names, err := readResourceNames(f.Name())
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"foo", "bar", "qux"}, names)
+}
+
+func TestWhoCan_getBindingsForResourceNames(t *testing.T) {
+	const namespace = "foo"
+
+	newRole := func(name string, resourceNames ...string) *rbac.Role {
+		return &rbac.Role{
+			ObjectMeta: meta.ObjectMeta{Name: name, Namespace: namespace},
+			Rules: []rbac.PolicyRule{
+				{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"secrets"}, ResourceNames: resourceNames},
+			},
+		}
+	}
+	newRoleBinding := func(name, role string, subjects ...string) *rbac.RoleBinding {
+		rb := &rbac.RoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: name, Namespace: namespace},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: role},
+		}
+		for _, s := range subjects {
+			rb.Subjects = append(rb.Subjects, rbac.Subject{Kind: rbac.UserKind, Name: s})
+		}
+		return rb
+	}
+	subjectsOf := func(rbs []rbac.RoleBinding) map[string][]string {
+		subjects := make(map[string][]string)
+		for _, rb := range rbs {
+			for _, s := range rb.Subjects {
+				subjects[rb.Name] = append(subjects[rb.Name], s.Name)
+			}
+		}
+		return subjects
+	}
+
+	objects := []runtime.Object{
+		newRole("secret-a", "a"),
+		newRole("secret-b", "b"),
+		newRole("secret-ab", "a", "b"),
+		newRole("secret-c", "c"),
+		newRoleBinding("rb-a", "secret-a", "Alice", "Dave"),
+		newRoleBinding("rb-b", "secret-b", "Carol", "Alice"),
+		newRoleBinding("rb-ab", "secret-ab", "Bob"),
+		newRoleBinding("rb-c", "secret-c", "Eve"),
+	}
+
+	data := []struct {
+		scenario string
+		match    string
+		expected map[string][]string
+	}{
+		{
+			scenario: "Should return subjects who can access any of the resource names",
+			match:    resourceNameMatchAny,
+			expected: map[string][]string{
+				"rb-a":  {"Alice", "Dave"},
+				"rb-b":  {"Carol", "Alice"},
+				"rb-ab": {"Bob"},
+			},
+		},
+		{
+			scenario: "Should return subjects who can access all the resource names",
+			match:    resourceNameMatchAll,
+			expected: map[string][]string{
+				"rb-a":  {"Alice"},
+				"rb-b":  {"Alice"},
+				"rb-ab": {"Bob"},
+			},
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			client := fake.NewSimpleClientset(objects...)
+			wc := whoCan{
+				verb:              "get",
+				resource:          "secrets",
+				namespace:         namespace,
+				resourceNames:     []string{"a", "b"},
+				resourceNameMatch: tt.match,
+				clientRBAC:        client.RbacV1(),
+				rules:             make(map[role][]rbac.PolicyRule),
+				wildcards:         make(map[role]bool),
+			}
+
+			// when
+			roleBindings, clusterRoleBindings, err := wc.getBindingsForResourceNames()
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, subjectsOf(roleBindings))
+			assert.Empty(t, clusterRoleBindings)
+			assert.Equal(t, "", wc.resourceName)
+		})
+	}
+}
@@ -2,25 +2,81 @@ package cmd
 
 import (
 	"fmt"
+	"github.com/golang/glog"
 	rbac "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	apismeta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
+	"sort"
+	"strings"
+	"sync"
 )
 
 // ResourceResolver wraps the Resolve method.
 //
-// Resolve attempts to resolve an APIResource's Name by `resource` and `subResource`.
+// Resolve attempts to resolve an APIResource's Name and API group by `resource` and `subResource`.
+// The `resource` may be qualified with a version and an API group, e.g. `deployments.v1.apps`.
 // It then validates that the specified `verb` is supported.
 // The returned APIResource's Name may represent a resource (e.g. `pods`) or a sub-resource (e.g. `pods/log`).
+//
+// SupportedVerbs resolves the `resource` and returns the verbs advertised for it by the API discovery,
+// followed by the verbs which are enforced by the RBAC authorizer only.
+//
+// ResourcesInCategory returns the resources which belong to the given `category`, such as `all`,
+// sorted by their group-qualified names.
 type ResourceResolver interface {
-	Resolve(verb, resource, subResource string) (string, error)
+	Resolve(verb, resource, subResource string) (ResolvedResource, error)
+	SupportedVerbs(resource string) (schema.GroupResource, []string, error)
+	ResourcesInCategory(category string) ([]ResolvedResource, error)
+}
+
+// ResolvedResource is a resource, or a sub-resource, resolved by the ResourceResolver.
+type ResolvedResource struct {
+	Resource string
+	Group    string
+	// Version is the served version of the API group in which the resource was resolved.
+	Version string
+	// Namespaced tells whether the objects of the resource are namespaced rather than cluster-scoped.
+	Namespaced bool
+}
+
+// String returns the resource qualified with its API group, such as `deployments.apps`.
+func (r ResolvedResource) String() string {
+	return qualifiedName(r.Resource, r.Group)
+}
+
+// maxConcurrentDiscoveryRequests limits the number of API group versions whose resources are fetched in parallel.
+const maxConcurrentDiscoveryRequests = 10
+
+// coreGroup is the name by which the core API group, whose actual name is empty, is referred to by the --apigroup flag.
+const coreGroup = "core"
+
+// policyVerbs lists verbs that are enforced by the RBAC authorizer for the given resources,
+// but which are not advertised by the API discovery.
+var policyVerbs = map[string][]string{
+	"roles":        {"bind", "escalate"},
+	"clusterroles": {"bind", "escalate"},
 }
 
 type resourceResolver struct {
 	client discovery.DiscoveryInterface
 	mapper meta.RESTMapper
+
+	// indexMu guards the index of the API resources, which is built once and shared by all lookups.
+	// It is only accessed through indexed.
+	indexMu sync.Mutex
+	index   *resourceIndex
+}
+
+// resourceIndex holds the API resources discovered by a single pass over the API groups.
+type resourceIndex struct {
+	// resources holds the resources of the preferred version of each API group by their names, see indexResources.
+	resources map[string]apismeta.APIResource
+	// ambiguous holds the names which are served by several API groups with the groups that serve them.
+	ambiguous map[string][]string
+	// versions holds the resources served by each version of each API group, in the order of the API groups.
+	versions []groupVersionResources
 }
 
 func NewResourceResolver(client discovery.DiscoveryInterface, mapper meta.RESTMapper) ResourceResolver {
@@ -30,62 +86,247 @@ func NewResourceResolver(client discovery.DiscoveryInterface, mapper meta.RESTMa
 	}
 }
 
-func (rv *resourceResolver) Resolve(verb, resource, subResource string) (string, error) {
+func (rv *resourceResolver) Resolve(verb, resource, subResource string) (ResolvedResource, error) {
 	if resource == rbac.ResourceAll {
-		return resource, nil
+		return ResolvedResource{Resource: resource}, nil
 	}
 	apiResource, err := rv.resourceFor(resource, subResource)
 	if err != nil {
-		name := resource
-		if subResource != "" {
-			name = name + "/" + subResource
-		}
-		return "", fmt.Errorf("the server doesn't have a resource type \"%s\"", name)
+		return ResolvedResource{}, err
 	}
 
 	if !rv.isVerbSupportedBy(verb, apiResource) {
-		return "", fmt.Errorf("the \"%s\" resource does not support the \"%s\" verb, only %v", apiResource.Name, verb, apiResource.Verbs)
+		err := fmt.Errorf("the \"%s\" resource does not support the \"%s\" verb, only %v", apiResource.Name, verb, apiResource.Verbs)
+		supported := append(append([]string{}, apiResource.Verbs...), policyVerbs[apiResource.Name]...)
+		if suggestion := suggestVerb(verb, supported); suggestion != "" {
+			err = fmt.Errorf("%v, did you mean \"%s\"?", err, suggestion)
+		}
+		return ResolvedResource{}, err
+	}
+
+	return ResolvedResource{Resource: apiResource.Name, Group: apiResource.Group, Version: apiResource.Version, Namespaced: apiResource.Namespaced}, nil
+}
+
+func (rv *resourceResolver) SupportedVerbs(resource string) (schema.GroupResource, []string, error) {
+	apiResource, err := rv.resourceFor(resource, "")
+	if err != nil {
+		return schema.GroupResource{}, nil, err
+	}
+	verbs := append([]string{}, apiResource.Verbs...)
+	for _, verb := range policyVerbs[apiResource.Name] {
+		if !rv.isVerbSupportedBy(verb, apismeta.APIResource{Verbs: verbs}) {
+			verbs = append(verbs, verb)
+		}
+	}
+	return schema.GroupResource{Group: apiResource.Group, Resource: apiResource.Name}, verbs, nil
+}
+
+func (rv *resourceResolver) ResourcesInCategory(category string) ([]ResolvedResource, error) {
+	index, err := rv.indexed()
+	if err != nil {
+		return nil, err
 	}
 
-	return apiResource.Name, nil
+	// The index holds each resource under several names, hence the resources are collected by their qualified names.
+	resources := make(map[string]ResolvedResource)
+	for _, res := range index.resources {
+		if strings.Contains(res.Name, "/") {
+			continue
+		}
+		for _, c := range res.Categories {
+			if c == category {
+				resolved := ResolvedResource{Resource: res.Name, Group: res.Group, Version: res.Version, Namespaced: res.Namespaced}
+				resources[resolved.String()] = resolved
+			}
+		}
+	}
+	if len(resources) == 0 {
+		return nil, fmt.Errorf("no resources found in category \"%s\"", category)
+	}
+
+	var result []ResolvedResource
+	for _, res := range resources {
+		result = append(result, res)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].String() < result[j].String()
+	})
+	return result, nil
 }
 
+// resourceFor looks up the APIResource of the given resource and sub-resource.
+// If either cannot be found, a resourceNotFoundError with the names of similar resources is returned.
+// If the API resources cannot be discovered or mapped, the failure is returned instead.
 func (rv *resourceResolver) resourceFor(resourceArg, subResource string) (apismeta.APIResource, error) {
-	index, err := rv.indexResources()
+	notFound := resourceArg
+	if subResource != "" {
+		notFound = notFound + "/" + subResource
+	}
+
+	index, err := rv.indexed()
 	if err != nil {
-		return apismeta.APIResource{}, err
+		return apismeta.APIResource{}, fmt.Errorf("discovering API resources: %v", err)
+	}
+	if len(index.resources) == 0 {
+		glog.V(3).Infof("No API resources discovered, falling back to the REST mapper")
+		apiResource, err := rv.resourceFromMapper(resourceArg, subResource)
+		if meta.IsNoMatchError(err) {
+			return apismeta.APIResource{}, &resourceNotFoundError{resource: notFound}
+		}
+		if err != nil {
+			return apismeta.APIResource{}, fmt.Errorf("mapping resource: %v", err)
+		}
+		return apiResource, nil
 	}
 
-	apiResource, err := rv.lookupResource(index, resourceArg)
+	apiResource, found, err := rv.lookupResource(index, resourceArg)
 	if err != nil {
 		return apismeta.APIResource{}, err
 	}
+	if !found {
+		return apismeta.APIResource{}, &resourceNotFoundError{resource: notFound, suggestions: suggestResources(index.resources, resourceArg, ""),
+			servedAs: index.servedAs(resourceArg)}
+	}
 
 	if subResource != "" {
-		apiResource, err = rv.lookupSubResource(index, apiResource.Name+"/"+subResource)
+		apiResource, err = rv.lookupSubResource(index.resources, qualifiedName(apiResource.Name+"/"+subResource, apiResource.Group))
 		if err != nil {
-			return apismeta.APIResource{}, err
+			return apismeta.APIResource{}, &resourceNotFoundError{resource: notFound, suggestions: suggestResources(index.resources, notFound, resourceArg+"/")}
 		}
 		return apiResource, nil
 	}
 	return apiResource, nil
 }
 
-func (rv *resourceResolver) lookupResource(index map[string]apismeta.APIResource, resourceArg string) (apismeta.APIResource, error) {
-	resource, ok := index[resourceArg]
+// resourceFromMapper resolves the given resource and sub-resource with the REST mapper, in case the API discovery
+// serves no resources, such as when the API server only publishes the OpenAPI v3 documents of some API groups
+// or the discovery endpoints of all API group versions fail. The REST mapper does not know the verbs supported by
+// the resource, hence they are reported as the wildcard verb, and sub-resources are taken as given.
+func (rv *resourceResolver) resourceFromMapper(resourceArg, subResource string) (apismeta.APIResource, error) {
+	gvr, gr := schema.ParseResourceArg(resourceArg)
+	if gvr == nil {
+		partial := gr.WithVersion("")
+		gvr = &partial
+	}
+	resource, err := rv.mapper.ResourceFor(*gvr)
+	if err != nil {
+		return apismeta.APIResource{}, err
+	}
+	kind, err := rv.mapper.KindFor(resource)
+	if err != nil {
+		return apismeta.APIResource{}, err
+	}
+	mapping, err := rv.mapper.RESTMapping(kind.GroupKind(), kind.Version)
+	if err != nil {
+		return apismeta.APIResource{}, err
+	}
+
+	name := resource.Resource
+	if subResource != "" {
+		name = name + "/" + subResource
+	}
+	return apismeta.APIResource{
+		Name:       name,
+		Group:      resource.Group,
+		Version:    resource.Version,
+		Namespaced: mapping.Scope.Name() == meta.RESTScopeNameNamespace,
+		Verbs:      []string{rbac.VerbAll},
+	}, nil
+}
+
+// lookupResource looks up the APIResource of the given resource in the index and returns whether it is found.
+// An error is returned if the resource is ambiguous or cannot be mapped.
+func (rv *resourceResolver) lookupResource(index *resourceIndex, resourceArg string) (apismeta.APIResource, bool, error) {
+	if groups, ok := index.ambiguous[resourceArg]; ok {
+		return apismeta.APIResource{}, false, newAmbiguousResourceError(index.resources, resourceArg, groups)
+	}
+	resource, ok := index.resources[resourceArg]
 	if ok {
-		return resource, nil
+		return resource, true, nil
+	}
+
+	// RBAC rules do not distinguish API versions, hence fully-qualified resources are matched by their group,
+	// as long as the given version serves them. A resource qualified with an API group of three or more segments,
+	// such as `issuers.cert-manager.io`, is not taken for a version.
+	if gvr, _ := schema.ParseResourceArg(resourceArg); gvr != nil && apiVersionPattern.MatchString(gvr.Version) {
+		resource, ok = index.resources[qualifiedName(gvr.Resource, gvr.Group)]
+		if ok && resource.Version == gvr.Version {
+			return resource, true, nil
+		}
+		name := gvr.Resource
+		if ok {
+			name = resource.Name
+		}
+		resource, ok = index.resourceInVersion(name, gvr.GroupVersion())
+		return resource, ok, nil
 	}
 
 	gvr, err := rv.mapper.ResourceFor(schema.GroupVersionResource{Resource: resourceArg})
+	if meta.IsNoMatchError(err) {
+		return apismeta.APIResource{}, false, nil
+	}
 	if err != nil {
-		return apismeta.APIResource{}, err
+		return apismeta.APIResource{}, false, fmt.Errorf("mapping resource: %v", err)
 	}
-	resource, ok = index[gvr.Resource]
-	if ok {
-		return resource, nil
+	if groups, ok := index.ambiguous[gvr.Resource]; ok {
+		return apismeta.APIResource{}, false, newAmbiguousResourceError(index.resources, gvr.Resource, groups)
 	}
-	return apismeta.APIResource{}, fmt.Errorf("not found \"%s\"", resourceArg)
+	resource, ok = index.resources[gvr.Resource]
+	return resource, ok, nil
+}
+
+// resourceInVersion returns the resource with the given name as served by the given API group version.
+// Only the preferred version of each API group is indexed by name, hence the resources of other versions are looked up
+// in the versions discovered with it, including those which are not served by the preferred version at all.
+func (index *resourceIndex) resourceInVersion(name string, gv schema.GroupVersion) (apismeta.APIResource, bool) {
+	for _, gvr := range index.versions {
+		if gvr.groupVersion != gv.String() {
+			continue
+		}
+		for _, res := range gvr.resources {
+			if hasName(res, name) {
+				res.Group, res.Version = gv.Group, gv.Version
+				return res, true
+			}
+		}
+	}
+	return apismeta.APIResource{}, false
+}
+
+// servedAs returns the fully-qualified names of the resources with the same name as the given resource in any version
+// of any API group, sorted by name. This tells why a resource is not found, such as a custom resource served only by
+// a version other than the preferred version of its API group, or a resource given with another API group than the
+// one which serves it.
+func (index *resourceIndex) servedAs(resourceArg string) []string {
+	name := strings.SplitN(resourceArg, ".", 2)[0]
+
+	var served []string
+	for _, gvr := range index.versions {
+		gv, err := schema.ParseGroupVersion(gvr.groupVersion)
+		if err != nil {
+			continue
+		}
+		for _, res := range gvr.resources {
+			if hasName(res, name) {
+				served = append(served, res.Name+"."+gv.Version+"."+gv.Group)
+			}
+		}
+	}
+	sort.Strings(served)
+	return served
+}
+
+// hasName returns `true` if the given name is the plural name, the singular name or a short name of the given resource.
+func hasName(res apismeta.APIResource, name string) bool {
+	if res.Name == name {
+		return true
+	}
+	for _, alias := range aliasesOf(res) {
+		if alias == name {
+			return true
+		}
+	}
+	return false
 }
 
 func (rv *resourceResolver) lookupSubResource(index map[string]apismeta.APIResource, subResource string) (apismeta.APIResource, error) {
@@ -96,46 +337,255 @@ func (rv *resourceResolver) lookupSubResource(index map[string]apismeta.APIResou
 	return apiResource, nil
 }
 
-// indexResources builds a lookup index for APIResources where the keys are resources names (both plural and short names).
-func (rv *resourceResolver) indexResources() (map[string]apismeta.APIResource, error) {
+// indexed returns the index of the API resources, which is built by a single discovery pass per resolver.
+// Concurrent callers wait for the pass in flight rather than starting their own, and later callers reuse its index,
+// so that resolving the resource, listing its verbs and the resources of a category discover the API resources once.
+// A failed pass is not cached, hence the next caller starts a new one.
+func (rv *resourceResolver) indexed() (*resourceIndex, error) {
+	rv.indexMu.Lock()
+	defer rv.indexMu.Unlock()
+	if rv.index != nil {
+		return rv.index, nil
+	}
+	index, err := rv.indexResources()
+	if err != nil {
+		return nil, err
+	}
+	rv.index = index
+	return index, nil
+}
+
+// indexResources builds a lookup index for APIResources where the keys are resources names (plural, singular and short names).
+// Each APIResource is also indexed by its names qualified with the API group, e.g. `deployments.apps` or `deploy.apps`,
+// which resolve uniquely even if the unqualified name is served by several API groups.
+// If resources of several API groups have the same name, the resource of the core API group is indexed by the name.
+// The names which are ambiguous between built-in and other API groups are returned with the groups that serve them.
+// Only the preferred version of each API group is indexed by name, unless it serves no resources, while the resources
+// of all versions are kept to tell in which versions a resource is served. API group versions whose resources cannot
+// be fetched are skipped.
+func (rv *resourceResolver) indexResources() (*resourceIndex, error) {
 	serverResources := make(map[string]apismeta.APIResource)
+	groupsByName := make(map[string][]string)
+
+	// index adds the resource by an unqualified name, preferring resources of the core API group.
+	index := func(name string, res apismeta.APIResource) {
+		if existing, ok := serverResources[name]; !ok || res.Group == "" {
+			serverResources[name] = res
+		} else if existing.Group == res.Group {
+			return
+		}
+		for _, group := range groupsByName[name] {
+			if group == res.Group {
+				return
+			}
+		}
+		groupsByName[name] = append(groupsByName[name], res.Group)
+	}
 
 	serverGroups, err := rv.client.ServerGroups()
 	if err != nil {
 		return nil, fmt.Errorf("getting API groups: %v", err)
 	}
-	for _, sg := range serverGroups.Groups {
-		for _, version := range sg.Versions {
-			// Consider only preferred versions
-			if version.GroupVersion != sg.PreferredVersion.GroupVersion {
-				continue
+
+	// The resources of the API groups are fetched concurrently, but indexed in the order of the API groups,
+	// so that the index does not depend on the order in which the responses arrive.
+	served := make([][]groupVersionResources, len(serverGroups.Groups))
+	sem := make(chan struct{}, maxConcurrentDiscoveryRequests)
+	var wg sync.WaitGroup
+	for i, sg := range serverGroups.Groups {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, sg apismeta.APIGroup) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			served[i] = rv.serverResourcesFor(sg)
+		}(i, sg)
+	}
+	wg.Wait()
+
+	var versions []groupVersionResources
+	for _, groupVersions := range served {
+		versions = append(versions, groupVersions...)
+		gvr := preferredResources(groupVersions)
+		if gvr.groupVersion == "" {
+			continue
+		}
+		gv, err := schema.ParseGroupVersion(gvr.groupVersion)
+		if err != nil {
+			return nil, fmt.Errorf("parsing API group version: %v", err)
+		}
+
+		for _, res := range gvr.resources {
+			if res.Group == "" {
+				res.Group, res.Version = gv.Group, gv.Version
 			}
-			rsList, err := rv.client.ServerResourcesForGroupVersion(version.GroupVersion)
-			if err != nil {
-				return nil, fmt.Errorf("getting resources for API group: %v", err)
+			index(res.Name, res)
+			if res.Group != "" {
+				serverResources[qualifiedName(res.Name, res.Group)] = res
 			}
-
-			for _, res := range rsList.APIResources {
-				serverResources[res.Name] = res
-				if len(res.ShortNames) > 0 {
-					for _, sn := range res.ShortNames {
-						serverResources[sn] = res
-					}
+			for _, sn := range aliasesOf(res) {
+				index(sn, res)
+				if res.Group != "" {
+					serverResources[qualifiedName(sn, res.Group)] = res
 				}
 			}
 		}
 	}
-	return serverResources, nil
+
+	ambiguous := make(map[string][]string)
+	for name, groups := range groupsByName {
+		if isAmbiguous(groups) {
+			sort.Strings(groups)
+			ambiguous[name] = groups
+		}
+	}
+	return &resourceIndex{resources: serverResources, ambiguous: ambiguous, versions: versions}, nil
+}
+
+// aliasesOf returns the short names and the singular name of the given resource.
+// The singular name is indexed, so that a short name of a custom resource, which clashes with the singular name
+// of a built-in resource, such as `pod`, is detected as ambiguous rather than silently resolved to the custom resource.
+func aliasesOf(res apismeta.APIResource) []string {
+	aliases := append([]string{}, res.ShortNames...)
+	if res.SingularName != "" && res.SingularName != res.Name {
+		aliases = append(aliases, res.SingularName)
+	}
+	return aliases
+}
+
+// groupVersionResources holds the resources served by an API group version.
+type groupVersionResources struct {
+	groupVersion string
+	resources    []apismeta.APIResource
+}
+
+// serverResourcesFor returns the resources served by the versions of the given API group, starting with the preferred
+// version. A broken aggregated API may fail to serve some versions, which are skipped rather than failing the resolution.
+func (rv *resourceResolver) serverResourcesFor(group apismeta.APIGroup) []groupVersionResources {
+	var served []groupVersionResources
+	for _, groupVersion := range preferredVersionFirst(group) {
+		rsList, err := rv.client.ServerResourcesForGroupVersion(groupVersion)
+		if err != nil {
+			glog.Warningf("Skipping API group version %s: %v", groupVersion, err)
+			continue
+		}
+		served = append(served, groupVersionResources{groupVersion: groupVersion, resources: rsList.APIResources})
+	}
+	return served
+}
+
+// preferredResources returns the resources of the preferred version of an API group, given the resources of its
+// versions as returned by serverResourcesFor. A broken aggregated API may serve no resources in its preferred version,
+// in which case the resources are taken from the next version that serves any. If no version serves any resources,
+// the returned groupVersion is empty, so that the API group is skipped.
+func preferredResources(groupVersions []groupVersionResources) groupVersionResources {
+	for _, gvr := range groupVersions {
+		if len(gvr.resources) > 0 {
+			return gvr
+		}
+		glog.V(3).Infof("No resources served by API group version %s", gvr.groupVersion)
+	}
+	return groupVersionResources{}
+}
+
+// preferredVersionFirst returns the versions of the given API group, starting with the preferred version
+// followed by the other versions in the order of their priority.
+func preferredVersionFirst(group apismeta.APIGroup) []string {
+	var versions []string
+	if group.PreferredVersion.GroupVersion != "" {
+		versions = append(versions, group.PreferredVersion.GroupVersion)
+	}
+	for _, version := range group.Versions {
+		if version.GroupVersion != group.PreferredVersion.GroupVersion {
+			versions = append(versions, version.GroupVersion)
+		}
+	}
+	return versions
+}
+
+// isAmbiguous returns `true` if a resource name is served by several API groups, of which at least one is not
+// a built-in API group. Built-in API groups serving the same resource, such as `events` of the core and the
+// `events.k8s.io` API groups, are not considered ambiguous, because they serve the same kind of objects.
+func isAmbiguous(groups []string) bool {
+	if len(groups) < 2 {
+		return false
+	}
+	for _, group := range groups {
+		if !isBuiltInGroup(group) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBuiltInGroup returns `true` for the API groups of Kubernetes, whose names are either unqualified, such as `apps`,
+// or end with `.k8s.io`, whereas the API groups of custom resources must be qualified with a domain.
+func isBuiltInGroup(group string) bool {
+	return !strings.Contains(group, ".") || strings.HasSuffix(group, ".k8s.io")
+}
+
+// qualifiedName returns the given resource name qualified with the given API group.
+// Resources of the core API group are not qualified.
+func qualifiedName(resource, group string) string {
+	if group == "" {
+		return resource
+	}
+	return resource + "." + group
+}
+
+// ambiguousResourceError is returned when a resource name is served by several API groups.
+type ambiguousResourceError struct {
+	resource string
+	groups   []string
+	// builtIn is the name of the built-in resource if the ambiguous name is one of its aliases, such as `pods` for `po`.
+	builtIn string
+}
+
+// newAmbiguousResourceError returns an ambiguousResourceError for the given resource name served by the given API groups.
+// If the name is an alias of a built-in resource, the error refers to the built-in resource by its plural name,
+// so that the user can tell apart the built-in resource from a custom resource with a clashing short name.
+func newAmbiguousResourceError(index map[string]apismeta.APIResource, resource string, groups []string) error {
+	err := &ambiguousResourceError{resource: resource, groups: groups}
+	if res, ok := index[resource]; ok && isBuiltInGroup(res.Group) && res.Name != resource {
+		err.builtIn = qualifiedName(res.Name, res.Group)
+	}
+	return err
+}
+
+func (e *ambiguousResourceError) Error() string {
+	var groups, qualified []string
+	for _, group := range e.groups {
+		if group == "" {
+			groups = append(groups, coreGroup)
+			continue
+		}
+		groups = append(groups, group)
+		qualified = append(qualified, qualifiedName(e.resource, group))
+	}
+	if e.builtIn != "" {
+		return fmt.Sprintf("the resource type \"%s\" is served by the API groups %v, use %s for the built-in resource, "+
+			"or --apigroup or a group-qualified name such as %s", e.resource, groups, e.builtIn, strings.Join(qualified, " or "))
+	}
+	return fmt.Sprintf("the resource type \"%s\" is served by the API groups %v, use --apigroup or a group-qualified name such as %s",
+		e.resource, groups, strings.Join(qualified, " or "))
 }
 
 // isVerbSupportedBy returns `true` if the given verb is supported by the given resource, `false` otherwise.
-// Returns `true` if the given verb equals VerbAll.
+// Returns `true` if the given verb equals VerbAll or is one of the policyVerbs of the given resource,
+// or if the verbs of the resource are unknown, which is reported as VerbAll.
 func (rv *resourceResolver) isVerbSupportedBy(verb string, resource apismeta.APIResource) bool {
 	if verb == rbac.VerbAll {
 		return true
 	}
 	supported := false
 	for _, v := range resource.Verbs {
+		if v == verb || v == rbac.VerbAll {
+			supported = true
+		}
+	}
+	for _, v := range policyVerbs[resource.Name] {
 		if v == verb {
 			supported = true
 		}
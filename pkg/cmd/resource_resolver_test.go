@@ -2,13 +2,19 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	apismeta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes/fake"
+	"sync"
 	"testing"
+	"time"
 )
 
 type mapperMock struct {
@@ -34,6 +40,25 @@ func TestResourceResolver_Resolve(t *testing.T) {
 				{Version: "v1", Name: "services", ShortNames: []string{"svc"}, Verbs: []string{"list", "delete"}},
 			},
 		},
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []apismeta.APIResource{
+				{Version: "v1", Name: "deployments", Namespaced: true, ShortNames: []string{"deploy"}, Verbs: []string{"list", "get"}},
+				{Version: "v1", Name: "deployments/scale", Namespaced: true, Verbs: []string{"get", "update"}},
+			},
+		},
+		{
+			GroupVersion: "rbac.authorization.k8s.io/v1",
+			APIResources: []apismeta.APIResource{
+				{Version: "v1", Name: "clusterroles", Verbs: []string{"list", "create", "delete"}},
+			},
+		},
+		{
+			GroupVersion: "certificates.k8s.io/v1beta1",
+			APIResources: []apismeta.APIResource{
+				{Version: "v1beta1", Name: "certificatesigningrequests", ShortNames: []string{"csr"}, Verbs: []string{"get", "approve"}},
+			},
+		},
 	}
 
 	type given struct {
@@ -48,8 +73,11 @@ func TestResourceResolver_Resolve(t *testing.T) {
 	}
 
 	type expected struct {
-		resource string
-		err      error
+		resource   string
+		group      string
+		version    string
+		namespaced bool
+		err        error
 	}
 
 	data := []struct {
@@ -61,12 +89,12 @@ func TestResourceResolver_Resolve(t *testing.T) {
 		{
 			scenario: "A",
 			given:    given{verb: "list", resource: "pods"},
-			expected: expected{resource: "pods"},
+			expected: expected{resource: "pods", version: "v1"},
 		},
 		{
 			scenario: "B",
 			given:    given{verb: "list", resource: "po"},
-			expected: expected{resource: "pods"},
+			expected: expected{resource: "pods", version: "v1"},
 		},
 		{
 			scenario: "C",
@@ -76,12 +104,12 @@ func TestResourceResolver_Resolve(t *testing.T) {
 		{
 			scenario: "D",
 			given:    given{verb: "list", resource: "services"},
-			expected: expected{resource: "services"},
+			expected: expected{resource: "services", version: "v1"},
 		},
 		{
 			scenario: "E",
 			given:    given{verb: "list", resource: "svc"},
-			expected: expected{resource: "services"},
+			expected: expected{resource: "services", version: "v1"},
 		},
 		{
 			scenario: "F",
@@ -91,41 +119,107 @@ func TestResourceResolver_Resolve(t *testing.T) {
 		{
 			scenario: "G",
 			given:    given{verb: "get", resource: "pods", subResource: "log"},
-			expected: expected{resource: "pods/log"},
+			expected: expected{resource: "pods/log", version: "v1"},
 		},
 		{
 			scenario: "H",
 			given:    given{verb: "get", resource: "pods", subResource: "logz"},
-			expected: expected{err: errors.New("the server doesn't have a resource type \"pods/logz\"")},
+			expected: expected{err: &resourceNotFoundError{resource: "pods/logz", suggestions: []string{"pods/log"}}},
 		},
 		{
 			scenario:      "I",
 			given:         given{verb: "list", resource: "pod"},
 			mappingResult: &mappingResult{out: "pods"},
-			expected:      expected{resource: "pods"},
+			expected:      expected{resource: "pods", version: "v1"},
 		},
 		{
 			scenario:      "J",
 			given:         given{verb: "get", resource: "pod", subResource: "log"},
 			mappingResult: &mappingResult{out: "pods"},
-			expected:      expected{resource: "pods/log"},
+			expected:      expected{resource: "pods/log", version: "v1"},
 		},
 		{
 			scenario:      "K",
 			given:         given{verb: "list", resource: "pod"},
-			mappingResult: &mappingResult{err: errors.New("mapping failed")},
-			expected:      expected{err: errors.New("the server doesn't have a resource type \"pod\"")},
+			mappingResult: &mappingResult{err: &meta.NoResourceMatchError{PartialResource: schema.GroupVersionResource{Resource: "pod"}}},
+			expected:      expected{err: &resourceNotFoundError{resource: "pod", suggestions: []string{"po", "pods"}}},
+		},
+		{
+			scenario:      "Z",
+			given:         given{verb: "list", resource: "pod"},
+			mappingResult: &mappingResult{err: errors.New("connection refused")},
+			expected:      expected{err: errors.New("mapping resource: connection refused")},
 		},
 		{
 			scenario: "L",
 			given:    given{verb: "*", resource: "pods"},
-			expected: expected{resource: "pods"},
+			expected: expected{resource: "pods", version: "v1"},
 		},
 		{
 			scenario: "M",
 			given:    given{verb: "list", resource: "*"},
 			expected: expected{resource: "*"},
 		},
+		{
+			scenario: "N",
+			given:    given{verb: "bind", resource: "clusterroles"},
+			expected: expected{resource: "clusterroles", group: "rbac.authorization.k8s.io", version: "v1"},
+		},
+		{
+			scenario: "O",
+			given:    given{verb: "escalate", resource: "clusterroles"},
+			expected: expected{resource: "clusterroles", group: "rbac.authorization.k8s.io", version: "v1"},
+		},
+		{
+			scenario: "P",
+			given:    given{verb: "bind", resource: "pods"},
+			expected: expected{err: errors.New("the \"pods\" resource does not support the \"bind\" verb, only [list create delete]")},
+		},
+		{
+			scenario: "Q",
+			given:    given{verb: "list", resource: "deployments.v1.apps"},
+			expected: expected{resource: "deployments", group: "apps", version: "v1", namespaced: true},
+		},
+		{
+			scenario: "R",
+			given:    given{verb: "list", resource: "pods.v1."},
+			expected: expected{resource: "pods", version: "v1"},
+		},
+		{
+			scenario: "S",
+			given:    given{verb: "update", resource: "deployments.v1.apps", subResource: "scale"},
+			expected: expected{resource: "deployments/scale", group: "apps", version: "v1", namespaced: true},
+		},
+		{
+			scenario: "U",
+			given:    given{verb: "approve", resource: "csr"},
+			expected: expected{resource: "certificatesigningrequests", group: "certificates.k8s.io", version: "v1beta1"},
+		},
+		{
+			scenario: "V",
+			given:    given{verb: "aprove", resource: "csr"},
+			expected: expected{err: errors.New("the \"certificatesigningrequests\" resource does not support the \"aprove\" verb, only [get approve], did you mean \"approve\"?")},
+		},
+		{
+			scenario: "W",
+			given:    given{verb: "escalte", resource: "clusterroles"},
+			expected: expected{err: errors.New("the \"clusterroles\" resource does not support the \"escalte\" verb, only [list create delete], did you mean \"escalate\"?")},
+		},
+		{
+			scenario: "X",
+			given:    given{verb: "list", resource: "deploy.apps"},
+			expected: expected{resource: "deployments", group: "apps", version: "v1", namespaced: true},
+		},
+		{
+			scenario: "Y",
+			given:    given{verb: "update", resource: "deploy.apps", subResource: "scale"},
+			expected: expected{resource: "deployments/scale", group: "apps", version: "v1", namespaced: true},
+		},
+		{
+			scenario: "T",
+			given:    given{verb: "list", resource: "deployments.v1.extensions"},
+			expected: expected{err: &resourceNotFoundError{resource: "deployments.v1.extensions", servedAs: []string{"deployments.v1.apps"}}},
+		},
 	}
 
 	for _, tt := range data {
@@ -138,12 +232,673 @@ func TestResourceResolver_Resolve(t *testing.T) {
 
 			resolver := NewResourceResolver(client.Discovery(), mapper)
 
-			resource, err := resolver.Resolve(tt.given.verb, tt.given.resource, tt.given.subResource)
+			resolved, err := resolver.Resolve(tt.given.verb, tt.given.resource, tt.given.subResource)
 
 			assert.Equal(t, tt.expected.err, err)
-			assert.Equal(t, tt.expected.resource, resource)
+			assert.Equal(t, ResolvedResource{Resource: tt.expected.resource, Group: tt.expected.group, Version: tt.expected.version, Namespaced: tt.expected.namespaced}, resolved)
 
 			mapper.AssertExpectations(t)
 		})
 	}
 }
+
+func TestResourceResolver_Resolve_FullyQualifiedVersion(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.Resources = []*apismeta.APIResourceList{
+		{
+			GroupVersion: "autoscaling/v1",
+			APIResources: []apismeta.APIResource{
+				{Name: "horizontalpodautoscalers", Namespaced: true, ShortNames: []string{"hpa"}, Verbs: []string{"list", "get"}},
+			},
+		},
+		{
+			GroupVersion: "autoscaling/v2beta1",
+			APIResources: []apismeta.APIResource{
+				{Name: "horizontalpodautoscalers", Namespaced: true, ShortNames: []string{"hpa"}, Verbs: []string{"list", "get"}},
+			},
+		},
+	}
+
+	data := []struct {
+		scenario string
+		resource string
+		resolved ResolvedResource
+		err      error
+	}{
+		{
+			scenario: "Should resolve preferred version",
+			resource: "horizontalpodautoscalers.v1.autoscaling",
+			resolved: ResolvedResource{Resource: "horizontalpodautoscalers", Group: "autoscaling", Version: "v1", Namespaced: true},
+		},
+		{
+			scenario: "Should resolve other served version",
+			resource: "hpa.v2beta1.autoscaling",
+			resolved: ResolvedResource{Resource: "horizontalpodautoscalers", Group: "autoscaling", Version: "v2beta1", Namespaced: true},
+		},
+		{
+			scenario: "Should return error when version is not served",
+			resource: "horizontalpodautoscalers.v3.autoscaling",
+			err: &resourceNotFoundError{resource: "horizontalpodautoscalers.v3.autoscaling",
+				suggestions: []string{"horizontalpodautoscalers.autoscaling"},
+				servedAs:    []string{"horizontalpodautoscalers.v1.autoscaling", "horizontalpodautoscalers.v2beta1.autoscaling"}},
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			resolver := NewResourceResolver(client.Discovery(), new(mapperMock))
+
+			// when
+			resolved, err := resolver.Resolve("list", tt.resource, "")
+
+			// then
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.resolved, resolved)
+		})
+	}
+}
+
+func TestResourceResolver_Resolve_NonPreferredVersionOnly(t *testing.T) {
+	// given
+	client := fake.NewSimpleClientset()
+	client.Resources = []*apismeta.APIResourceList{
+		{
+			// The fake discovery takes the first version of an API group as the preferred version.
+			GroupVersion: "example.com/v1",
+			APIResources: []apismeta.APIResource{
+				{Name: "databases", Namespaced: true, Verbs: []string{"list", "get"}},
+			},
+		},
+		{
+			GroupVersion: "example.com/v1alpha1",
+			APIResources: []apismeta.APIResource{
+				{Name: "widgets", SingularName: "widget", Namespaced: true, Verbs: []string{"list", "get"}},
+			},
+		},
+	}
+	// The group-qualified name is not found in the index, hence it is mapped before the versions are looked up.
+	mapper := new(mapperMock)
+	mapper.On("ResourceFor", schema.GroupVersionResource{Resource: "widgets.example.com"}).
+		Return(schema.GroupVersionResource{}, &meta.NoResourceMatchError{PartialResource: schema.GroupVersionResource{Resource: "widgets.example.com"}})
+	discoveryClient := &slowDiscovery{DiscoveryInterface: client.Discovery(), resourceRequests: make(map[string]int)}
+	resolver := NewResourceResolver(discoveryClient, mapper)
+
+	// when
+	_, err := resolver.Resolve("list", "widgets.example.com", "")
+
+	// then
+	assert.Equal(t, &resourceNotFoundError{resource: "widgets.example.com", servedAs: []string{"widgets.v1alpha1.example.com"}}, err)
+	assert.EqualError(t, err, "the server doesn't have a resource type \"widgets.example.com\", "+
+		"but a resource of the same name is served as widgets.v1alpha1.example.com")
+
+	// when
+	resolved, err := resolver.Resolve("list", "widget.v1alpha1.example.com", "")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, ResolvedResource{Resource: "widgets", Group: "example.com", Version: "v1alpha1", Namespaced: true}, resolved)
+
+	// and the served versions are taken from the single discovery pass of the index
+	assert.Equal(t, 1, discoveryClient.groupsPasses)
+	assert.Equal(t, map[string]int{"example.com/v1": 1, "example.com/v1alpha1": 1}, discoveryClient.resourceRequests)
+}
+
+// groupsFailingDiscovery fails to list the API groups.
+type groupsFailingDiscovery struct {
+	discovery.DiscoveryInterface
+}
+
+func (d *groupsFailingDiscovery) ServerGroups() (*apismeta.APIGroupList, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestResourceResolver_Resolve_DiscoveryFailure(t *testing.T) {
+	// given
+	resolver := NewResourceResolver(&groupsFailingDiscovery{DiscoveryInterface: fake.NewSimpleClientset().Discovery()}, new(mapperMock))
+
+	// when
+	_, err := resolver.Resolve("list", "pods", "")
+
+	// then
+	assert.EqualError(t, err, "discovering API resources: getting API groups: connection refused")
+}
+
+// flakyDiscovery fails to list the API groups the given number of times before it succeeds.
+type flakyDiscovery struct {
+	discovery.DiscoveryInterface
+	failures int
+}
+
+func (d *flakyDiscovery) ServerGroups() (*apismeta.APIGroupList, error) {
+	if d.failures > 0 {
+		d.failures--
+		return nil, errors.New("connection reset")
+	}
+	return d.DiscoveryInterface.ServerGroups()
+}
+
+func TestResourceResolver_Resolve_RetriesFailedDiscovery(t *testing.T) {
+	// given
+	client := fake.NewSimpleClientset()
+	client.Resources = []*apismeta.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []apismeta.APIResource{{Name: "pods", Namespaced: true, Verbs: []string{"list", "get"}}},
+		},
+	}
+	resolver := NewResourceResolver(&flakyDiscovery{DiscoveryInterface: client.Discovery(), failures: 1}, new(mapperMock))
+
+	// when
+	_, err := resolver.Resolve("list", "pods", "")
+
+	// then
+	assert.EqualError(t, err, "discovering API resources: getting API groups: connection reset")
+
+	// when
+	resolved, err := resolver.Resolve("list", "pods", "")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, ResolvedResource{Resource: "pods", Version: "v1", Namespaced: true}, resolved)
+}
+
+func TestResourceResolver_Resolve_DottedGroup(t *testing.T) {
+	// given
+	client := fake.NewSimpleClientset()
+	client.Resources = []*apismeta.APIResourceList{
+		{
+			GroupVersion: "cert-manager.io/v1",
+			APIResources: []apismeta.APIResource{
+				{Name: "issuers", Namespaced: true, Verbs: []string{"list", "get"}},
+			},
+		},
+	}
+	mapper := new(mapperMock)
+	mapper.On("ResourceFor", schema.GroupVersionResource{Resource: "foos.cert-manager.io"}).
+		Return(schema.GroupVersionResource{}, &meta.NoResourceMatchError{PartialResource: schema.GroupVersionResource{Resource: "foos.cert-manager.io"}})
+	discoveryClient := &slowDiscovery{DiscoveryInterface: client.Discovery(), resourceRequests: make(map[string]int)}
+	resolver := NewResourceResolver(discoveryClient, mapper)
+
+	// when
+	resolved, err := resolver.Resolve("list", "issuers.cert-manager.io", "")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, ResolvedResource{Resource: "issuers", Group: "cert-manager.io", Version: "v1", Namespaced: true}, resolved)
+
+	// when
+	_, err = resolver.Resolve("list", "foos.cert-manager.io", "")
+
+	// then
+	assert.Equal(t, &resourceNotFoundError{resource: "foos.cert-manager.io", suggestions: []string{"issuers.cert-manager.io"}}, err)
+	assert.Equal(t, map[string]int{"cert-manager.io/v1": 1}, discoveryClient.resourceRequests)
+	mapper.AssertExpectations(t)
+}
+
+func TestResourceResolver_Resolve_EmptyPreferredVersion(t *testing.T) {
+	// given
+	client := fake.NewSimpleClientset()
+	client.Resources = []*apismeta.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []apismeta.APIResource{
+				{Version: "v1", Name: "pods", Namespaced: true, Verbs: []string{"list", "get"}},
+			},
+		},
+		{
+			// The fake discovery takes the first version of an API group as the preferred version.
+			GroupVersion: "example.com/v2",
+		},
+		{
+			GroupVersion: "example.com/v1",
+			APIResources: []apismeta.APIResource{
+				{Name: "databases", ShortNames: []string{"db"}, Namespaced: true, Verbs: []string{"list", "get"}},
+			},
+		},
+	}
+	resolver := NewResourceResolver(client.Discovery(), new(mapperMock))
+
+	// when
+	resolved, err := resolver.Resolve("list", "db", "")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, ResolvedResource{Resource: "databases", Group: "example.com", Version: "v1", Namespaced: true}, resolved)
+}
+
+func TestResourceResolver_Resolve_AmbiguousResource(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	client.Resources = []*apismeta.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []apismeta.APIResource{
+				{Version: "v1", Name: "services", ShortNames: []string{"svc"}, Verbs: []string{"list", "get"}},
+				{Version: "v1", Name: "events", ShortNames: []string{"ev"}, Verbs: []string{"list", "get"}},
+			},
+		},
+		{
+			GroupVersion: "events.k8s.io/v1beta1",
+			APIResources: []apismeta.APIResource{
+				{Name: "events", ShortNames: []string{"ev"}, Verbs: []string{"list", "get"}},
+			},
+		},
+		{
+			GroupVersion: "mycompany.com/v1",
+			APIResources: []apismeta.APIResource{
+				{Name: "services", Verbs: []string{"list", "get"}},
+			},
+		},
+	}
+
+	data := []struct {
+		scenario string
+		resource string
+		result   ResolvedResource
+		err      error
+	}{
+		{
+			scenario: "Should return error when resource name is served by core and custom API groups",
+			resource: "services",
+			err:      &ambiguousResourceError{resource: "services", groups: []string{"", "mycompany.com"}},
+		},
+		{
+			scenario: "Should resolve resource qualified with custom API group",
+			resource: "services.mycompany.com",
+			result:   ResolvedResource{Resource: "services", Group: "mycompany.com", Version: "v1"},
+		},
+		{
+			scenario: "Should resolve resource qualified with core API group",
+			resource: "services.v1.",
+			result:   ResolvedResource{Resource: "services", Version: "v1"},
+		},
+		{
+			scenario: "Should resolve short name served only by core API group",
+			resource: "svc",
+			result:   ResolvedResource{Resource: "services", Version: "v1"},
+		},
+		{
+			scenario: "Should resolve resource name served by several built-in API groups to core API group",
+			resource: "events",
+			result:   ResolvedResource{Resource: "events", Version: "v1"},
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			resolver := NewResourceResolver(client.Discovery(), new(mapperMock))
+
+			// when
+			result, err := resolver.Resolve("list", tt.resource, "")
+
+			// then
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.result, result)
+		})
+	}
+}
+
+func TestResourceResolver_Resolve_CustomResourceShortNameClash(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	client.Resources = []*apismeta.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []apismeta.APIResource{
+				{Version: "v1", Name: "pods", SingularName: "pod", ShortNames: []string{"po"}, Namespaced: true, Verbs: []string{"list", "get"}},
+			},
+		},
+		{
+			GroupVersion: "example.com/v1",
+			APIResources: []apismeta.APIResource{
+				{Name: "potatoes", SingularName: "potato", ShortNames: []string{"po"}, Namespaced: true, Verbs: []string{"list", "get"}},
+				{Name: "pods", SingularName: "pod", Namespaced: true, Verbs: []string{"list", "get"}},
+			},
+		},
+		{
+			GroupVersion: "mycompany.com/v1",
+			APIResources: []apismeta.APIResource{
+				{Name: "podcasts", SingularName: "podcast", ShortNames: []string{"pod"}, Verbs: []string{"list", "get"}},
+			},
+		},
+	}
+
+	data := []struct {
+		scenario string
+		resource string
+		result   ResolvedResource
+		err      error
+	}{
+		{
+			scenario: "Should return error when custom resource short name clashes with built-in short name",
+			resource: "po",
+			err:      &ambiguousResourceError{resource: "po", groups: []string{"", "example.com"}, builtIn: "pods"},
+		},
+		{
+			scenario: "Should return error when custom resource short name clashes with built-in singular name",
+			resource: "pod",
+			err:      &ambiguousResourceError{resource: "pod", groups: []string{"", "example.com", "mycompany.com"}, builtIn: "pods"},
+		},
+		{
+			scenario: "Should resolve custom resource short name qualified with its API group",
+			resource: "po.example.com",
+			result:   ResolvedResource{Resource: "potatoes", Group: "example.com", Version: "v1", Namespaced: true},
+		},
+		{
+			scenario: "Should resolve built-in short name qualified with core API group",
+			resource: "po.v1.",
+			result:   ResolvedResource{Resource: "pods", Version: "v1", Namespaced: true},
+		},
+		{
+			scenario: "Should resolve plural name of built-in resource",
+			resource: "pods.v1.",
+			result:   ResolvedResource{Resource: "pods", Version: "v1", Namespaced: true},
+		},
+		{
+			scenario: "Should resolve singular name of custom resource",
+			resource: "potato",
+			result:   ResolvedResource{Resource: "potatoes", Group: "example.com", Version: "v1", Namespaced: true},
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			resolver := NewResourceResolver(client.Discovery(), new(mapperMock))
+
+			// when
+			result, err := resolver.Resolve("list", tt.resource, "")
+
+			// then
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.result, result)
+		})
+	}
+}
+
+func TestAmbiguousResourceError(t *testing.T) {
+	err := &ambiguousResourceError{resource: "services", groups: []string{"", "mycompany.com"}}
+
+	assert.EqualError(t, err, "the resource type \"services\" is served by the API groups [core mycompany.com], "+
+		"use --apigroup or a group-qualified name such as services.mycompany.com")
+}
+
+func TestAmbiguousResourceError_BuiltInAlias(t *testing.T) {
+	err := &ambiguousResourceError{resource: "po", groups: []string{"", "example.com"}, builtIn: "pods"}
+
+	assert.EqualError(t, err, "the resource type \"po\" is served by the API groups [core example.com], "+
+		"use pods for the built-in resource, or --apigroup or a group-qualified name such as po.example.com")
+}
+
+func TestResourceResolver_SupportedVerbs(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	client.Resources = []*apismeta.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []apismeta.APIResource{
+				{Version: "v1", Name: "pods", ShortNames: []string{"po"}, Verbs: []string{"list", "get"}},
+			},
+		},
+		{
+			GroupVersion: "rbac.authorization.k8s.io/v1",
+			APIResources: []apismeta.APIResource{
+				{Name: "roles", Verbs: []string{"get", "escalate"}},
+			},
+		},
+	}
+
+	data := []struct {
+		scenario string
+		resource string
+		result   schema.GroupResource
+		verbs    []string
+		err      error
+	}{
+		{
+			scenario: "Should return verbs advertised by discovery",
+			resource: "po",
+			result:   schema.GroupResource{Resource: "pods"},
+			verbs:    []string{"list", "get"},
+		},
+		{
+			scenario: "Should append verbs enforced by RBAC authorizer only",
+			resource: "roles",
+			result:   schema.GroupResource{Group: "rbac.authorization.k8s.io", Resource: "roles"},
+			verbs:    []string{"get", "escalate", "bind"},
+		},
+		{
+			scenario: "Should return error when resource is not served",
+			resource: "pod.v1.example.org",
+			err:      &resourceNotFoundError{resource: "pod.v1.example.org"},
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			resolver := NewResourceResolver(client.Discovery(), new(mapperMock))
+
+			result, verbs, err := resolver.SupportedVerbs(tt.resource)
+
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.result, result)
+			assert.Equal(t, tt.verbs, verbs)
+		})
+	}
+}
+
+// brokenDiscovery fails to serve the resources of the given API group versions.
+type brokenDiscovery struct {
+	discovery.DiscoveryInterface
+	broken map[string]bool
+}
+
+func (d *brokenDiscovery) ServerResourcesForGroupVersion(groupVersion string) (*apismeta.APIResourceList, error) {
+	if d.broken[groupVersion] {
+		return nil, fmt.Errorf("the server is currently unable to handle the request")
+	}
+	return d.DiscoveryInterface.ServerResourcesForGroupVersion(groupVersion)
+}
+
+func TestResourceResolver_indexResources_ManyGroups(t *testing.T) {
+	// given
+	const groups = 40
+	client := fake.NewSimpleClientset()
+	client.Resources = []*apismeta.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []apismeta.APIResource{{Name: "pods", ShortNames: []string{"po"}}},
+		},
+		{
+			GroupVersion: "metrics.example.com/v1beta1",
+			APIResources: []apismeta.APIResource{{Name: "nodes"}},
+		},
+	}
+	expectedIndex := map[string]apismeta.APIResource{
+		"pods": {Name: "pods", ShortNames: []string{"po"}, Version: "v1"},
+		"po":   {Name: "pods", ShortNames: []string{"po"}, Version: "v1"},
+	}
+	var gadgetGroups []string
+	for i := 0; i < groups; i++ {
+		group := fmt.Sprintf("group%02d.example.com", i)
+		widgets := fmt.Sprintf("widgets%02d", i)
+		shortName := fmt.Sprintf("w%02d", i)
+		client.Resources = append(client.Resources, &apismeta.APIResourceList{
+			GroupVersion: group + "/v1",
+			APIResources: []apismeta.APIResource{
+				{Name: widgets, ShortNames: []string{shortName}},
+				{Name: "gadgets"},
+			},
+		})
+
+		widget := apismeta.APIResource{Name: widgets, ShortNames: []string{shortName}, Group: group, Version: "v1"}
+		gadget := apismeta.APIResource{Name: "gadgets", Group: group, Version: "v1"}
+		expectedIndex[widgets] = widget
+		expectedIndex[shortName] = widget
+		expectedIndex[widgets+"."+group] = widget
+		expectedIndex[shortName+"."+group] = widget
+		expectedIndex["gadgets."+group] = gadget
+		gadgetGroups = append(gadgetGroups, group)
+	}
+	discoveryClient := &brokenDiscovery{
+		DiscoveryInterface: client.Discovery(),
+		broken:             map[string]bool{"metrics.example.com/v1beta1": true},
+	}
+
+	for run := 0; run < 10; run++ {
+		// when
+		index, err := NewResourceResolver(discoveryClient, new(mapperMock)).(*resourceResolver).indexResources()
+
+		// then
+		require.NoError(t, err)
+		gadgets, ok := index.resources["gadgets"]
+		require.True(t, ok)
+		delete(index.resources, "gadgets")
+		assert.Equal(t, "gadgets", gadgets.Name)
+		assert.Equal(t, expectedIndex, index.resources)
+		assert.Equal(t, map[string][]string{"gadgets": gadgetGroups}, index.ambiguous)
+	}
+}
+
+// slowDiscovery counts the discovery requests, and answers the API groups request after a delay,
+// so that concurrent callers overlap.
+type slowDiscovery struct {
+	discovery.DiscoveryInterface
+
+	mu               sync.Mutex
+	groupsPasses     int
+	resourceRequests map[string]int
+}
+
+func (d *slowDiscovery) ServerGroups() (*apismeta.APIGroupList, error) {
+	d.mu.Lock()
+	d.groupsPasses++
+	d.mu.Unlock()
+	time.Sleep(50 * time.Millisecond)
+	return d.DiscoveryInterface.ServerGroups()
+}
+
+func (d *slowDiscovery) ServerResourcesForGroupVersion(groupVersion string) (*apismeta.APIResourceList, error) {
+	d.mu.Lock()
+	d.resourceRequests[groupVersion]++
+	d.mu.Unlock()
+	return d.DiscoveryInterface.ServerResourcesForGroupVersion(groupVersion)
+}
+
+func TestResourceResolver_ConcurrentResolveDiscoversOnce(t *testing.T) {
+	// given
+	client := fake.NewSimpleClientset()
+	client.Resources = []*apismeta.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []apismeta.APIResource{{Name: "pods", ShortNames: []string{"po"}, Namespaced: true, Verbs: []string{"list", "get"}}},
+		},
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []apismeta.APIResource{{Name: "deployments", ShortNames: []string{"deploy"}, Namespaced: true, Verbs: []string{"list", "get"}}},
+		},
+	}
+	discoveryClient := &slowDiscovery{DiscoveryInterface: client.Discovery(), resourceRequests: make(map[string]int)}
+	resolver := NewResourceResolver(discoveryClient, new(mapperMock))
+
+	// when
+	var wg sync.WaitGroup
+	errs := make([]error, 20)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resource := "po"
+			if i%2 == 1 {
+				resource = "deploy"
+			}
+			if i%4 == 3 {
+				_, _, errs[i] = resolver.SupportedVerbs(resource)
+				return
+			}
+			_, errs[i] = resolver.Resolve("list", resource, "")
+		}(i)
+	}
+	wg.Wait()
+
+	// then
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 1, discoveryClient.groupsPasses)
+	assert.Equal(t, map[string]int{"v1": 1, "apps/v1": 1}, discoveryClient.resourceRequests)
+}
+
+// openAPIV3OnlyDiscovery lists the API groups, but serves no resources of their versions, like an API server which
+// only publishes the OpenAPI v3 documents of the API group versions.
+type openAPIV3OnlyDiscovery struct {
+	discovery.DiscoveryInterface
+}
+
+func (d *openAPIV3OnlyDiscovery) ServerResourcesForGroupVersion(groupVersion string) (*apismeta.APIResourceList, error) {
+	return nil, apierrors.NewNotFound(schema.GroupResource{}, groupVersion)
+}
+
+func TestResourceResolver_Resolve_OpenAPIV3OnlyDiscovery(t *testing.T) {
+	// given
+	client := fake.NewSimpleClientset()
+	client.Resources = []*apismeta.APIResourceList{
+		{GroupVersion: "v1", APIResources: []apismeta.APIResource{{Name: "pods", Namespaced: true, Verbs: []string{"get"}}}},
+		{GroupVersion: "apps/v1", APIResources: []apismeta.APIResource{{Name: "deployments", Namespaced: true, Verbs: []string{"get"}}}},
+		{GroupVersion: "storage.k8s.io/v1", APIResources: []apismeta.APIResource{{Name: "storageclasses", Verbs: []string{"get"}}}},
+	}
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "storage.k8s.io", Version: "v1", Kind: "StorageClass"}, meta.RESTScopeRoot)
+
+	resolver := NewResourceResolver(&openAPIV3OnlyDiscovery{DiscoveryInterface: client.Discovery()}, mapper)
+
+	data := []struct {
+		scenario    string
+		resource    string
+		subResource string
+		result      ResolvedResource
+		err         error
+	}{
+		{
+			scenario: "Should resolve namespaced resource of core API group",
+			resource: "pods",
+			result:   ResolvedResource{Resource: "pods", Version: "v1", Namespaced: true},
+		},
+		{
+			scenario:    "Should resolve sub-resource",
+			resource:    "pods",
+			subResource: "log",
+			result:      ResolvedResource{Resource: "pods/log", Version: "v1", Namespaced: true},
+		},
+		{
+			scenario: "Should resolve group-qualified resource",
+			resource: "deployments.apps",
+			result:   ResolvedResource{Resource: "deployments", Group: "apps", Version: "v1", Namespaced: true},
+		},
+		{
+			scenario: "Should resolve cluster-scoped resource",
+			resource: "storageclasses.v1.storage.k8s.io",
+			result:   ResolvedResource{Resource: "storageclasses", Group: "storage.k8s.io", Version: "v1", Namespaced: false},
+		},
+		{
+			scenario: "Should return error when resource is unknown",
+			resource: "foos",
+			err:      &resourceNotFoundError{resource: "foos"},
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// when
+			result, err := resolver.Resolve("get", tt.resource, tt.subResource)
+
+			// then
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.result, result)
+		})
+	}
+}
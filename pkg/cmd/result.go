@@ -0,0 +1,311 @@
+package cmd
+
+import (
+	"fmt"
+	rbac "k8s.io/api/rbac/v1"
+	"sort"
+)
+
+const (
+	outputVersionV1Alpha1 = "v1alpha1"
+	outputVersionV1       = "v1"
+)
+
+// outputFormats lists the supported values of the --output flag.
+var outputFormats = []string{outputJSON, outputYAML, outputRawJSON, outputRego, outputLogfmt, outputConfigMap, outputHTML, outputReviewCSV, outputCSV, outputMarkdown, outputDOT, outputMermaid, outputSARIF, outputJUnit, outputJSONLines, outputName}
+
+// allOutputFormats lists the supported values of the --output flag, including the custom-columns and the template
+// output formats.
+func allOutputFormats() []string {
+	formats := append([]string{}, outputFormats...)
+	formats = append(formats, outputWide, customColumnsPrefix+"SPEC")
+	return append(formats, templateOutputFormats...)
+}
+
+func isSupportedOutputFormat(format string) bool {
+	for _, f := range outputFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// outputVersions maps the supported versions of the structured output to the converters
+// from a Result to the document of the given version.
+var outputVersions = map[string]func(Result) interface{}{
+	outputVersionV1Alpha1: toV1Alpha1,
+	outputVersionV1:       toV1,
+}
+
+const (
+	// resultAPIVersion is the apiVersion of the v1 document.
+	resultAPIVersion = "whocan.dev/v1"
+	// resultKind is the kind of the v1 document.
+	resultKind = "WhoCanResult"
+)
+
+// toV1 returns the Result qualified with the apiVersion and kind of the v1 document.
+func toV1(r Result) interface{} {
+	r.APIVersion, r.Kind = resultAPIVersion, resultKind
+	return r
+}
+
+// Result is the structured result of a who-can query.
+type Result struct {
+	// APIVersion and Kind tell the schema of the v1 document like a Kubernetes object, so that downstream tools can
+	// check which schema they read. The fields of an apiVersion are only ever added, never renamed, removed or changed
+	// in meaning. Incompatible changes are released as a new apiVersion.
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+	// Metadata is set when the result is printed by the command, rather than built for further processing.
+	Metadata            *Metadata `json:"metadata,omitempty"`
+	Query               Query     `json:"query"`
+	RoleBindings        []Binding `json:"roleBindings"`
+	ClusterRoleBindings []Binding `json:"clusterRoleBindings"`
+	// NearMisses lists the bindings which grant the queried action only for other resource names.
+	NearMisses []Binding `json:"nearMisses,omitempty"`
+	Warnings   []string  `json:"warnings,omitempty"`
+	// Truncated tells whether subjects were dropped by --max-results, in which case Total is the number of all subjects.
+	Truncated bool `json:"truncated,omitempty"`
+	Total     int  `json:"total,omitempty"`
+}
+
+// Query describes the action that was checked.
+type Query struct {
+	Verb         string `json:"verb"`
+	Resource     string `json:"resource,omitempty"`
+	ResourceName string `json:"resourceName,omitempty"`
+	// ResourceNames and ResourceNameMatch are set when the resource names are read from a file.
+	ResourceNames     []string `json:"resourceNames,omitempty"`
+	ResourceNameMatch string   `json:"resourceNameMatch,omitempty"`
+	// Verbs and RequireAllVerbs are set when the verb is given as a comma-separated list of verbs.
+	Verbs           []string `json:"verbs,omitempty"`
+	RequireAllVerbs bool     `json:"requireAllVerbs,omitempty"`
+	NonResourceURL  string   `json:"nonResourceURL,omitempty"`
+	Namespace       string   `json:"namespace,omitempty"`
+	// Resolved is the resource as resolved by the API discovery. It is not set for NonResourceURLs.
+	Resolved *Resolved `json:"resolved,omitempty"`
+}
+
+// Resolved describes the resource resolved from the TYPE argument of the query.
+type Resolved struct {
+	Resource string `json:"resource"`
+	APIGroup string `json:"apiGroup"`
+	// Namespaced is not set for the `*` resource, which stands for both namespaced and cluster-scoped resources.
+	Namespaced *bool `json:"namespaced,omitempty"`
+}
+
+// Binding is a RoleBinding or a ClusterRoleBinding which grants the queried action to its subjects.
+type Binding struct {
+	Name      string  `json:"name"`
+	Namespace string  `json:"namespace,omitempty"`
+	RoleRef   RoleRef `json:"roleRef"`
+	// Wildcard tells whether the action is granted only through a wildcard verb, resource or API group.
+	Wildcard bool `json:"wildcard"`
+	// ResourceNames lists the resource names to which a near miss is restricted.
+	ResourceNames []string  `json:"resourceNames,omitempty"`
+	Subjects      []Subject `json:"subjects"`
+}
+
+// RoleRef references the Role or the ClusterRole granted by a Binding.
+type RoleRef struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// Subject is a user, a group or a service account.
+type Subject struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// newResult builds the structured result of the query from the matched bindings.
+func (w *whoCan) newResult(roleBindings []rbac.RoleBinding, clusterRoleBindings []rbac.ClusterRoleBinding, warnings []string) Result {
+	result := Result{
+		Query: Query{
+			Verb:           w.verb,
+			Resource:       w.resource,
+			ResourceName:   w.resourceName,
+			NonResourceURL: w.nonResourceURL,
+			Namespace:      w.namespace,
+		},
+		RoleBindings:        []Binding{},
+		ClusterRoleBindings: []Binding{},
+		Warnings:            warnings,
+	}
+	if w.resource != "" {
+		result.Query.Resolved = &Resolved{Resource: w.resource, APIGroup: w.apiGroup}
+		if w.resource != rbac.ResourceAll {
+			namespaced := w.namespacedResource
+			result.Query.Resolved.Namespaced = &namespaced
+		}
+	}
+	if len(w.verbs) > 1 {
+		result.Query.Verbs = w.verbs
+		result.Query.RequireAllVerbs = w.requireAllVerbs
+	}
+	if len(w.resourceNames) > 0 {
+		result.Query.ResourceNames = w.resourceNames
+		result.Query.ResourceNameMatch = w.resourceNameMatch
+	}
+	for _, rb := range roleBindings {
+		result.RoleBindings = append(result.RoleBindings, w.newBinding(rb.Name, rb.Namespace, rb.RoleRef, rb.Subjects))
+	}
+	for _, crb := range clusterRoleBindings {
+		result.ClusterRoleBindings = append(result.ClusterRoleBindings, w.newBinding(crb.Name, "", crb.RoleRef, crb.Subjects))
+	}
+	if w.sortBy != "" {
+		sortBindings(result.RoleBindings, w.sortBy)
+		sortBindings(result.ClusterRoleBindings, w.sortBy)
+	}
+	result.NearMisses = w.nearMissBindings()
+	result.truncate(w.maxResults, w.sortBy)
+	return result
+}
+
+func (w *whoCan) newBinding(name, namespace string, roleRef rbac.RoleRef, subjects []rbac.Subject) Binding {
+	binding := Binding{
+		Name:      name,
+		Namespace: namespace,
+		RoleRef:   RoleRef{Kind: roleRef.Kind, Name: roleRef.Name},
+		Wildcard:  w.wildcards[newRoleFromRef(&roleRef, namespace)],
+		Subjects:  []Subject{},
+	}
+	for _, s := range subjects {
+		binding.Subjects = append(binding.Subjects, Subject{Kind: s.Kind, Name: s.Name, Namespace: s.Namespace})
+	}
+	return binding
+}
+
+// forEachBinding calls fn with the kind of the binding and each matched binding, first for the RoleBindings and then
+// for the ClusterRoleBindings.
+func (r Result) forEachBinding(fn func(bindingKind string, b Binding)) {
+	for _, b := range r.RoleBindings {
+		fn("RoleBinding", b)
+	}
+	for _, b := range r.ClusterRoleBindings {
+		fn("ClusterRoleBinding", b)
+	}
+}
+
+// Subjects returns the distinct subjects of all RoleBindings and ClusterRoleBindings in the order of their first occurrence.
+func (r Result) Subjects() []Subject {
+	seen := make(map[Subject]struct{})
+	subjects := []Subject{}
+	for _, bindings := range [][]Binding{r.RoleBindings, r.ClusterRoleBindings} {
+		for _, b := range bindings {
+			for _, s := range b.Subjects {
+				if _, ok := seen[s]; ok {
+					continue
+				}
+				seen[s] = struct{}{}
+				subjects = append(subjects, s)
+			}
+		}
+	}
+	return subjects
+}
+
+// HasSubject returns `true` if the given subject is granted the queried action by any RoleBinding or ClusterRoleBinding.
+func (r Result) HasSubject(subject Subject) bool {
+	for _, s := range r.Subjects() {
+		if s == subject {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterByNamespace returns a copy of the Result with the bindings which grant the queried action in the given namespace.
+// RoleBindings in other namespaces are dropped, whereas ClusterRoleBindings are kept, because they grant the action
+// in every namespace.
+func (r Result) FilterByNamespace(namespace string) Result {
+	inNamespace := func(bindings []Binding) []Binding {
+		var filtered []Binding
+		for _, b := range bindings {
+			if b.Namespace == "" || b.Namespace == namespace {
+				filtered = append(filtered, b)
+			}
+		}
+		return filtered
+	}
+
+	filtered := r
+	filtered.RoleBindings = inNamespace(r.RoleBindings)
+	if filtered.RoleBindings == nil {
+		filtered.RoleBindings = []Binding{}
+	}
+	filtered.ClusterRoleBindings = append([]Binding{}, r.ClusterRoleBindings...)
+	filtered.NearMisses = inNamespace(r.NearMisses)
+	return filtered
+}
+
+// convert returns the document representing the Result in the given version of the structured output.
+func (r Result) convert(version string) (interface{}, error) {
+	converter, ok := outputVersions[version]
+	if !ok {
+		return nil, fmt.Errorf("unsupported output version \"%s\", expected one of %v", version, supportedOutputVersions())
+	}
+	return converter(r), nil
+}
+
+func supportedOutputVersions() []string {
+	var versions []string
+	for version := range outputVersions {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// resultV1Alpha1 is the v1alpha1 version of the structured output, which lists the subjects
+// of all bindings in a single flat list.
+type resultV1Alpha1 struct {
+	Verb           string            `json:"verb"`
+	Resource       string            `json:"resource,omitempty"`
+	ResourceName   string            `json:"resourceName,omitempty"`
+	NonResourceURL string            `json:"nonResourceURL,omitempty"`
+	Namespace      string            `json:"namespace,omitempty"`
+	Subjects       []subjectV1Alpha1 `json:"subjects"`
+	Warnings       []string          `json:"warnings,omitempty"`
+}
+
+type subjectV1Alpha1 struct {
+	Binding     string `json:"binding"`
+	BindingKind string `json:"bindingKind"`
+	Namespace   string `json:"namespace,omitempty"`
+	Kind        string `json:"kind"`
+	Name        string `json:"name"`
+	SANamespace string `json:"saNamespace,omitempty"`
+}
+
+func toV1Alpha1(r Result) interface{} {
+	doc := resultV1Alpha1{
+		Verb:           r.Query.Verb,
+		Resource:       r.Query.Resource,
+		ResourceName:   r.Query.ResourceName,
+		NonResourceURL: r.Query.NonResourceURL,
+		Namespace:      r.Query.Namespace,
+		Subjects:       []subjectV1Alpha1{},
+		Warnings:       r.Warnings,
+	}
+	add := func(bindingKind string, bindings []Binding) {
+		for _, b := range bindings {
+			for _, s := range b.Subjects {
+				doc.Subjects = append(doc.Subjects, subjectV1Alpha1{
+					Binding:     b.Name,
+					BindingKind: bindingKind,
+					Namespace:   b.Namespace,
+					Kind:        s.Kind,
+					Name:        s.Name,
+					SANamespace: s.Namespace,
+				})
+			}
+		}
+	}
+	add("RoleBinding", r.RoleBindings)
+	add("ClusterRoleBinding", r.ClusterRoleBindings)
+	return doc
+}
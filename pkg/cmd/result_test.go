@@ -0,0 +1,407 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"testing"
+
+	rbac "k8s.io/api/rbac/v1"
+)
+
+func TestWhoCan_printResult(t *testing.T) {
+	roleBindings := []rbac.RoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Alice-can-view-pods", Namespace: "default"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "view-pods"},
+			Subjects: []rbac.Subject{
+				{Name: "Alice", Kind: "User"},
+			}},
+	}
+	clusterRoleBindings := []rbac.ClusterRoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Bob-can-view-pods"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "view"},
+			Subjects: []rbac.Subject{
+				{Name: "Bob", Kind: "ServiceAccount", Namespace: "foo"},
+			},
+		},
+	}
+
+	data := []struct {
+		scenario string
+
+		outputFormat        string
+		outputVersion       string
+		roleBindings        []rbac.RoleBinding
+		clusterRoleBindings []rbac.ClusterRoleBinding
+
+		output string
+	}{
+		{
+			scenario:            "Should print bindings in v1 version",
+			outputVersion:       outputVersionV1,
+			roleBindings:        roleBindings,
+			clusterRoleBindings: clusterRoleBindings,
+			output: `{
+  "apiVersion": "whocan.dev/v1",
+  "kind": "WhoCanResult",
+  "query": {
+    "verb": "get",
+    "resource": "pods",
+    "namespace": "default",
+    "resolved": {
+      "resource": "pods",
+      "apiGroup": "",
+      "namespaced": true
+    }
+  },
+  "roleBindings": [
+    {
+      "name": "Alice-can-view-pods",
+      "namespace": "default",
+      "roleRef": {
+        "kind": "Role",
+        "name": "view-pods"
+      },
+      "wildcard": false,
+      "subjects": [
+        {
+          "kind": "User",
+          "name": "Alice"
+        }
+      ]
+    }
+  ],
+  "clusterRoleBindings": [
+    {
+      "name": "Bob-can-view-pods",
+      "roleRef": {
+        "kind": "ClusterRole",
+        "name": "view"
+      },
+      "wildcard": true,
+      "subjects": [
+        {
+          "kind": "ServiceAccount",
+          "name": "Bob",
+          "namespace": "foo"
+        }
+      ]
+    }
+  ]
+}
+`,
+		},
+		{
+			scenario:            "Should print bindings in v1alpha1 version",
+			outputVersion:       outputVersionV1Alpha1,
+			roleBindings:        roleBindings,
+			clusterRoleBindings: clusterRoleBindings,
+			output: `{
+  "verb": "get",
+  "resource": "pods",
+  "namespace": "default",
+  "subjects": [
+    {
+      "binding": "Alice-can-view-pods",
+      "bindingKind": "RoleBinding",
+      "namespace": "default",
+      "kind": "User",
+      "name": "Alice"
+    },
+    {
+      "binding": "Bob-can-view-pods",
+      "bindingKind": "ClusterRoleBinding",
+      "kind": "ServiceAccount",
+      "name": "Bob",
+      "saNamespace": "foo"
+    }
+  ]
+}
+`,
+		},
+		{
+			scenario:      "Should print empty lists in v1 version",
+			outputVersion: outputVersionV1,
+			output: `{
+  "apiVersion": "whocan.dev/v1",
+  "kind": "WhoCanResult",
+  "query": {
+    "verb": "get",
+    "resource": "pods",
+    "namespace": "default",
+    "resolved": {
+      "resource": "pods",
+      "apiGroup": "",
+      "namespaced": true
+    }
+  },
+  "roleBindings": [],
+  "clusterRoleBindings": []
+}
+`,
+		},
+		{
+			scenario:            "Should print bindings as YAML",
+			outputFormat:        outputYAML,
+			outputVersion:       outputVersionV1,
+			roleBindings:        roleBindings,
+			clusterRoleBindings: clusterRoleBindings,
+			output: `apiVersion: whocan.dev/v1
+clusterRoleBindings:
+- name: Bob-can-view-pods
+  roleRef:
+    kind: ClusterRole
+    name: view
+  subjects:
+  - kind: ServiceAccount
+    name: Bob
+    namespace: foo
+  wildcard: true
+kind: WhoCanResult
+query:
+  namespace: default
+  resolved:
+    apiGroup: ""
+    namespaced: true
+    resource: pods
+  resource: pods
+  verb: get
+roleBindings:
+- name: Alice-can-view-pods
+  namespace: default
+  roleRef:
+    kind: Role
+    name: view-pods
+  subjects:
+  - kind: User
+    name: Alice
+  wildcard: false
+`,
+		},
+		{
+			scenario:      "Should print empty lists as YAML",
+			outputFormat:  outputYAML,
+			outputVersion: outputVersionV1,
+			output: `apiVersion: whocan.dev/v1
+clusterRoleBindings: []
+kind: WhoCanResult
+query:
+  namespace: default
+  resolved:
+    apiGroup: ""
+    namespaced: true
+    resource: pods
+  resource: pods
+  verb: get
+roleBindings: []
+`,
+		},
+		{
+			scenario:      "Should print empty lists in v1alpha1 version",
+			outputVersion: outputVersionV1Alpha1,
+			output: `{
+  "verb": "get",
+  "resource": "pods",
+  "namespace": "default",
+  "subjects": []
+}
+`,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			streams, _, out, _ := clioptions.NewTestIOStreams()
+			outputFormat := tt.outputFormat
+			if outputFormat == "" {
+				outputFormat = outputJSON
+			}
+			wc := whoCan{
+				verb:               "get",
+				resource:           "pods",
+				namespacedResource: true,
+				namespace:          "default",
+				outputFormat:       outputFormat,
+				outputVersion:      tt.outputVersion,
+				wildcards:          map[role]bool{{name: "view", isClusterRole: true}: true},
+				IOStreams:          streams,
+			}
+
+			// when
+			err := wc.printResult(wc.newResult(tt.roleBindings, tt.clusterRoleBindings, nil))
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.output, out.String())
+		})
+	}
+}
+
+func TestResult_helpers(t *testing.T) {
+	alice := Subject{Kind: "User", Name: "Alice"}
+	bob := Subject{Kind: "ServiceAccount", Name: "Bob", Namespace: "foo"}
+	eve := Subject{Kind: "User", Name: "Eve"}
+
+	result := Result{
+		Query: Query{Verb: "get", Resource: "pods"},
+		RoleBindings: []Binding{
+			{Name: "Alice-can-view-pods", Namespace: "foo", Subjects: []Subject{alice}},
+			{Name: "Bob-can-view-pods", Namespace: "bar", Subjects: []Subject{bob, alice}},
+		},
+		ClusterRoleBindings: []Binding{
+			{Name: "Eve-can-view-pods", Subjects: []Subject{eve}},
+		},
+		NearMisses: []Binding{
+			{Name: "Eve-can-view-other-pods", Namespace: "bar", Subjects: []Subject{eve}},
+		},
+	}
+
+	t.Run("Should return distinct subjects in order of occurrence", func(t *testing.T) {
+		assert.Equal(t, []Subject{alice, bob, eve}, result.Subjects())
+	})
+
+	t.Run("Should return empty subjects for empty result", func(t *testing.T) {
+		assert.Equal(t, []Subject{}, Result{}.Subjects())
+	})
+
+	t.Run("Should tell whether subject is granted action", func(t *testing.T) {
+		assert.True(t, result.HasSubject(bob))
+		assert.True(t, result.HasSubject(eve))
+		assert.False(t, result.HasSubject(Subject{Kind: "Group", Name: "Alice"}))
+		assert.False(t, result.HasSubject(Subject{Kind: "ServiceAccount", Name: "Bob", Namespace: "bar"}))
+	})
+
+	t.Run("Should keep RoleBindings in namespace and all ClusterRoleBindings", func(t *testing.T) {
+		// when
+		filtered := result.FilterByNamespace("foo")
+
+		// then
+		assert.Equal(t, []Binding{result.RoleBindings[0]}, filtered.RoleBindings)
+		assert.Equal(t, result.ClusterRoleBindings, filtered.ClusterRoleBindings)
+		assert.Empty(t, filtered.NearMisses)
+		assert.Equal(t, []Subject{alice, eve}, filtered.Subjects())
+		assert.Len(t, result.RoleBindings, 2)
+	})
+
+	t.Run("Should return empty RoleBindings when none is in namespace", func(t *testing.T) {
+		assert.Equal(t, []Binding{}, result.FilterByNamespace("baz").RoleBindings)
+	})
+}
+
+func TestWhoCan_newResult_Resolved(t *testing.T) {
+	namespaced := true
+
+	data := []struct {
+		scenario string
+		wc       whoCan
+		resolved *Resolved
+	}{
+		{
+			scenario: "Should describe resolved resource",
+			wc:       whoCan{verb: "get", resource: "deployments", apiGroup: "apps", namespacedResource: true},
+			resolved: &Resolved{Resource: "deployments", APIGroup: "apps", Namespaced: &namespaced},
+		},
+		{
+			scenario: "Should leave scope of all resources unset",
+			wc:       whoCan{verb: "get", resource: "*"},
+			resolved: &Resolved{Resource: "*"},
+		},
+		{
+			scenario: "Should not describe non-resource URL",
+			wc:       whoCan{verb: "get", nonResourceURL: "/healthz"},
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			assert.Equal(t, tt.resolved, tt.wc.newResult(nil, nil, nil).Query.Resolved)
+		})
+	}
+}
+
+func TestWhoCan_newResult_Verbs(t *testing.T) {
+	data := []struct {
+		scenario string
+		wc       whoCan
+		query    Query
+	}{
+		{
+			scenario: "Should describe single verb",
+			wc:       whoCan{verb: "get", verbs: []string{"get"}, resource: "secrets", namespace: "foo"},
+			query:    Query{Verb: "get", Resource: "secrets", Namespace: "foo", Resolved: &Resolved{Resource: "secrets", Namespaced: new(bool)}},
+		},
+		{
+			scenario: "Should describe comma-separated verbs",
+			wc:       whoCan{verb: "get,list", verbs: []string{"get", "list"}, requireAllVerbs: true, resource: "secrets", namespace: "foo"},
+			query: Query{Verb: "get,list", Verbs: []string{"get", "list"}, RequireAllVerbs: true, Resource: "secrets", Namespace: "foo",
+				Resolved: &Resolved{Resource: "secrets", Namespaced: new(bool)}},
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			assert.Equal(t, tt.query, tt.wc.newResult(nil, nil, nil).Query)
+		})
+	}
+}
+
+func TestWhoCan_printResult_Warnings(t *testing.T) {
+	roleBindings := []rbac.RoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Alice-can-view-pods", Namespace: "default"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "view-pods"},
+			Subjects:   []rbac.Subject{{Name: "Alice", Kind: "User"}},
+		},
+	}
+
+	data := []struct {
+		outputFormat string
+		errOut       string
+	}{
+		{outputFormat: outputJSON, errOut: ""},
+		{outputFormat: outputYAML, errOut: ""},
+		{outputFormat: outputRego, errOut: ""},
+		{outputFormat: outputConfigMap, errOut: ""},
+		{outputFormat: outputHTML, errOut: ""},
+		{outputFormat: "go-template={{len .warnings}}", errOut: ""},
+		{outputFormat: outputCSV, errOut: "Warning: list roles\n"},
+		{outputFormat: outputReviewCSV, errOut: "Warning: list roles\n"},
+		{outputFormat: outputName, errOut: "Warning: list roles\n"},
+		{outputFormat: outputJSONLines, errOut: "Warning: list roles\n"},
+		{outputFormat: outputLogfmt, errOut: "Warning: list roles\n"},
+		{outputFormat: outputMarkdown, errOut: "Warning: list roles\n"},
+		{outputFormat: outputMermaid, errOut: "Warning: list roles\n"},
+		{outputFormat: outputDOT, errOut: "Warning: list roles\n"},
+		{outputFormat: outputJUnit, errOut: "Warning: list roles\n"},
+		{outputFormat: outputSARIF, errOut: "Warning: list roles\n"},
+		{outputFormat: "custom-columns=SUBJECT:.subject.name", errOut: "Warning: list roles\n"},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.outputFormat, func(t *testing.T) {
+			// given
+			streams, _, out, errOut := clioptions.NewTestIOStreams()
+			wc := whoCan{
+				verb:          "get",
+				resource:      "pods",
+				namespace:     "default",
+				outputFormat:  tt.outputFormat,
+				outputVersion: outputVersionV1,
+				IOStreams:     streams,
+			}
+
+			// when
+			err := wc.printResult(wc.newResult(roleBindings, nil, []string{"list roles"}))
+
+			// then
+			require.NoError(t, err)
+			assert.NotEmpty(t, out.String())
+			assert.Equal(t, tt.errOut, errOut.String())
+			assert.True(t, wc.resultPrinted)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import "io"
+
+// reviewCSVHeader is the header of the access review CSV. The last column is left blank for the reviewer
+// to approve or revoke each grant.
+var reviewCSVHeader = []string{"Subject", "Access Granted", "Source Binding", "Role", "Scope", "Approved?"}
+
+// printReviewCSV writes one CSV row per subject of the Result, tailored to spreadsheet-based access reviews.
+// Subjects are given as KIND/NAME, such as `ServiceAccount/foo:bar`, and the scope is the namespace of a RoleBinding,
+// or cluster-wide for a ClusterRoleBinding.
+func (w *whoCan) printReviewCSV(out io.Writer, result Result) error {
+	access := reviewAccess(result.Query)
+	var rows [][]string
+	result.forEachBinding(func(bindingKind string, b Binding) {
+		scope := b.Namespace
+		if bindingKind == "ClusterRoleBinding" {
+			scope = clusterWide
+		}
+		for _, s := range b.Subjects {
+			subject := s.Name
+			if s.Namespace != "" {
+				subject = s.Namespace + ":" + s.Name
+			}
+			rows = append(rows, []string{s.Kind + "/" + subject, access, bindingKind + "/" + b.Name,
+				b.RoleRef.Kind + "/" + b.RoleRef.Name, scope, ""})
+		}
+	})
+	return writeCSV(out, reviewCSVHeader, rows)
+}
+
+// reviewAccess describes the queried action, such as `get secrets/db` or `get /logs`.
+func reviewAccess(q Query) string {
+	if q.NonResourceURL != "" {
+		return q.Verb + " " + q.NonResourceURL
+	}
+	resource := q.Resource
+	if q.Resolved != nil {
+		resource = qualifiedName(q.Resolved.Resource, q.Resolved.APIGroup)
+	}
+	if q.ResourceName != "" {
+		resource += "/" + q.ResourceName
+	}
+	return q.Verb + " " + resource
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"testing"
+)
+
+func TestWhoCan_printReviewCSV(t *testing.T) {
+	// given
+	roleBindings := []rbac.RoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Alice-can-read-db", Namespace: "default"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "read-db"},
+			Subjects: []rbac.Subject{
+				{Name: "Alice Smith", Kind: "User"},
+				{Name: "team, ops", Kind: "Group"},
+			},
+		},
+	}
+	clusterRoleBindings := []rbac.ClusterRoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Bob-can-read-secrets"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "view"},
+			Subjects: []rbac.Subject{
+				{Name: "bob", Kind: "ServiceAccount", Namespace: "foo"},
+			},
+		},
+	}
+
+	streams, _, out, _ := clioptions.NewTestIOStreams()
+	wc := whoCan{
+		verb:               "get",
+		resource:           "secrets",
+		resourceName:       "db",
+		namespacedResource: true,
+		namespace:          "default",
+		outputFormat:       outputReviewCSV,
+		IOStreams:          streams,
+	}
+	result := wc.newResult(roleBindings, clusterRoleBindings, nil)
+	result.Warnings = []string{"The user is not allowed to list roles in the default namespace"}
+
+	// when
+	err := wc.printResult(result)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, `Subject,Access Granted,Source Binding,Role,Scope,Approved?
+User/Alice Smith,get secrets/db,RoleBinding/Alice-can-read-db,Role/read-db,default,
+"Group/team, ops",get secrets/db,RoleBinding/Alice-can-read-db,Role/read-db,default,
+ServiceAccount/foo:bob,get secrets/db,ClusterRoleBinding/Bob-can-read-secrets,ClusterRole/view,cluster-wide,
+`, out.String())
+}
+
+func TestReviewAccess(t *testing.T) {
+	data := []struct {
+		scenario string
+		query    Query
+		access   string
+	}{
+		{
+			scenario: "Should describe resource of core API group",
+			query:    Query{Verb: "list", Resource: "pods", Resolved: &Resolved{Resource: "pods"}},
+			access:   "list pods",
+		},
+		{
+			scenario: "Should describe resource qualified with API group",
+			query:    Query{Verb: "delete", Resource: "deployments", Resolved: &Resolved{Resource: "deployments", APIGroup: "apps"}},
+			access:   "delete deployments.apps",
+		},
+		{
+			scenario: "Should describe non-resource URL",
+			query:    Query{Verb: "get", NonResourceURL: "/logs"},
+			access:   "get /logs",
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			assert.Equal(t, tt.access, reviewAccess(tt.query))
+		})
+	}
+}
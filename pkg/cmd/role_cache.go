@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"sync"
+
+	rbac "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientrbac "k8s.io/client-go/kubernetes/typed/rbac/v1"
+)
+
+// clusterRoleCache holds the rules of the ClusterRoles listed once for the duration of a query, indexed by name.
+// A ClusterRole missing from the list, for example because it was created afterwards or because the user is not
+// allowed to list ClusterRoles, is fetched once on its first lookup. It is safe for concurrent use.
+type clusterRoleCache struct {
+	client clientrbac.ClusterRoleInterface
+	// listed holds the rules of the listed ClusterRoles by name. It is never modified after the cache is created.
+	listed map[string][]rbac.PolicyRule
+
+	mu      sync.Mutex
+	entries map[string]*clusterRoleEntry
+}
+
+// clusterRoleEntry holds the outcome of fetching a single ClusterRole. The once guards the fetch,
+// so that concurrent lookups of the same ClusterRole wait for the first one instead of fetching it again.
+type clusterRoleEntry struct {
+	once  sync.Once
+	rules []rbac.PolicyRule
+	found bool
+	err   error
+}
+
+func newClusterRoleCache(client clientrbac.ClusterRoleInterface, clusterRoles []rbac.ClusterRole) *clusterRoleCache {
+	listed := make(map[string][]rbac.PolicyRule, len(clusterRoles))
+	for _, cr := range clusterRoles {
+		listed[cr.Name] = cr.Rules
+	}
+	return &clusterRoleCache{
+		client:  client,
+		listed:  listed,
+		entries: make(map[string]*clusterRoleEntry),
+	}
+}
+
+// rulesOf returns the rules of the ClusterRole with the given name. A missing ClusterRole has no rules.
+func (c *clusterRoleCache) rulesOf(name string) ([]rbac.PolicyRule, error) {
+	rules, _, err := c.lookup(name)
+	if err != nil {
+		return nil, fmt.Errorf("getting ClusterRole %s: %v", name, err)
+	}
+	return rules, nil
+}
+
+// lookup returns the rules of the ClusterRole with the given name and whether it exists.
+// The error is returned as received from the API server, so that the caller can tell why the fetch failed.
+func (c *clusterRoleCache) lookup(name string) ([]rbac.PolicyRule, bool, error) {
+	if rules, ok := c.listed[name]; ok {
+		return rules, true, nil
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[name]
+	if !ok {
+		entry = &clusterRoleEntry{}
+		c.entries[name] = entry
+	}
+	c.mu.Unlock()
+
+	entry.once.Do(func() {
+		cr, err := c.client.Get(name, meta.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return
+		}
+		if err != nil {
+			entry.err = err
+			return
+		}
+		entry.rules, entry.found = cr.Rules, true
+	})
+	return entry.rules, entry.found, entry.err
+}
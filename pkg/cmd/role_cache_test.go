@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbac "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sync"
+	"testing"
+)
+
+// countClusterRoleGets returns the number of times each ClusterRole was fetched through the fake client.
+func countClusterRoleGets(client *fake.Clientset) map[string]int {
+	gets := make(map[string]int)
+	for _, action := range client.Actions() {
+		if get, ok := action.(clienttesting.GetAction); ok && action.GetVerb() == "get" && action.GetResource().Resource == "clusterroles" {
+			gets[get.GetName()]++
+		}
+	}
+	return gets
+}
+
+func TestClusterRoleCache_rulesOf(t *testing.T) {
+	viewPods := rbac.PolicyRule{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"pods"}}
+
+	t.Run("Should not fetch listed ClusterRole", func(t *testing.T) {
+		// given
+		client := fake.NewSimpleClientset()
+		cache := newClusterRoleCache(client.RbacV1().ClusterRoles(),
+			[]rbac.ClusterRole{{ObjectMeta: meta.ObjectMeta{Name: "view"}, Rules: []rbac.PolicyRule{viewPods}}})
+
+		// when
+		rules, err := cache.rulesOf("view")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []rbac.PolicyRule{viewPods}, rules)
+		assert.Empty(t, countClusterRoleGets(client))
+	})
+
+	t.Run("Should fetch repeated ClusterRole missing from list once under concurrency", func(t *testing.T) {
+		// given
+		client := fake.NewSimpleClientset(&rbac.ClusterRole{ObjectMeta: meta.ObjectMeta{Name: "view"}, Rules: []rbac.PolicyRule{viewPods}})
+		cache := newClusterRoleCache(client.RbacV1().ClusterRoles(), nil)
+
+		// when
+		var wg sync.WaitGroup
+		results := make([][]rbac.PolicyRule, 50)
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				results[i], _ = cache.rulesOf("view")
+			}(i)
+		}
+		wg.Wait()
+
+		// then
+		for _, rules := range results {
+			assert.Equal(t, []rbac.PolicyRule{viewPods}, rules)
+		}
+		assert.Equal(t, map[string]int{"view": 1}, countClusterRoleGets(client))
+	})
+
+	t.Run("Should return no rules for missing ClusterRole", func(t *testing.T) {
+		// given
+		cache := newClusterRoleCache(fake.NewSimpleClientset().RbacV1().ClusterRoles(), nil)
+
+		// when
+		rules, err := cache.rulesOf("missing")
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, rules)
+	})
+
+	t.Run("Should return error when ClusterRole cannot be fetched", func(t *testing.T) {
+		// given
+		client := fake.NewSimpleClientset()
+		client.PrependReactor("get", "clusterroles", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("forbidden")
+		})
+		cache := newClusterRoleCache(client.RbacV1().ClusterRoles(), nil)
+
+		// when
+		_, err := cache.rulesOf("view")
+
+		// then
+		assert.EqualError(t, err, "getting ClusterRole view: forbidden")
+	})
+}
+
+func TestLookupServiceAccountGrants_ListsClusterRolesOnce(t *testing.T) {
+	viewPods := rbac.PolicyRule{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"pods"}}
+
+	data := []struct {
+		scenario        string
+		listForbidden   bool
+		clusterRoleGets map[string]int
+	}{
+		{
+			scenario:        "Should look up listed ClusterRole without fetching it",
+			clusterRoleGets: map[string]int{},
+		},
+		{
+			scenario:        "Should fetch ClusterRole once when ClusterRoles cannot be listed",
+			listForbidden:   true,
+			clusterRoleGets: map[string]int{"view": 1},
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			objects := []runtime.Object{
+				&rbac.ClusterRole{ObjectMeta: meta.ObjectMeta{Name: "view"}, Rules: []rbac.PolicyRule{viewPods}},
+			}
+			var serviceAccounts []rbac.Subject
+			for i := 0; i < 30; i++ {
+				sa := rbac.Subject{Kind: rbac.ServiceAccountKind, Name: fmt.Sprintf("sa-%d", i), Namespace: "foo"}
+				serviceAccounts = append(serviceAccounts, sa)
+				objects = append(objects, &rbac.RoleBinding{
+					ObjectMeta: meta.ObjectMeta{Name: fmt.Sprintf("sa-%d-can-view", i), Namespace: "foo"},
+					RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "view"},
+					Subjects:   []rbac.Subject{sa},
+				})
+			}
+			client := fake.NewSimpleClientset(objects...)
+			if tt.listForbidden {
+				client.PrependReactor("list", "clusterroles", func(action clienttesting.Action) (bool, runtime.Object, error) {
+					return true, nil, apierrors.NewForbidden(rbac.Resource("clusterroles"), "", errors.New("forbidden"))
+				})
+			}
+
+			snapshot, err := loadRBACSnapshot(client.RbacV1(), "foo")
+			require.NoError(t, err)
+
+			// when
+			results, err := lookupServiceAccountGrants(snapshot, serviceAccounts)
+
+			// then
+			require.NoError(t, err)
+			require.Len(t, results, 30)
+			for _, r := range results {
+				assert.Len(t, r.grants, 1)
+			}
+			assert.Equal(t, tt.clusterRoleGets, countClusterRoleGets(client))
+		})
+	}
+}
+
+func TestLoadRBACSnapshot_ListClusterRolesError(t *testing.T) {
+	// given
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("list", "clusterroles", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("connection refused")
+	})
+
+	// when
+	_, err := loadRBACSnapshot(client.RbacV1(), "foo")
+
+	// then
+	assert.EqualError(t, err, "listing ClusterRoles: connection refused")
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"text/tabwriter"
+
+	rbac "k8s.io/api/rbac/v1"
+)
+
+// roleSummary is a Role or a ClusterRole referenced by the matched bindings
+// with the number of distinct subjects it grants the queried action to.
+type roleSummary struct {
+	kind      string
+	namespace string
+	name      string
+	subjects  int
+}
+
+// rolesSummary returns the distinct roles referenced by the given bindings, ordered by the number
+// of subjects they grant the action to in descending order.
+func rolesSummary(roleBindings []rbac.RoleBinding, clusterRoleBindings []rbac.ClusterRoleBinding) []roleSummary {
+	type roleKey struct {
+		kind, namespace, name string
+	}
+	subjects := make(map[roleKey]map[rbac.Subject]struct{})
+	add := func(key roleKey, s []rbac.Subject) {
+		if _, ok := subjects[key]; !ok {
+			subjects[key] = make(map[rbac.Subject]struct{})
+		}
+		for _, subject := range s {
+			subjects[key][subject] = struct{}{}
+		}
+	}
+
+	for _, rb := range roleBindings {
+		key := roleKey{kind: rb.RoleRef.Kind, name: rb.RoleRef.Name}
+		// Roles are namespaced, so roles with the same name in different namespaces are distinct.
+		if rb.RoleRef.Kind == "Role" {
+			key.namespace = rb.Namespace
+		}
+		add(key, rb.Subjects)
+	}
+	for _, crb := range clusterRoleBindings {
+		add(roleKey{kind: crb.RoleRef.Kind, name: crb.RoleRef.Name}, crb.Subjects)
+	}
+
+	var summary []roleSummary
+	for key, s := range subjects {
+		summary = append(summary, roleSummary{kind: key.kind, namespace: key.namespace, name: key.name, subjects: len(s)})
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		a, b := summary[i], summary[j]
+		if a.subjects != b.subjects {
+			return a.subjects > b.subjects
+		}
+		if a.kind != b.kind {
+			return a.kind < b.kind
+		}
+		if a.namespace != b.namespace {
+			return a.namespace < b.namespace
+		}
+		return a.name < b.name
+	})
+	return summary
+}
+
+// printRolesSummary prints the distinct roles which are the sources of the access granted by the matched bindings.
+func (w *whoCan) printRolesSummary(roleBindings []rbac.RoleBinding, clusterRoleBindings []rbac.ClusterRoleBinding) {
+	if !w.showRolesSummary {
+		return
+	}
+
+	summary := rolesSummary(roleBindings, clusterRoleBindings)
+	if len(summary) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintln(w.Out)
+	wr := new(tabwriter.Writer)
+	wr.Init(w.Out, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintln(wr, "ROLE\tNAMESPACE\tSUBJECTS")
+	for _, r := range summary {
+		name := r.kind + "/" + r.name
+		if w.aggregatedRoles.match(&rbac.RoleRef{Kind: r.kind, Name: r.name}, r.namespace) {
+			name += " (aggregated)"
+		}
+		_, _ = fmt.Fprintf(wr, "%s\t%s\t%d\n", name, r.namespace, r.subjects)
+	}
+	_ = wr.Flush()
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"testing"
+)
+
+func TestWhoCan_printRolesSummary(t *testing.T) {
+	// given
+	alice := rbac.Subject{Kind: "User", Name: "Alice"}
+	bob := rbac.Subject{Kind: "User", Name: "Bob"}
+	ops := rbac.Subject{Kind: "Group", Name: "ops"}
+
+	roleBindings := []rbac.RoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Alice-can-edit-pods", Namespace: "foo"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "edit-pods"},
+			Subjects:   []rbac.Subject{alice},
+		},
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Bob-can-edit-pods", Namespace: "bar"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "edit-pods"},
+			Subjects:   []rbac.Subject{bob},
+		},
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Bob-can-view", Namespace: "foo"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "view"},
+			Subjects:   []rbac.Subject{bob, alice},
+		},
+	}
+	clusterRoleBindings := []rbac.ClusterRoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Ops-can-view"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "view"},
+			Subjects:   []rbac.Subject{ops, alice},
+		},
+	}
+
+	streams, _, out, _ := clioptions.NewTestIOStreams()
+	wc := whoCan{showRolesSummary: true, IOStreams: streams}
+
+	// when
+	wc.printRolesSummary(roleBindings, clusterRoleBindings)
+
+	// then
+	assert.Equal(t, `
+ROLE              NAMESPACE  SUBJECTS
+ClusterRole/view             3
+Role/edit-pods    bar        1
+Role/edit-pods    foo        1
+`, out.String())
+}
+
+func TestWhoCan_printRolesSummary_Aggregated(t *testing.T) {
+	// given
+	clusterRoleBindings := []rbac.ClusterRoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Ops-can-view"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "view"},
+			Subjects:   []rbac.Subject{{Kind: "Group", Name: "ops"}},
+		},
+	}
+
+	streams, _, out, _ := clioptions.NewTestIOStreams()
+	wc := whoCan{
+		showRolesSummary: true,
+		aggregatedRoles:  roles{{name: "view", isClusterRole: true}: {}},
+		IOStreams:        streams,
+	}
+
+	// when
+	wc.printRolesSummary(nil, clusterRoleBindings)
+
+	// then
+	assert.Equal(t, `
+ROLE                           NAMESPACE  SUBJECTS
+ClusterRole/view (aggregated)             1
+`, out.String())
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	// sarifRuleID identifies the single rule of the SARIF log, which is the queried action.
+	sarifRuleID = "who-can"
+)
+
+// sarifLog is the subset of the SARIF 2.1.0 format which is used to report the subjects of the matched bindings
+// to code scanning dashboards.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations"`
+	Properties map[string]string `json:"properties"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// printSARIF writes the Result as a SARIF log with a result per subject of the matched bindings, such as
+// "User jane can delete secrets cluster-wide through ClusterRoleBinding admins", located at the binding.
+// Grants through a wildcard are reported as warnings and other grants as notes.
+func (w *whoCan) printSARIF(out io.Writer, result Result) error {
+	action := w.prettyPrintAction()
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "kubectl-who-can",
+			InformationURI: "https://github.com/aquasecurity/kubectl-who-can",
+			Rules:          []sarifRule{{ID: sarifRuleID, ShortDescription: sarifMessage{Text: "Who can " + action}}},
+		}},
+		Results: []sarifResult{},
+	}
+	result.forEachBinding(func(bindingKind string, b Binding) {
+		scope := "in namespace " + b.Namespace
+		name := bindingKind + "/" + b.Namespace + "/" + b.Name
+		if b.Namespace == "" {
+			scope = clusterWide
+			name = bindingKind + "/" + b.Name
+		}
+		level := "note"
+		if b.Wildcard {
+			level = "warning"
+		}
+		for _, s := range b.Subjects {
+			run.Results = append(run.Results, sarifResult{
+				RuleID: sarifRuleID,
+				Level:  level,
+				Message: sarifMessage{Text: fmt.Sprintf("%s %s can %s %s through %s %s",
+					s.Kind, s.Name, action, scope, bindingKind, b.Name)},
+				Locations: []sarifLocation{{LogicalLocations: []sarifLogicalLocation{
+					{FullyQualifiedName: name, Kind: "resource"},
+				}}},
+				Properties: map[string]string{
+					"subject":     subjectName(s),
+					"role":        b.RoleRef.Kind + "/" + b.RoleRef.Name,
+					"bindingKind": bindingKind,
+				},
+			})
+		}
+	})
+
+	data, err := json.MarshalIndent(sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling SARIF log: %v", err)
+	}
+	_, err = fmt.Fprintln(out, string(data))
+	return err
+}
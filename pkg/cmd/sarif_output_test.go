@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"testing"
+)
+
+func TestWhoCan_printSARIF(t *testing.T) {
+	// given
+	roleBindings := []rbac.RoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "view-secrets", Namespace: "default"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "view-secrets"},
+			Subjects: []rbac.Subject{
+				{Name: "bar", Kind: "ServiceAccount", Namespace: "foo"},
+			},
+		},
+	}
+	clusterRoleBindings := []rbac.ClusterRoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "admins"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+			Subjects: []rbac.Subject{
+				{Name: "alice", Kind: "User"},
+			},
+		},
+	}
+
+	streams, _, out, _ := clioptions.NewTestIOStreams()
+	wc := whoCan{
+		verb:         "get",
+		resource:     "secrets",
+		outputFormat: outputSARIF,
+		wildcards: map[role]bool{
+			{name: "view-secrets", namespace: "default"}: false,
+			{name: "cluster-admin", isClusterRole: true}: true,
+		},
+		IOStreams: streams,
+	}
+
+	// when
+	err := wc.printResult(wc.newResult(roleBindings, clusterRoleBindings, []string{"list roles"}))
+
+	// then
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "version": "2.1.0",
+  "runs": [
+    {
+      "tool": {
+        "driver": {
+          "name": "kubectl-who-can",
+          "informationUri": "https://github.com/aquasecurity/kubectl-who-can",
+          "rules": [{"id": "who-can", "shortDescription": {"text": "Who can get secrets"}}]
+        }
+      },
+      "results": [
+        {
+          "ruleId": "who-can",
+          "level": "note",
+          "message": {"text": "ServiceAccount bar can get secrets in namespace default through RoleBinding view-secrets"},
+          "locations": [{"logicalLocations": [{"fullyQualifiedName": "RoleBinding/default/view-secrets", "kind": "resource"}]}],
+          "properties": {"subject": "serviceaccount/foo/bar", "role": "Role/view-secrets", "bindingKind": "RoleBinding"}
+        },
+        {
+          "ruleId": "who-can",
+          "level": "warning",
+          "message": {"text": "User alice can get secrets cluster-wide through ClusterRoleBinding admins"},
+          "locations": [{"logicalLocations": [{"fullyQualifiedName": "ClusterRoleBinding/admins", "kind": "resource"}]}],
+          "properties": {"subject": "user/alice", "role": "ClusterRole/cluster-admin", "bindingKind": "ClusterRoleBinding"}
+        }
+      ]
+    }
+  ]
+}`, out.String())
+}
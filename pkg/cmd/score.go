@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"text/tabwriter"
+
+	rbac "k8s.io/api/rbac/v1"
+)
+
+// Weights of the experimental blast radius score. The score of a binding is
+//
+//	(1 + scoreWildcardWeight * wildcard + write verbs) * scope
+//
+// where wildcard is 1 if any rule of the referenced role grants all verbs, resources or API groups,
+// write verbs is the number of distinct verbs among scoreWriteVerbs granted by the rules of the role,
+// of which a wildcard verb grants all, and scope is scoreClusterScopeWeight for ClusterRoleBindings
+// and 1 for RoleBindings. The score of a subject is the sum of the scores of the bindings which grant
+// it the queried action.
+const (
+	scoreWildcardWeight     = 2
+	scoreClusterScopeWeight = 3
+)
+
+// scoreWriteVerbs are the verbs which modify objects, and hence widen the blast radius of a subject.
+var scoreWriteVerbs = []string{"create", "update", "patch", "delete", "deletecollection"}
+
+// subjectScore is the blast radius score of a subject with the number of bindings it is derived from.
+type subjectScore struct {
+	subject  rbac.Subject
+	score    int
+	bindings int
+}
+
+// scores returns the blast radius scores of the subjects of the given bindings, in descending order of the score.
+func (w *whoCan) scores(roleBindings []rbac.RoleBinding, clusterRoleBindings []rbac.ClusterRoleBinding) []subjectScore {
+	scores := make(map[rbac.Subject]*subjectScore)
+	add := func(r subjectRow, scope int) {
+		s, ok := scores[r.subject]
+		if !ok {
+			s = &subjectScore{subject: r.subject}
+			scores[r.subject] = s
+		}
+		s.score += bindingScore(w.rules[newRoleFromRef(&r.roleRef, r.namespace)], scope)
+		s.bindings++
+	}
+	for _, r := range w.roleBindingRows(roleBindings) {
+		add(r, 1)
+	}
+	for _, r := range w.clusterRoleBindingRows(clusterRoleBindings) {
+		add(r, scoreClusterScopeWeight)
+	}
+
+	var result []subjectScore
+	for _, s := range scores {
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		if a.subject.Kind != b.subject.Kind {
+			return a.subject.Kind < b.subject.Kind
+		}
+		if a.subject.Namespace != b.subject.Namespace {
+			return a.subject.Namespace < b.subject.Namespace
+		}
+		return a.subject.Name < b.subject.Name
+	})
+	return result
+}
+
+// bindingScore returns the score of a binding to a role with the given rules in the given scope.
+func bindingScore(rules []rbac.PolicyRule, scope int) int {
+	score := 1 + writeVerbs(rules)
+	if hasWildcardRule(rules) {
+		score += scoreWildcardWeight
+	}
+	return score * scope
+}
+
+// writeVerbs returns the number of distinct write verbs granted by the given rules.
+func writeVerbs(rules []rbac.PolicyRule) int {
+	granted := make(map[string]struct{})
+	for _, rule := range rules {
+		for _, verb := range rule.Verbs {
+			if verb == rbac.VerbAll {
+				return len(scoreWriteVerbs)
+			}
+			granted[verb] = struct{}{}
+		}
+	}
+	count := 0
+	for _, verb := range scoreWriteVerbs {
+		if _, ok := granted[verb]; ok {
+			count++
+		}
+	}
+	return count
+}
+
+// outputScores prints the subjects who can perform the action with their blast radius scores.
+func (w *whoCan) outputScores(roleBindings []rbac.RoleBinding, clusterRoleBindings []rbac.ClusterRoleBinding) {
+	scores := w.scores(roleBindings, clusterRoleBindings)
+	if len(scores) == 0 {
+		_, _ = fmt.Fprintf(w.Out, "No subjects found with permissions to %s\n", w.prettyPrintAction())
+		return
+	}
+
+	wr := new(tabwriter.Writer)
+	wr.Init(w.Out, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintln(wr, "SUBJECT\tTYPE\tSA-NAMESPACE\tBINDINGS\tSCORE (EXPERIMENTAL)")
+	for _, s := range scores {
+		_, _ = fmt.Fprintf(wr, "%s\t%s\t%s\t%d\t%d\n", s.subject.Name, s.subject.Kind, s.subject.Namespace, s.bindings, s.score)
+	}
+	_ = wr.Flush()
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"testing"
+)
+
+func TestBindingScore(t *testing.T) {
+	data := []struct {
+		scenario string
+		rules    []rbac.PolicyRule
+		scope    int
+		expected int
+	}{
+		{
+			scenario: "A",
+			rules:    []rbac.PolicyRule{{APIGroups: []string{""}, Verbs: []string{"get", "list"}, Resources: []string{"pods"}}},
+			scope:    1,
+			expected: 1,
+		},
+		{
+			scenario: "B",
+			rules: []rbac.PolicyRule{
+				{APIGroups: []string{""}, Verbs: []string{"get", "create", "delete"}, Resources: []string{"pods"}},
+				{APIGroups: []string{""}, Verbs: []string{"create", "patch"}, Resources: []string{"services"}},
+			},
+			scope:    1,
+			expected: 4,
+		},
+		{
+			scenario: "C",
+			rules:    []rbac.PolicyRule{{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"*"}}},
+			scope:    1,
+			expected: 3,
+		},
+		{
+			scenario: "D",
+			rules:    []rbac.PolicyRule{{APIGroups: []string{"*"}, Verbs: []string{"*"}, Resources: []string{"*"}}},
+			scope:    1,
+			expected: 8,
+		},
+		{
+			scenario: "E",
+			rules:    []rbac.PolicyRule{{APIGroups: []string{"*"}, Verbs: []string{"*"}, Resources: []string{"*"}}},
+			scope:    scoreClusterScopeWeight,
+			expected: 24,
+		},
+		{
+			scenario: "F",
+			rules:    nil,
+			scope:    scoreClusterScopeWeight,
+			expected: 3,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			assert.Equal(t, tt.expected, bindingScore(tt.rules, tt.scope))
+		})
+	}
+}
+
+func TestWhoCan_outputScores(t *testing.T) {
+	// given
+	alice := rbac.Subject{Kind: rbac.UserKind, Name: "Alice"}
+	bob := rbac.Subject{Kind: rbac.UserKind, Name: "Bob"}
+	ops := rbac.Subject{Kind: rbac.GroupKind, Name: "ops"}
+
+	roleBindings := []rbac.RoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Alice-can-view-pods", Namespace: "foo"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "view-pods"},
+			Subjects:   []rbac.Subject{alice},
+		},
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Bob-can-edit-pods", Namespace: "foo"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "edit-pods"},
+			Subjects:   []rbac.Subject{bob},
+		},
+	}
+	clusterRoleBindings := []rbac.ClusterRoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Ops-are-admins"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "admin"},
+			Subjects:   []rbac.Subject{ops, alice},
+		},
+	}
+
+	streams, _, out, _ := clioptions.NewTestIOStreams()
+	wc := whoCan{
+		verb:     "get",
+		resource: "pods",
+		score:    true,
+		rules: map[role][]rbac.PolicyRule{
+			{name: "view-pods", namespace: "foo"}: {{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"pods"}}},
+			{name: "edit-pods", namespace: "foo"}: {{APIGroups: []string{""}, Verbs: []string{"get", "update"}, Resources: []string{"pods"}}},
+			{name: "admin", isClusterRole: true}:  {{APIGroups: []string{"*"}, Verbs: []string{"*"}, Resources: []string{"*"}}},
+		},
+		IOStreams: streams,
+	}
+
+	// when
+	wc.output(roleBindings, clusterRoleBindings)
+
+	// then
+	assert.Equal(t, `SUBJECT  TYPE   SA-NAMESPACE  BINDINGS  SCORE (EXPERIMENTAL)
+Alice    User                 2         25
+ops      Group                1         24
+Bob      User                 1         2
+`, out.String())
+}
+
+func TestWhoCan_outputScores_NoSubjects(t *testing.T) {
+	// given
+	streams, _, out, _ := clioptions.NewTestIOStreams()
+	wc := whoCan{verb: "get", resource: "pods", score: true, IOStreams: streams}
+
+	// when
+	wc.output(nil, nil)
+
+	// then
+	assert.Equal(t, "No subjects found with permissions to get pods\n", out.String())
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	authn "k8s.io/api/authentication/v1"
+	rbac "k8s.io/api/rbac/v1"
+	clientauthn "k8s.io/client-go/kubernetes/typed/authentication/v1"
+	"k8s.io/client-go/rest"
+)
+
+// Identity is the user, and the groups it belongs to, as which the API server authenticates the current user.
+type Identity struct {
+	User   string
+	Groups []string
+}
+
+// SelfIdentifier wraps the Identify method.
+//
+// Identify returns the Identity of the current user. The user impersonated with --as is returned as is.
+// Otherwise the user is taken from the basic authentication or the client certificate of the kubeconfig,
+// or the bearer token of the kubeconfig is reviewed by the API server.
+// An error is returned if the current user cannot be determined, such as with an authentication provider plugin.
+type SelfIdentifier interface {
+	Identify() (Identity, error)
+}
+
+type selfIdentifier struct {
+	client        clientauthn.TokenReviewInterface
+	config        *rest.Config
+	impersonation *impersonation
+}
+
+func NewSelfIdentifier(client clientauthn.TokenReviewInterface, config *rest.Config, impersonation *impersonation) SelfIdentifier {
+	return &selfIdentifier{
+		client:        client,
+		config:        config,
+		impersonation: impersonation,
+	}
+}
+
+func (si *selfIdentifier) Identify() (Identity, error) {
+	if si.impersonation.requested() {
+		var identity Identity
+		if si.impersonation.user != nil {
+			identity.User = *si.impersonation.user
+		}
+		if si.impersonation.groups != nil {
+			identity.Groups = *si.impersonation.groups
+		}
+		return identity, nil
+	}
+	if si.config.Username != "" {
+		return Identity{User: si.config.Username}, nil
+	}
+
+	certData, err := dataFromBytesOrFile(si.config.CertData, si.config.CertFile)
+	if err != nil {
+		return Identity{}, fmt.Errorf("reading client certificate: %v", err)
+	}
+	if len(certData) > 0 {
+		return identityFromCertificate(certData)
+	}
+
+	token, err := dataFromBytesOrFile([]byte(si.config.BearerToken), si.config.BearerTokenFile)
+	if err != nil {
+		return Identity{}, fmt.Errorf("reading bearer token: %v", err)
+	}
+	if len(token) > 0 {
+		return si.reviewToken(string(token))
+	}
+
+	return Identity{}, errors.New("the kubeconfig has no username, client certificate or bearer token")
+}
+
+// reviewToken returns the Identity authenticated by the given bearer token.
+func (si *selfIdentifier) reviewToken(token string) (Identity, error) {
+	review, err := si.client.Create(&authn.TokenReview{Spec: authn.TokenReviewSpec{Token: token}})
+	if err != nil {
+		return Identity{}, fmt.Errorf("reviewing bearer token: %v", err)
+	}
+	if !review.Status.Authenticated {
+		return Identity{}, fmt.Errorf("bearer token not authenticated: %s", review.Status.Error)
+	}
+	return Identity{User: review.Status.User.Username, Groups: review.Status.User.Groups}, nil
+}
+
+// identityFromCertificate returns the Identity of a client certificate, whose common name is the user
+// and whose organizations are the groups.
+func identityFromCertificate(data []byte) (Identity, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Identity{}, errors.New("client certificate is not PEM encoded")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return Identity{}, fmt.Errorf("parsing client certificate: %v", err)
+	}
+	return Identity{User: cert.Subject.CommonName, Groups: cert.Subject.Organization}, nil
+}
+
+// dataFromBytesOrFile returns the given data, or the content of the given file if the data is empty.
+func dataFromBytesOrFile(data []byte, file string) ([]byte, error) {
+	if len(data) > 0 || file == "" {
+		return data, nil
+	}
+	return ioutil.ReadFile(file)
+}
+
+// excludeSelf drops the subjects which refer to the current user from the bindings when --exclude-self is set,
+// as well as the bindings which are left without subjects. Groups are kept, because they stand for other members as well.
+// If the current user cannot be determined, a warning is printed and no subject is dropped.
+func (w *whoCan) excludeSelf(roleBindings []rbac.RoleBinding, clusterRoleBindings []rbac.ClusterRoleBinding) ([]rbac.RoleBinding, []rbac.ClusterRoleBinding) {
+	if !w.excludeSelfFlag {
+		return roleBindings, clusterRoleBindings
+	}
+	identity, err := w.selfIdentifier.Identify()
+	if err != nil {
+		_, _ = fmt.Fprintf(w.ErrOut, "Warning: Not excluding the current user, which cannot be determined: %v\n", err)
+		return roleBindings, clusterRoleBindings
+	}
+	self := normalizeSubject(rbac.Subject{Kind: rbac.UserKind, Name: identity.User}, "")
+
+	kept := func(subjects []rbac.Subject, bindingNamespace string) []rbac.Subject {
+		var kept []rbac.Subject
+		for _, s := range subjects {
+			if s.Kind != rbac.GroupKind && normalizeSubject(s, bindingNamespace) == self {
+				continue
+			}
+			kept = append(kept, s)
+		}
+		return kept
+	}
+
+	var keptRoleBindings []rbac.RoleBinding
+	for _, rb := range roleBindings {
+		if rb.Subjects = kept(rb.Subjects, rb.Namespace); len(rb.Subjects) > 0 {
+			keptRoleBindings = append(keptRoleBindings, rb)
+		}
+	}
+	var keptClusterRoleBindings []rbac.ClusterRoleBinding
+	for _, crb := range clusterRoleBindings {
+		if crb.Subjects = kept(crb.Subjects, ""); len(crb.Subjects) > 0 {
+			keptClusterRoleBindings = append(keptClusterRoleBindings, crb)
+		}
+	}
+	return keptRoleBindings, keptClusterRoleBindings
+}
@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	authn "k8s.io/api/authentication/v1"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	clientTesting "k8s.io/client-go/testing"
+	"math/big"
+	"testing"
+	"time"
+)
+
+type selfIdentifierMock struct {
+	mock.Mock
+}
+
+func (m *selfIdentifierMock) Identify() (Identity, error) {
+	args := m.Called()
+	return args.Get(0).(Identity), args.Error(1)
+}
+
+func TestSelfIdentifier_Identify(t *testing.T) {
+	jane := "jane"
+	admins := []string{"admins"}
+	noUser := ""
+	noGroups := []string{}
+
+	data := []struct {
+		scenario      string
+		config        *rest.Config
+		impersonation *impersonation
+
+		expectedIdentity Identity
+		expectedErr      error
+	}{
+		{
+			scenario:         "Should return impersonated user",
+			config:           &rest.Config{Username: "admin"},
+			impersonation:    &impersonation{user: &jane, groups: &admins},
+			expectedIdentity: Identity{User: "jane", Groups: []string{"admins"}},
+		},
+		{
+			scenario:         "Should return user of basic authentication",
+			config:           &rest.Config{Username: "admin"},
+			impersonation:    &impersonation{user: &noUser, groups: &noGroups},
+			expectedIdentity: Identity{User: "admin"},
+		},
+		{
+			scenario:         "Should return user of client certificate",
+			config:           &rest.Config{TLSClientConfig: rest.TLSClientConfig{CertData: newClientCertificate(t, "alice", "devs")}},
+			expectedIdentity: Identity{User: "alice", Groups: []string{"devs"}},
+		},
+		{
+			scenario:         "Should return user of reviewed bearer token",
+			config:           &rest.Config{BearerToken: "valid"},
+			expectedIdentity: Identity{User: "system:serviceaccount:ci:deployer", Groups: []string{"system:serviceaccounts"}},
+		},
+		{
+			scenario:    "Should return error when bearer token is not authenticated",
+			config:      &rest.Config{BearerToken: "expired"},
+			expectedErr: errors.New("bearer token not authenticated: token expired"),
+		},
+		{
+			scenario:    "Should return error when kubeconfig has no credentials",
+			config:      &rest.Config{},
+			expectedErr: errors.New("the kubeconfig has no username, client certificate or bearer token"),
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			client := fake.NewSimpleClientset()
+			client.Fake.PrependReactor("create", "tokenreviews", func(action clientTesting.Action) (bool, runtime.Object, error) {
+				review := action.(clientTesting.CreateAction).GetObject().(*authn.TokenReview)
+				if review.Spec.Token == "valid" {
+					review.Status = authn.TokenReviewStatus{Authenticated: true, User: authn.UserInfo{
+						Username: "system:serviceaccount:ci:deployer", Groups: []string{"system:serviceaccounts"}}}
+				} else {
+					review.Status = authn.TokenReviewStatus{Error: "token expired"}
+				}
+				return true, review, nil
+			})
+			si := NewSelfIdentifier(client.AuthenticationV1().TokenReviews(), tt.config, tt.impersonation)
+
+			// when
+			identity, err := si.Identify()
+
+			// then
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedIdentity, identity)
+		})
+	}
+}
+
+func newClientCertificate(t *testing.T, user, group string) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: user, Organization: []string{group}},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestWhoCan_excludeSelf(t *testing.T) {
+	// given
+	roleBindings := []rbac.RoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "deployers", Namespace: "ci"},
+			Subjects: []rbac.Subject{
+				{Kind: rbac.ServiceAccountKind, Name: "deployer"},
+				{Kind: rbac.UserKind, Name: "Alice"},
+			},
+		},
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "deployer-by-username", Namespace: "foo"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "system:serviceaccount:ci:deployer"}},
+		},
+	}
+	clusterRoleBindings := []rbac.ClusterRoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "service-accounts"},
+			Subjects:   []rbac.Subject{{Kind: rbac.GroupKind, Name: "system:serviceaccounts"}},
+		},
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "other-deployer"},
+			Subjects:   []rbac.Subject{{Kind: rbac.ServiceAccountKind, Name: "deployer", Namespace: "prod"}},
+		},
+	}
+
+	selfIdentifier := new(selfIdentifierMock)
+	selfIdentifier.On("Identify").Return(Identity{User: "system:serviceaccount:ci:deployer", Groups: []string{"system:serviceaccounts"}}, nil)
+	wc := whoCan{excludeSelfFlag: true, selfIdentifier: selfIdentifier}
+
+	// when
+	rbs, crbs := wc.excludeSelf(roleBindings, clusterRoleBindings)
+
+	// then
+	assert.Equal(t, []rbac.RoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "deployers", Namespace: "ci"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "Alice"}},
+		},
+	}, rbs)
+	assert.Equal(t, clusterRoleBindings, crbs)
+	selfIdentifier.AssertExpectations(t)
+}
+
+func TestWhoCan_excludeSelf_Unknown(t *testing.T) {
+	// given
+	roleBindings := []rbac.RoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Alice-can-view-pods", Namespace: "foo"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "Alice"}},
+		},
+	}
+	selfIdentifier := new(selfIdentifierMock)
+	selfIdentifier.On("Identify").Return(Identity{}, errors.New("the kubeconfig has no username, client certificate or bearer token"))
+	streams, _, _, errOut := clioptions.NewTestIOStreams()
+	wc := whoCan{excludeSelfFlag: true, selfIdentifier: selfIdentifier, IOStreams: streams}
+
+	// when
+	rbs, crbs := wc.excludeSelf(roleBindings, nil)
+
+	// then
+	assert.Equal(t, roleBindings, rbs)
+	assert.Nil(t, crbs)
+	assert.Equal(t, "Warning: Not excluding the current user, which cannot be determined: "+
+		"the kubeconfig has no username, client certificate or bearer token\n", errOut.String())
+}
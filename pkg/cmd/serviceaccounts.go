@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	core "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	serviceAccountsUsage = `serviceaccounts --selector SELECTOR`
+	serviceAccountsLong  = `Shows what the service accounts matching a label selector can do.
+
+For each service account the rules granted by RoleBindings and ClusterRoleBindings are listed, including the rules
+granted to the groups which every service account implicitly belongs to.`
+	serviceAccountsExample = `  # List what the service accounts labeled tier=frontend in the current namespace can do
+  kubectl who-can serviceaccounts --selector tier=frontend
+
+  # List what the service accounts labeled tier=frontend in all namespaces can do
+  kubectl who-can serviceaccounts -l tier=frontend --all-namespaces
+
+  # Also list the bindings whose roles are missing or cannot be read, noting why their rules are unavailable
+  kubectl who-can serviceaccounts -l tier=frontend --show-unresolved`
+
+	// defaultServiceAccount is the service account which pods run as unless they specify another one.
+	defaultServiceAccount = "default"
+
+	// maxConcurrentSubjectLookups limits the number of subjects whose grants are looked up in parallel.
+	maxConcurrentSubjectLookups = 10
+)
+
+type serviceAccountsWhoCan struct {
+	selector       string
+	namespace      string
+	allNamespaces  bool
+	showUnresolved bool
+
+	configFlags  *clioptions.ConfigFlags
+	clientConfig clientcmd.ClientConfig
+	client       kubernetes.Interface
+
+	clioptions.IOStreams
+}
+
+// serviceAccountGrants are the grants of a single service account.
+type serviceAccountGrants struct {
+	serviceAccount rbac.Subject
+	grants         []grant
+}
+
+func NewCmdServiceAccounts(client kubernetes.Interface, configFlags *clioptions.ConfigFlags, streams clioptions.IOStreams) *cobra.Command {
+	o := &serviceAccountsWhoCan{
+		configFlags:  configFlags,
+		clientConfig: configFlags.ToRawKubeConfigLoader(),
+		client:       client,
+		IOStreams:    streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:          serviceAccountsUsage,
+		Aliases:      []string{"sa"},
+		Short:        "Show what the service accounts matching a label selector can do",
+		Long:         serviceAccountsLong,
+		Example:      serviceAccountsExample,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if o.selector == "" {
+				return errors.New("--selector is required")
+			}
+			var err error
+			o.namespace, err = resolveNamespace(o.configFlags, o.clientConfig, o.allNamespaces)
+			if err != nil {
+				return err
+			}
+			return o.run()
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.selector, "selector", "l", "", "Label selector of the service accounts, such as tier=frontend")
+	cmd.Flags().BoolVarP(&o.allNamespaces, "all-namespaces", "A", false,
+		"If true, check the service accounts in all namespaces.")
+	cmd.Flags().BoolVar(&o.showUnresolved, "show-unresolved", false,
+		"If true, also list the bindings which refer to roles that are missing or that cannot be read, with the reason why their rules are unavailable.")
+
+	return cmd
+}
+
+func (o *serviceAccountsWhoCan) run() error {
+	sal, err := o.client.CoreV1().ServiceAccounts(o.namespace).List(meta.ListOptions{LabelSelector: o.selector})
+	if err != nil {
+		return fmt.Errorf("listing service accounts: %v", err)
+	}
+	if len(sal.Items) == 0 {
+		_, _ = fmt.Fprintf(o.Out, "No service accounts found matching %s\n", o.selector)
+		return nil
+	}
+
+	var serviceAccounts []rbac.Subject
+	for _, sa := range sal.Items {
+		serviceAccounts = append(serviceAccounts, rbac.Subject{Kind: rbac.ServiceAccountKind, Name: sa.Name, Namespace: sa.Namespace})
+	}
+
+	snapshot, err := loadRBACSnapshot(o.client.RbacV1(), o.namespace)
+	if err != nil {
+		return err
+	}
+	snapshot.showUnresolved = o.showUnresolved
+
+	results, err := lookupServiceAccountGrants(snapshot, serviceAccounts)
+	if err != nil {
+		return err
+	}
+	printServiceAccountGrants(o.Out, results)
+	return nil
+}
+
+// runDefaultServiceAccount prints what the default service account of the namespace given by the --default-sa flag
+// can do. RoleBindings of all namespaces are considered, because any of them may bind the service account.
+func (w *whoCan) runDefaultServiceAccount(args []string) error {
+	if len(args) > 0 {
+		return errors.New("--default-sa cannot be used with VERB and TYPE")
+	}
+
+	snapshot, err := loadRBACSnapshot(w.clientRBAC, core.NamespaceAll)
+	if err != nil {
+		return err
+	}
+	snapshot.showUnresolved = w.showUnresolved
+
+	sa := rbac.Subject{Kind: rbac.ServiceAccountKind, Name: defaultServiceAccount, Namespace: w.defaultSA}
+	results, err := lookupServiceAccountGrants(snapshot, []rbac.Subject{sa})
+	if err != nil {
+		return err
+	}
+	printServiceAccountGrants(w.Out, results)
+	return nil
+}
+
+// lookupServiceAccountGrants looks up the grants of the given service accounts in parallel and returns them
+// ordered by namespace and name. Duplicate service accounts are looked up once. If any lookup fails, the first error
+// in that order is returned.
+func lookupServiceAccountGrants(snapshot *rbacSnapshot, serviceAccounts []rbac.Subject) ([]serviceAccountGrants, error) {
+	seen := make(map[rbac.Subject]struct{})
+	var distinct []rbac.Subject
+	for _, sa := range serviceAccounts {
+		if _, ok := seen[sa]; ok {
+			continue
+		}
+		seen[sa] = struct{}{}
+		distinct = append(distinct, sa)
+	}
+	sort.Slice(distinct, func(i, j int) bool {
+		if distinct[i].Namespace != distinct[j].Namespace {
+			return distinct[i].Namespace < distinct[j].Namespace
+		}
+		return distinct[i].Name < distinct[j].Name
+	})
+
+	results := make([]serviceAccountGrants, len(distinct))
+	errs := make([]error, len(distinct))
+	sem := make(chan struct{}, maxConcurrentSubjectLookups)
+	var wg sync.WaitGroup
+	for i, sa := range distinct {
+		wg.Add(1)
+		go func(i int, sa rbac.Subject) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			grants, err := snapshot.grantsFor(sa)
+			results[i], errs[i] = serviceAccountGrants{serviceAccount: sa, grants: grants}, err
+		}(i, sa)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// printServiceAccountGrants prints a table of the grants of each service account,
+// followed by the service accounts which are granted nothing.
+func printServiceAccountGrants(out io.Writer, results []serviceAccountGrants) {
+	wr := new(tabwriter.Writer)
+	wr.Init(out, 0, 8, 2, ' ', 0)
+
+	var none []string
+	header := false
+	for _, r := range results {
+		name := r.serviceAccount.Namespace + ":" + r.serviceAccount.Name
+		if len(r.grants) == 0 {
+			none = append(none, name)
+			continue
+		}
+		if !header {
+			_, _ = fmt.Fprintln(wr, "SERVICEACCOUNT\tBINDING\tNAMESPACE\tROLE\tVERBS\tRESOURCES\tRESOURCE-NAMES")
+			header = true
+		}
+		for _, g := range r.grants {
+			if g.unresolved != "" {
+				_, _ = fmt.Fprintf(wr, "%s\t%s/%s\t%s\t%s/%s\t(rules unavailable: %s)\t\t\n", name, g.bindingKind, g.binding, g.namespace,
+					g.roleRef.Kind, g.roleRef.Name, g.unresolved)
+				continue
+			}
+			_, _ = fmt.Fprintf(wr, "%s\t%s/%s\t%s\t%s/%s\t%s\t%s\t%s\n", name, g.bindingKind, g.binding, g.namespace,
+				g.roleRef.Kind, g.roleRef.Name, strings.Join(g.rule.Verbs, ","), ruleResources(g.rule), strings.Join(g.rule.ResourceNames, ","))
+		}
+	}
+	_ = wr.Flush()
+
+	if len(none) > 0 {
+		if header {
+			_, _ = fmt.Fprintln(out)
+		}
+		_, _ = fmt.Fprintf(out, "No permissions found for service account(s): %s\n", strings.Join(none, ", "))
+	}
+}
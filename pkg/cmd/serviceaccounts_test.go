@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	core "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"testing"
+)
+
+func TestServiceAccountsWhoCan_run(t *testing.T) {
+	newServiceAccount := func(namespace, name, tier string) *core.ServiceAccount {
+		return &core.ServiceAccount{ObjectMeta: meta.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"tier": tier}}}
+	}
+
+	// given
+	client := fake.NewSimpleClientset(
+		newServiceAccount("foo", "web", "frontend"),
+		newServiceAccount("foo", "api", "frontend"),
+		newServiceAccount("foo", "db", "backend"),
+		&rbac.Role{
+			ObjectMeta: meta.ObjectMeta{Name: "view-config", Namespace: "foo"},
+			Rules: []rbac.PolicyRule{
+				{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"configmaps"}, ResourceNames: []string{"web-config"}},
+			},
+		},
+		&rbac.RoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "web-can-view-config", Namespace: "foo"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "view-config"},
+			Subjects: []rbac.Subject{
+				{Kind: rbac.ServiceAccountKind, Name: "web", Namespace: "foo"},
+				{Kind: rbac.UserKind, Name: "system:serviceaccount:foo:web"},
+				{Kind: rbac.ServiceAccountKind, Name: "db", Namespace: "foo"},
+			},
+		},
+	)
+	streams, _, out, _ := clioptions.NewTestIOStreams()
+	o := &serviceAccountsWhoCan{
+		selector:  "tier=frontend",
+		namespace: "foo",
+		client:    client,
+		IOStreams: streams,
+	}
+
+	// when
+	err := o.run()
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, `SERVICEACCOUNT  BINDING                          NAMESPACE  ROLE              VERBS  RESOURCES   RESOURCE-NAMES
+foo:web         RoleBinding/web-can-view-config  foo        Role/view-config  get    configmaps  web-config
+
+No permissions found for service account(s): foo:api
+`, out.String())
+}
+
+func TestServiceAccountsWhoCan_run_ShowUnresolved(t *testing.T) {
+	// given
+	sa := rbac.Subject{Kind: rbac.ServiceAccountKind, Name: "web", Namespace: "foo"}
+	client := fake.NewSimpleClientset(
+		&core.ServiceAccount{ObjectMeta: meta.ObjectMeta{Name: "web", Namespace: "foo", Labels: map[string]string{"tier": "frontend"}}},
+		&rbac.ClusterRole{ObjectMeta: meta.ObjectMeta{Name: "secret-reader"}},
+		&rbac.RoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "web-can-read-secrets", Namespace: "foo"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "secret-reader"},
+			Subjects:   []rbac.Subject{sa},
+		},
+		&rbac.RoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "web-can-do-nothing", Namespace: "foo"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "missing"},
+			Subjects:   []rbac.Subject{sa},
+		},
+	)
+	client.PrependReactor("list", "clusterroles", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(rbac.Resource("clusterroles"), "", errors.New("access denied"))
+	})
+	client.PrependReactor("get", "clusterroles", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(rbac.Resource("clusterroles"), "secret-reader", errors.New("access denied"))
+	})
+
+	t.Run("Should list bindings whose roles cannot be resolved", func(t *testing.T) {
+		// given
+		streams, _, out, _ := clioptions.NewTestIOStreams()
+		o := &serviceAccountsWhoCan{selector: "tier=frontend", namespace: "foo", showUnresolved: true, client: client, IOStreams: streams}
+
+		// when
+		err := o.run()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, `SERVICEACCOUNT  BINDING                           NAMESPACE  ROLE                       VERBS                                                              RESOURCES  RESOURCE-NAMES
+foo:web         RoleBinding/web-can-read-secrets  foo        ClusterRole/secret-reader  (rules unavailable: not allowed to get ClusterRole secret-reader)             
+foo:web         RoleBinding/web-can-do-nothing    foo        Role/missing               (rules unavailable: Role missing not found in namespace foo)                  
+`, out.String())
+	})
+
+	t.Run("Should return error when role cannot be fetched without showing unresolved bindings", func(t *testing.T) {
+		// given
+		streams, _, out, _ := clioptions.NewTestIOStreams()
+		o := &serviceAccountsWhoCan{selector: "tier=frontend", namespace: "foo", client: client, IOStreams: streams}
+
+		// when
+		err := o.run()
+
+		// then
+		assert.EqualError(t, err, "getting ClusterRole secret-reader: clusterroles.rbac.authorization.k8s.io \"secret-reader\" is forbidden: access denied")
+		assert.Empty(t, out.String())
+	})
+}
+
+func TestWhoCan_runDefaultServiceAccount(t *testing.T) {
+	viewConfig := []rbac.PolicyRule{{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"configmaps"}}}
+	listPods := []rbac.PolicyRule{{APIGroups: []string{""}, Verbs: []string{"list"}, Resources: []string{"pods"}}}
+
+	data := []struct {
+		scenario  string
+		defaultSA string
+		args      []string
+		output    string
+		err       error
+	}{
+		{
+			scenario:  "Should print direct and group-based grants of default service account",
+			defaultSA: "foo",
+			output: `SERVICEACCOUNT  BINDING                               NAMESPACE  ROLE                   VERBS  RESOURCES   RESOURCE-NAMES
+foo:default     RoleBinding/default-can-view-config   foo        Role/view-config       get    configmaps  
+foo:default     ClusterRoleBinding/foo-can-list-pods             ClusterRole/list-pods  list   pods        
+`,
+		},
+		{
+			scenario:  "Should print message when default service account is granted nothing",
+			defaultSA: "bar",
+			output:    "No permissions found for service account(s): bar:default\n",
+		},
+		{
+			scenario:  "Should return error when used with VERB and TYPE",
+			defaultSA: "foo",
+			args:      []string{"get", "pods"},
+			err:       errors.New("--default-sa cannot be used with VERB and TYPE"),
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			client := fake.NewSimpleClientset(
+				&rbac.Role{ObjectMeta: meta.ObjectMeta{Name: "view-config", Namespace: "foo"}, Rules: viewConfig},
+				&rbac.ClusterRole{ObjectMeta: meta.ObjectMeta{Name: "list-pods"}, Rules: listPods},
+				&rbac.RoleBinding{
+					ObjectMeta: meta.ObjectMeta{Name: "default-can-view-config", Namespace: "foo"},
+					RoleRef:    rbac.RoleRef{Kind: "Role", Name: "view-config"},
+					Subjects:   []rbac.Subject{{Kind: rbac.ServiceAccountKind, Name: "default", Namespace: "foo"}},
+				},
+				&rbac.ClusterRoleBinding{
+					ObjectMeta: meta.ObjectMeta{Name: "foo-can-list-pods"},
+					RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "list-pods"},
+					Subjects:   []rbac.Subject{{Kind: rbac.GroupKind, Name: "system:serviceaccounts:foo"}},
+				},
+			)
+			streams, _, out, _ := clioptions.NewTestIOStreams()
+			wc := whoCan{defaultSA: tt.defaultSA, clientRBAC: client.RbacV1(), IOStreams: streams}
+
+			// when
+			err := wc.runDefaultServiceAccount(tt.args)
+
+			// then
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.output, out.String())
+		})
+	}
+}
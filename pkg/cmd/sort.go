@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	rbac "k8s.io/api/rbac/v1"
+	"sort"
+)
+
+// sortKey is a column by which the output rows can be ordered.
+type sortKey string
+
+const (
+	sortBySubject   sortKey = "subject"
+	sortByKind      sortKey = "kind"
+	sortByNamespace sortKey = "namespace"
+	sortByBinding   sortKey = "binding"
+)
+
+// sortKeys lists the supported values of the --sort-by flag.
+var sortKeys = []sortKey{sortBySubject, sortByKind, sortByNamespace, sortByBinding}
+
+func isSupportedSortKey(key sortKey) bool {
+	for _, k := range sortKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// subjectRow represents a single subject granted access through a RoleBinding or a ClusterRoleBinding.
+type subjectRow struct {
+	binding string
+	// bindingKind is set only when rows of RoleBindings and ClusterRoleBindings are shown together.
+	bindingKind string
+	namespace   string
+	roleRef     rbac.RoleRef
+	subject     rbac.Subject
+}
+
+// sortRows orders the rows by the given key. Rows with identical keys are ordered by the remaining
+// columns so that the result does not depend on the order in which the bindings were listed.
+func sortRows(rows []subjectRow, key sortKey) {
+	order := sortOrder(key)
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rowLess(rows[i], rows[j], order)
+	})
+}
+
+// sortBindings orders the subjects of each binding by the given key, and then the bindings by their first subject,
+// so that the structured output follows the order of the table rows as closely as the bindings allow.
+func sortBindings(bindings []Binding, key sortKey) {
+	order := sortOrder(key)
+	for _, b := range bindings {
+		sort.SliceStable(b.Subjects, func(i, j int) bool {
+			return rowLess(bindingRow(b, b.Subjects[i]), bindingRow(b, b.Subjects[j]), order)
+		})
+	}
+	sort.SliceStable(bindings, func(i, j int) bool {
+		return rowLess(firstRow(bindings[i]), firstRow(bindings[j]), order)
+	})
+}
+
+// sortOrder returns the sort keys with the given key first.
+func sortOrder(key sortKey) []sortKey {
+	order := append([]sortKey{}, sortKeys...)
+	for i, k := range order {
+		if k == key {
+			return append([]sortKey{k}, append(order[:i:i], order[i+1:]...)...)
+		}
+	}
+	return order
+}
+
+func rowLess(a, b subjectRow, order []sortKey) bool {
+	for _, k := range order {
+		x, y := a.sortValues(k), b.sortValues(k)
+		for n := range x {
+			if x[n] != y[n] {
+				return x[n] < y[n]
+			}
+		}
+	}
+	return false
+}
+
+// bindingRow returns the row of the given subject of the binding.
+func bindingRow(b Binding, s Subject) subjectRow {
+	return subjectRow{
+		binding:   b.Name,
+		namespace: b.Namespace,
+		subject:   rbac.Subject{Kind: s.Kind, Name: s.Name, Namespace: s.Namespace},
+	}
+}
+
+// firstRow returns the row of the first subject of the binding, or a row without a subject if it has none.
+func firstRow(b Binding) subjectRow {
+	if len(b.Subjects) == 0 {
+		return subjectRow{binding: b.Name, namespace: b.Namespace}
+	}
+	return bindingRow(b, b.Subjects[0])
+}
+
+// sortValues returns the values compared when ordering rows by the given key.
+func (r subjectRow) sortValues(key sortKey) []string {
+	switch key {
+	case sortBySubject:
+		return []string{r.subject.Name}
+	case sortByKind:
+		return []string{r.subject.Kind}
+	case sortByNamespace:
+		return []string{r.namespace, r.subject.Namespace}
+	default:
+		return []string{r.binding}
+	}
+}
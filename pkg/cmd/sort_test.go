@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	rbac "k8s.io/api/rbac/v1"
+	"testing"
+)
+
+func TestSortRows(t *testing.T) {
+	var (
+		aliceUser   = subjectRow{binding: "b2", namespace: "foo", subject: rbac.Subject{Kind: "User", Name: "alice"}}
+		aliceGroup  = subjectRow{binding: "b1", namespace: "foo", subject: rbac.Subject{Kind: "Group", Name: "alice"}}
+		aliceSAFoo  = subjectRow{binding: "b1", namespace: "bar", subject: rbac.Subject{Kind: "ServiceAccount", Name: "alice", Namespace: "foo"}}
+		aliceSABar  = subjectRow{binding: "b1", namespace: "bar", subject: rbac.Subject{Kind: "ServiceAccount", Name: "alice", Namespace: "bar"}}
+		bobUserB1   = subjectRow{binding: "b1", namespace: "foo", subject: rbac.Subject{Kind: "User", Name: "bob"}}
+		bobUserB2   = subjectRow{binding: "b2", namespace: "foo", subject: rbac.Subject{Kind: "User", Name: "bob"}}
+		permutation = [][]subjectRow{
+			{aliceUser, aliceGroup, aliceSAFoo, aliceSABar, bobUserB1, bobUserB2},
+			{bobUserB2, bobUserB1, aliceSABar, aliceSAFoo, aliceGroup, aliceUser},
+			{aliceSAFoo, bobUserB2, aliceUser, bobUserB1, aliceSABar, aliceGroup},
+		}
+	)
+
+	data := []struct {
+		scenario string
+		key      sortKey
+		expected []subjectRow
+	}{
+		{
+			scenario: "Should break ties on subject name by kind, namespace and binding",
+			key:      sortBySubject,
+			expected: []subjectRow{aliceGroup, aliceSABar, aliceSAFoo, aliceUser, bobUserB1, bobUserB2},
+		},
+		{
+			scenario: "Should break ties on kind by subject name, namespace and binding",
+			key:      sortByKind,
+			expected: []subjectRow{aliceGroup, aliceSABar, aliceSAFoo, aliceUser, bobUserB1, bobUserB2},
+		},
+		{
+			scenario: "Should break ties on namespace by subject name, kind and binding",
+			key:      sortByNamespace,
+			expected: []subjectRow{aliceSABar, aliceSAFoo, aliceGroup, aliceUser, bobUserB1, bobUserB2},
+		},
+		{
+			scenario: "Should break ties on binding by subject name, kind and namespace",
+			key:      sortByBinding,
+			expected: []subjectRow{aliceGroup, aliceSABar, aliceSAFoo, bobUserB1, aliceUser, bobUserB2},
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			for _, p := range permutation {
+				// given
+				rows := append([]subjectRow{}, p...)
+
+				// when
+				sortRows(rows, tt.key)
+
+				// then
+				assert.Equal(t, tt.expected, rows)
+			}
+		})
+	}
+}
+
+func TestSortBindings(t *testing.T) {
+	data := []struct {
+		scenario string
+		key      sortKey
+		expected []Binding
+	}{
+		{
+			scenario: "Should order subjects and then bindings by subject",
+			key:      sortBySubject,
+			expected: []Binding{
+				{Name: "b0", Namespace: "foo", Subjects: []Subject{}},
+				{Name: "b2", Namespace: "bar", Subjects: []Subject{{Kind: "User", Name: "alice"}, {Kind: "User", Name: "carol"}}},
+				{Name: "b1", Namespace: "foo", Subjects: []Subject{{Kind: "User", Name: "bob"}, {Kind: "User", Name: "dave"}}},
+			},
+		},
+		{
+			scenario: "Should order bindings by binding",
+			key:      sortByBinding,
+			expected: []Binding{
+				{Name: "b0", Namespace: "foo", Subjects: []Subject{}},
+				{Name: "b1", Namespace: "foo", Subjects: []Subject{{Kind: "User", Name: "bob"}, {Kind: "User", Name: "dave"}}},
+				{Name: "b2", Namespace: "bar", Subjects: []Subject{{Kind: "User", Name: "alice"}, {Kind: "User", Name: "carol"}}},
+			},
+		},
+		{
+			scenario: "Should order bindings by namespace",
+			key:      sortByNamespace,
+			expected: []Binding{
+				{Name: "b2", Namespace: "bar", Subjects: []Subject{{Kind: "User", Name: "alice"}, {Kind: "User", Name: "carol"}}},
+				{Name: "b0", Namespace: "foo", Subjects: []Subject{}},
+				{Name: "b1", Namespace: "foo", Subjects: []Subject{{Kind: "User", Name: "bob"}, {Kind: "User", Name: "dave"}}},
+			},
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			bindings := []Binding{
+				{Name: "b1", Namespace: "foo", Subjects: []Subject{{Kind: "User", Name: "dave"}, {Kind: "User", Name: "bob"}}},
+				{Name: "b0", Namespace: "foo", Subjects: []Subject{}},
+				{Name: "b2", Namespace: "bar", Subjects: []Subject{{Kind: "User", Name: "carol"}, {Kind: "User", Name: "alice"}}},
+			}
+
+			// when
+			sortBindings(bindings, tt.key)
+
+			// then
+			assert.Equal(t, tt.expected, bindings)
+		})
+	}
+}
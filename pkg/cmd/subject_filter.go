@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	rbac "k8s.io/api/rbac/v1"
+)
+
+// parseSubjectKind returns the canonical name of the kind given by --subject-kind, which may be lower-case or short.
+func parseSubjectKind(kind string) (string, error) {
+	canonical, ok := subjectKinds[strings.ToLower(kind)]
+	if !ok {
+		return "", fmt.Errorf("unsupported subject kind \"%s\", expected one of [User Group ServiceAccount]", kind)
+	}
+	return canonical, nil
+}
+
+// filterSubjects keeps the subjects of the bindings which match the --subject and --subject-kind flags,
+// as well as the bindings which are left with any subjects.
+func (w *whoCan) filterSubjects(roleBindings []rbac.RoleBinding, clusterRoleBindings []rbac.ClusterRoleBinding) ([]rbac.RoleBinding, []rbac.ClusterRoleBinding) {
+	if w.subject == "" && w.subjectKind == "" {
+		return roleBindings, clusterRoleBindings
+	}
+
+	kept := func(subjects []rbac.Subject, bindingNamespace string) []rbac.Subject {
+		var kept []rbac.Subject
+		for _, s := range subjects {
+			if w.matchesSubject(s, bindingNamespace) {
+				kept = append(kept, s)
+			}
+		}
+		return kept
+	}
+
+	var keptRoleBindings []rbac.RoleBinding
+	for _, rb := range roleBindings {
+		if rb.Subjects = kept(rb.Subjects, rb.Namespace); len(rb.Subjects) > 0 {
+			keptRoleBindings = append(keptRoleBindings, rb)
+		}
+	}
+	var keptClusterRoleBindings []rbac.ClusterRoleBinding
+	for _, crb := range clusterRoleBindings {
+		if crb.Subjects = kept(crb.Subjects, ""); len(crb.Subjects) > 0 {
+			keptClusterRoleBindings = append(keptClusterRoleBindings, crb)
+		}
+	}
+	return keptRoleBindings, keptClusterRoleBindings
+}
+
+// matchesSubject returns `true` if the given subject of a binding in the given namespace matches the --subject
+// and --subject-kind flags. The name of a ServiceAccount may be qualified with its namespace, e.g. `ci:deployer`,
+// in which case only the ServiceAccount of that namespace matches.
+func (w *whoCan) matchesSubject(s rbac.Subject, bindingNamespace string) bool {
+	if w.subjectKind != "" && s.Kind != w.subjectKind {
+		return false
+	}
+	if w.subject == "" || s.Name == w.subject {
+		return true
+	}
+	if s.Kind != rbac.ServiceAccountKind {
+		return false
+	}
+	tokens := strings.SplitN(w.subject, ":", 2)
+	return len(tokens) == 2 && tokens[1] == s.Name && tokens[0] == serviceAccountNamespace(s, bindingNamespace)
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	rbac "k8s.io/api/rbac/v1"
+	"testing"
+)
+
+func TestWhoCan_matchesSubject(t *testing.T) {
+	data := []struct {
+		scenario         string
+		subjectFlag      string
+		subjectKindFlag  string
+		subject          rbac.Subject
+		bindingNamespace string
+		matches          bool
+	}{
+		{
+			scenario:    "Should match subject by name",
+			subjectFlag: "alice",
+			subject:     rbac.Subject{Kind: rbac.UserKind, Name: "alice"},
+			matches:     true,
+		},
+		{
+			scenario:        "Should not match subject of other kind",
+			subjectFlag:     "alice",
+			subjectKindFlag: rbac.GroupKind,
+			subject:         rbac.Subject{Kind: rbac.UserKind, Name: "alice"},
+		},
+		{
+			scenario:        "Should match all subjects of kind",
+			subjectKindFlag: rbac.ServiceAccountKind,
+			subject:         rbac.Subject{Kind: rbac.ServiceAccountKind, Name: "deployer", Namespace: "ci"},
+			matches:         true,
+		},
+		{
+			scenario:    "Should match ServiceAccount by namespace and name",
+			subjectFlag: "ci:deployer",
+			subject:     rbac.Subject{Kind: rbac.ServiceAccountKind, Name: "deployer", Namespace: "ci"},
+			matches:     true,
+		},
+		{
+			scenario:         "Should match ServiceAccount in namespace of RoleBinding",
+			subjectFlag:      "ci:deployer",
+			subject:          rbac.Subject{Kind: rbac.ServiceAccountKind, Name: "deployer"},
+			bindingNamespace: "ci",
+			matches:          true,
+		},
+		{
+			scenario:    "Should not match ServiceAccount of other namespace",
+			subjectFlag: "ci:deployer",
+			subject:     rbac.Subject{Kind: rbac.ServiceAccountKind, Name: "deployer", Namespace: "prod"},
+		},
+		{
+			scenario:    "Should match User with colons in name",
+			subjectFlag: "system:kube-scheduler",
+			subject:     rbac.Subject{Kind: rbac.UserKind, Name: "system:kube-scheduler"},
+			matches:     true,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			wc := whoCan{subject: tt.subjectFlag, subjectKind: tt.subjectKindFlag}
+
+			assert.Equal(t, tt.matches, wc.matchesSubject(tt.subject, tt.bindingNamespace))
+		})
+	}
+}
+
+func TestParseSubjectKind(t *testing.T) {
+	kind, err := parseSubjectKind("sa")
+	assert.NoError(t, err)
+	assert.Equal(t, rbac.ServiceAccountKind, kind)
+
+	_, err = parseSubjectKind("robot")
+	assert.Equal(t, errors.New("unsupported subject kind \"robot\", expected one of [User Group ServiceAccount]"), err)
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultMatrixPageSize is the number of namespaces printed per page of the matrix of a subject.
+const defaultMatrixPageSize = 25
+
+// subjectMatrix is the grid of the verbs granted to a subject, with a row per namespace and a column per resource.
+type subjectMatrix struct {
+	// namespaces are the namespaces of the page, which starts at the offset in all the total namespaces.
+	namespaces []string
+	offset     int
+	total      int
+	// resources are the resources named by the rules granted to the subject, qualified with their API group.
+	resources []string
+	// cells holds the verbs granted on each resource in each namespace.
+	cells map[string]map[string]map[string]struct{}
+}
+
+// runSubject prints the matrix of the verbs granted to the --subject in the namespace, or in all namespaces.
+// The RBAC objects are listed once and matched in memory, and the printed rows are split into pages of --page-size
+// namespaces. Only the namespaces of the --page are matched, hence the work per page does not grow with the number
+// of namespaces.
+func (o *matrixWhoCan) runSubject() error {
+	subject, err := parseSubject(o.subject)
+	if err != nil {
+		return err
+	}
+	if o.page < 1 || o.pageSize < 1 {
+		return fmt.Errorf("--page and --page-size must be positive, got %d and %d", o.page, o.pageSize)
+	}
+
+	namespaces := []string{o.namespace}
+	if o.allNamespaces {
+		nsList, err := o.client.CoreV1().Namespaces().List(meta.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("listing namespaces: %v", err)
+		}
+		namespaces = nil
+		for _, ns := range nsList.Items {
+			namespaces = append(namespaces, ns.Name)
+		}
+		sort.Strings(namespaces)
+	}
+
+	snapshot, err := loadRBACSnapshot(o.client.RbacV1(), o.namespace)
+	if err != nil {
+		return err
+	}
+	grants, err := snapshot.grantsFor(subject)
+	if err != nil {
+		return err
+	}
+
+	return printSubjectMatrix(o.Out, o.subject, subjectMatrixPage(grants, namespaces, o.page, o.pageSize), o.page, o.pageSize)
+}
+
+// subjectMatrixPage builds the matrix of the given grants in the namespaces of the given page. The columns are the
+// resources granted in any namespace, so that they are the same on every page. A page out of range has no namespaces.
+func subjectMatrixPage(grants []grant, namespaces []string, page, pageSize int) subjectMatrix {
+	first := (page - 1) * pageSize
+	if first > len(namespaces) {
+		first = len(namespaces)
+	}
+	last := first + pageSize
+	if last > len(namespaces) {
+		last = len(namespaces)
+	}
+	m := subjectMatrixOf(grants, namespaces[first:last])
+	m.offset, m.total = first, len(namespaces)
+	return m
+}
+
+// subjectMatrixOf builds the matrix of the given grants in the given namespaces. The grants of ClusterRoleBindings
+// apply in every namespace. Verbs which a rule grants on named objects only are prefixed with a tilde, unless
+// another rule grants them on all objects of the resource. Rules for non-resource URLs are left out.
+func subjectMatrixOf(grants []grant, namespaces []string) subjectMatrix {
+	m := subjectMatrix{namespaces: namespaces, cells: make(map[string]map[string]map[string]struct{})}
+	for _, ns := range namespaces {
+		m.cells[ns] = make(map[string]map[string]struct{})
+	}
+
+	resources := make(map[string]struct{})
+	for _, g := range grants {
+		for _, group := range g.rule.APIGroups {
+			for _, resource := range g.rule.Resources {
+				name := qualifiedName(resource, group)
+				resources[name] = struct{}{}
+				for _, ns := range namespaces {
+					if g.namespace != "" && g.namespace != ns {
+						continue
+					}
+					if m.cells[ns][name] == nil {
+						m.cells[ns][name] = make(map[string]struct{})
+					}
+					for _, verb := range g.rule.Verbs {
+						if len(g.rule.ResourceNames) > 0 {
+							verb = "~" + verb
+						}
+						m.cells[ns][name][verb] = struct{}{}
+					}
+				}
+			}
+		}
+	}
+
+	for name := range resources {
+		m.resources = append(m.resources, name)
+	}
+	sort.Strings(m.resources)
+	return m
+}
+
+// cell returns the verbs granted on the resource in the namespace, separated by commas, or a dash if there are none.
+func (m subjectMatrix) cell(namespace, resource string) string {
+	verbs := m.cells[namespace][resource]
+	if _, ok := verbs[rbac.VerbAll]; ok {
+		return rbac.VerbAll
+	}
+	var names []string
+	for verb := range verbs {
+		if _, ok := verbs[strings.TrimPrefix(verb, "~")]; strings.HasPrefix(verb, "~") && ok {
+			continue
+		}
+		names = append(names, verb)
+	}
+	if len(names) == 0 {
+		return "-"
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+// printSubjectMatrix prints the matrix of the given page, followed by a hint at the next page if there is one.
+func printSubjectMatrix(out io.Writer, subject string, m subjectMatrix, page, pageSize int) error {
+	if len(m.resources) == 0 {
+		_, _ = fmt.Fprintf(out, "No verbs on resources are granted to %s\n", subject)
+		return nil
+	}
+
+	pages := (m.total + pageSize - 1) / pageSize
+	if page > pages {
+		return fmt.Errorf("--page %d is out of range, the matrix has %d page(s) of %d namespace(s)", page, pages, pageSize)
+	}
+
+	_, _ = fmt.Fprintf(out, "Verbs granted to %s in namespaces %d-%d of %d:\n\n", subject, m.offset+1, m.offset+len(m.namespaces), m.total)
+
+	wr := new(tabwriter.Writer)
+	wr.Init(out, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintf(wr, "NAMESPACE\t%s\n", strings.Join(m.resources, "\t"))
+	for _, ns := range m.namespaces {
+		cells := make([]string, len(m.resources))
+		for i, resource := range m.resources {
+			cells[i] = m.cell(ns, resource)
+		}
+		_, _ = fmt.Fprintf(wr, "%s\t%s\n", ns, strings.Join(cells, "\t"))
+	}
+	_ = wr.Flush()
+
+	if page < pages {
+		_, _ = fmt.Fprintf(out, "\nUse --page %d to show the next %d namespace(s).\n", page+1, pageSize)
+	}
+	return nil
+}
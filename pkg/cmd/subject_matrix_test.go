@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	core "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+)
+
+func TestMatrixWhoCan_runSubject(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&core.Namespace{ObjectMeta: meta.ObjectMeta{Name: "dev"}},
+		&core.Namespace{ObjectMeta: meta.ObjectMeta{Name: "prod"}},
+		&core.Namespace{ObjectMeta: meta.ObjectMeta{Name: "staging"}},
+		&rbac.Role{ObjectMeta: meta.ObjectMeta{Name: "developer", Namespace: "dev"},
+			Rules: []rbac.PolicyRule{
+				{APIGroups: []string{""}, Verbs: []string{"*"}, Resources: []string{"pods"}},
+				{APIGroups: []string{"apps"}, Verbs: []string{"get", "update"}, Resources: []string{"deployments"}},
+			}},
+		&rbac.Role{ObjectMeta: meta.ObjectMeta{Name: "tls-reader", Namespace: "prod"},
+			Rules: []rbac.PolicyRule{{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"secrets"}, ResourceNames: []string{"tls"}}}},
+		&rbac.ClusterRole{ObjectMeta: meta.ObjectMeta{Name: "pod-reader"},
+			Rules: []rbac.PolicyRule{{APIGroups: []string{""}, Verbs: []string{"get", "list"}, Resources: []string{"pods"}}}},
+		&rbac.RoleBinding{ObjectMeta: meta.ObjectMeta{Name: "jane-develops", Namespace: "dev"},
+			RoleRef: rbac.RoleRef{Kind: "Role", Name: "developer"}, Subjects: []rbac.Subject{{Kind: rbac.UserKind, Name: "jane"}}},
+		&rbac.RoleBinding{ObjectMeta: meta.ObjectMeta{Name: "jane-reads-tls", Namespace: "prod"},
+			RoleRef: rbac.RoleRef{Kind: "Role", Name: "tls-reader"}, Subjects: []rbac.Subject{{Kind: rbac.UserKind, Name: "jane"}}},
+		&rbac.ClusterRoleBinding{ObjectMeta: meta.ObjectMeta{Name: "everyone-reads-pods"},
+			RoleRef: rbac.RoleRef{Kind: "ClusterRole", Name: "pod-reader"}, Subjects: []rbac.Subject{{Kind: rbac.GroupKind, Name: "developers"}, {Kind: rbac.UserKind, Name: "jane"}}},
+	)
+
+	data := []struct {
+		scenario      string
+		subject       string
+		namespace     string
+		allNamespaces bool
+		page          int
+		pageSize      int
+
+		output string
+		err    error
+	}{
+		{
+			scenario:      "Should print verbs granted to subject with mixed access per namespace",
+			subject:       "User/jane",
+			allNamespaces: true,
+			page:          1,
+			pageSize:      defaultMatrixPageSize,
+			output: `Verbs granted to User/jane in namespaces 1-3 of 3:
+
+NAMESPACE  deployments.apps  pods      secrets
+dev        get,update        *         -
+prod       -                 get,list  ~get
+staging    -                 get,list  -
+`,
+		},
+		{
+			scenario:  "Should print verbs granted to subject in single namespace",
+			subject:   "User/jane",
+			namespace: "prod",
+			page:      1,
+			pageSize:  defaultMatrixPageSize,
+			output: `Verbs granted to User/jane in namespaces 1-1 of 1:
+
+NAMESPACE  pods      secrets
+prod       get,list  ~get
+`,
+		},
+		{
+			scenario:      "Should print first page of namespaces",
+			subject:       "User/jane",
+			allNamespaces: true,
+			page:          1,
+			pageSize:      2,
+			output: `Verbs granted to User/jane in namespaces 1-2 of 3:
+
+NAMESPACE  deployments.apps  pods      secrets
+dev        get,update        *         -
+prod       -                 get,list  ~get
+
+Use --page 2 to show the next 2 namespace(s).
+`,
+		},
+		{
+			scenario:      "Should print last page of namespaces",
+			subject:       "User/jane",
+			allNamespaces: true,
+			page:          2,
+			pageSize:      2,
+			output: `Verbs granted to User/jane in namespaces 3-3 of 3:
+
+NAMESPACE  deployments.apps  pods      secrets
+staging    -                 get,list  -
+`,
+		},
+		{
+			scenario:      "Should return error when page is out of range",
+			subject:       "User/jane",
+			allNamespaces: true,
+			page:          3,
+			pageSize:      2,
+			err:           errors.New("--page 3 is out of range, the matrix has 2 page(s) of 2 namespace(s)"),
+		},
+		{
+			scenario:      "Should print that no verbs are granted to subject",
+			subject:       "User/bob",
+			allNamespaces: true,
+			page:          1,
+			pageSize:      defaultMatrixPageSize,
+			output:        "No verbs on resources are granted to User/bob\n",
+		},
+		{
+			scenario: "Should return error when page size is not positive",
+			subject:  "User/jane",
+			page:     1,
+			err:      errors.New("--page and --page-size must be positive, got 1 and 0"),
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			streams, _, out, _ := clioptions.NewTestIOStreams()
+			o := &matrixWhoCan{
+				subject:       tt.subject,
+				namespace:     tt.namespace,
+				allNamespaces: tt.allNamespaces,
+				page:          tt.page,
+				pageSize:      tt.pageSize,
+				client:        client,
+				IOStreams:     streams,
+			}
+
+			// when
+			err := o.runSubject()
+
+			// then
+			assert.Equal(t, tt.err, err)
+			if tt.err == nil {
+				assert.Equal(t, tt.output, out.String())
+			}
+		})
+	}
+}
+
+func TestSubjectMatrixPage(t *testing.T) {
+	// given
+	grants := []grant{
+		{bindingKind: "ClusterRoleBinding", binding: "pod-readers",
+			rule: rbac.PolicyRule{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"pods"}}},
+		{bindingKind: "RoleBinding", binding: "secret-readers", namespace: "ns-0",
+			rule: rbac.PolicyRule{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"secrets"}}},
+	}
+	var namespaces []string
+	for i := 0; i < 1000; i++ {
+		namespaces = append(namespaces, fmt.Sprintf("ns-%d", i))
+	}
+
+	data := []struct {
+		scenario string
+		page     int
+
+		expectedNamespaces []string
+		expectedOffset     int
+	}{
+		{
+			scenario:           "Should match only namespaces of first page",
+			page:               1,
+			expectedNamespaces: []string{"ns-0", "ns-1"},
+			expectedOffset:     0,
+		},
+		{
+			scenario:           "Should match only namespaces of last page",
+			page:               500,
+			expectedNamespaces: []string{"ns-998", "ns-999"},
+			expectedOffset:     998,
+		},
+		{
+			scenario:           "Should match no namespaces of page out of range",
+			page:               501,
+			expectedNamespaces: []string{},
+			expectedOffset:     1000,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// when
+			m := subjectMatrixPage(grants, namespaces, tt.page, 2)
+
+			// then
+			assert.Equal(t, tt.expectedNamespaces, m.namespaces)
+			assert.Len(t, m.cells, len(tt.expectedNamespaces))
+			assert.Equal(t, tt.expectedOffset, m.offset)
+			assert.Equal(t, 1000, m.total)
+			assert.Equal(t, []string{"pods", "secrets"}, m.resources)
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/golang/glog"
+	rbac "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientrbac "k8s.io/client-go/kubernetes/typed/rbac/v1"
+)
+
+const (
+	serviceAccountUserPrefix  = "system:serviceaccount:"
+	serviceAccountsGroup      = "system:serviceaccounts"
+	serviceAccountGroupPrefix = "system:serviceaccounts:"
+	authenticatedGroup        = "system:authenticated"
+	unauthenticatedGroup      = "system:unauthenticated"
+	anonymousUser             = "system:anonymous"
+)
+
+// grant is a PolicyRule granted to a subject through a RoleBinding or a ClusterRoleBinding.
+type grant struct {
+	bindingKind string
+	binding     string
+	// namespace is the namespace of the RoleBinding, in which the rule applies.
+	// It is empty for ClusterRoleBindings, which apply in all namespaces.
+	namespace string
+	roleRef   rbac.RoleRef
+	rule      rbac.PolicyRule
+	// unresolved is the reason why the rules of the referenced role are unavailable, in which case the grant has no
+	// rule. Such grants are only returned if the snapshot shows unresolved bindings.
+	unresolved string
+}
+
+// rbacSnapshot holds the RBAC objects listed once so that the grants of many subjects can be looked up without
+// listing them again. This is the reverse of the who-can query: for a given subject it finds what the subject can do.
+type rbacSnapshot struct {
+	// roles are indexed by namespace and name.
+	roles               map[string]map[string]rbac.Role
+	clusterRoles        *clusterRoleCache
+	roleBindings        []rbac.RoleBinding
+	clusterRoleBindings []rbac.ClusterRoleBinding
+
+	// showUnresolved keeps the bindings which refer to roles that are missing or that the user is not allowed to get,
+	// rather than dropping them or failing. They are returned as grants with the reason why their rules are unavailable.
+	showUnresolved bool
+}
+
+// loadRBACSnapshot lists the Roles and RoleBindings in the given namespace and all ClusterRoles and ClusterRoleBindings.
+// If the user is not allowed to list ClusterRoles, they are fetched one by one when referenced by a binding.
+func loadRBACSnapshot(client clientrbac.RbacV1Interface, namespace string) (*rbacSnapshot, error) {
+	s := &rbacSnapshot{
+		roles: make(map[string]map[string]rbac.Role),
+	}
+
+	crl, err := client.ClusterRoles().List(meta.ListOptions{})
+	if err != nil && !apierrors.IsForbidden(err) {
+		return nil, fmt.Errorf("listing ClusterRoles: %v", err)
+	}
+	if err != nil {
+		glog.V(3).Infof("Getting ClusterRoles one by one as they cannot be listed: %v", err)
+		crl = &rbac.ClusterRoleList{}
+	}
+	s.clusterRoles = newClusterRoleCache(client.ClusterRoles(), crl.Items)
+
+	rl, err := client.Roles(namespace).List(meta.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing Roles: %v", err)
+	}
+	for _, r := range rl.Items {
+		if _, ok := s.roles[r.Namespace]; !ok {
+			s.roles[r.Namespace] = make(map[string]rbac.Role)
+		}
+		s.roles[r.Namespace][r.Name] = r
+	}
+
+	rbl, err := client.RoleBindings(namespace).List(meta.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing RoleBindings: %v", err)
+	}
+	s.roleBindings = rbl.Items
+
+	crbl, err := client.ClusterRoleBindings().List(meta.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing ClusterRoleBindings: %v", err)
+	}
+	s.clusterRoleBindings = crbl.Items
+
+	return s, nil
+}
+
+// grantsFor returns the rules granted to the given subject, either directly or through the groups it implicitly
+// belongs to. Identical grants are returned once. Bindings which refer to missing roles grant nothing, unless the
+// snapshot shows unresolved bindings. It is safe for concurrent use.
+func (s *rbacSnapshot) grantsFor(subject rbac.Subject) ([]grant, error) {
+	var grants []grant
+	seen := make(map[string]struct{})
+	add := func(g grant) {
+		key := g.key()
+		if _, ok := seen[key]; ok {
+			return
+		}
+		seen[key] = struct{}{}
+		grants = append(grants, g)
+	}
+
+	for _, rb := range s.roleBindings {
+		if !bindsSubject(rb.Subjects, subject) {
+			continue
+		}
+		rules, unresolved, err := s.resolveRules(rb.RoleRef, rb.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		if unresolved != "" {
+			if s.showUnresolved {
+				add(grant{bindingKind: "RoleBinding", binding: rb.Name, namespace: rb.Namespace, roleRef: rb.RoleRef, unresolved: unresolved})
+			}
+			continue
+		}
+		for _, rule := range rules {
+			add(grant{bindingKind: "RoleBinding", binding: rb.Name, namespace: rb.Namespace, roleRef: rb.RoleRef, rule: rule})
+		}
+	}
+	for _, crb := range s.clusterRoleBindings {
+		if !bindsSubject(crb.Subjects, subject) {
+			continue
+		}
+		rules, unresolved, err := s.resolveRules(crb.RoleRef, "")
+		if err != nil {
+			return nil, err
+		}
+		if unresolved != "" {
+			if s.showUnresolved {
+				add(grant{bindingKind: "ClusterRoleBinding", binding: crb.Name, roleRef: crb.RoleRef, unresolved: unresolved})
+			}
+			continue
+		}
+		for _, rule := range rules {
+			add(grant{bindingKind: "ClusterRoleBinding", binding: crb.Name, roleRef: crb.RoleRef, rule: rule})
+		}
+	}
+	return grants, nil
+}
+
+// rulesOf returns the rules of the role referenced by a binding in the given namespace.
+func (s *rbacSnapshot) rulesOf(roleRef rbac.RoleRef, namespace string) ([]rbac.PolicyRule, error) {
+	if roleRef.Kind == "ClusterRole" {
+		return s.clusterRoles.rulesOf(roleRef.Name)
+	}
+	return s.roles[namespace][roleRef.Name].Rules, nil
+}
+
+// resolveRules returns the rules of the role referenced by a binding in the given namespace, or the reason why they
+// are unavailable if the role is missing. A ClusterRole which the user is not allowed to get is unresolved too if the
+// snapshot shows unresolved bindings, and fails the lookup otherwise.
+func (s *rbacSnapshot) resolveRules(roleRef rbac.RoleRef, namespace string) ([]rbac.PolicyRule, string, error) {
+	if roleRef.Kind == "ClusterRole" {
+		rules, found, err := s.clusterRoles.lookup(roleRef.Name)
+		switch {
+		case apierrors.IsForbidden(err) && s.showUnresolved:
+			return nil, fmt.Sprintf("not allowed to get ClusterRole %s", roleRef.Name), nil
+		case err != nil:
+			return nil, "", fmt.Errorf("getting ClusterRole %s: %v", roleRef.Name, err)
+		case !found:
+			return nil, fmt.Sprintf("ClusterRole %s not found", roleRef.Name), nil
+		}
+		return rules, "", nil
+	}
+	r, found := s.roles[namespace][roleRef.Name]
+	if !found {
+		return nil, fmt.Sprintf("Role %s not found in namespace %s", roleRef.Name, namespace), nil
+	}
+	return r.Rules, "", nil
+}
+
+// bindsSubject returns `true` if any of the subjects of a binding is the given subject
+// or a group which the given subject implicitly belongs to.
+func bindsSubject(subjects []rbac.Subject, subject rbac.Subject) bool {
+	for _, s := range subjects {
+		if subjectMatches(s, subject) {
+			return true
+		}
+	}
+	return false
+}
+
+// subjectMatches returns `true` if the bound subject of a binding refers to the given subject.
+// Any subject but the anonymous user and the group of unauthenticated users is also matched by the group of
+// authenticated users. A ServiceAccount is also matched by its username, and by the groups of all service accounts
+// and of the service accounts in its namespace.
+func subjectMatches(bound, subject rbac.Subject) bool {
+	if bound.Kind == subject.Kind && bound.Name == subject.Name && bound.Namespace == subject.Namespace {
+		return true
+	}
+	if bound.Kind == rbac.GroupKind && bound.Name == authenticatedGroup {
+		return !(subject.Kind == rbac.UserKind && subject.Name == anonymousUser) &&
+			!(subject.Kind == rbac.GroupKind && subject.Name == unauthenticatedGroup)
+	}
+	if subject.Kind != rbac.ServiceAccountKind {
+		return false
+	}
+	switch bound.Kind {
+	case rbac.UserKind:
+		return bound.Name == serviceAccountUserPrefix+subject.Namespace+":"+subject.Name
+	case rbac.GroupKind:
+		return bound.Name == serviceAccountsGroup ||
+			bound.Name == serviceAccountGroupPrefix+subject.Namespace
+	}
+	return false
+}
+
+// subjectKinds maps the supported kinds of subjects, including lower-case and short names, to their canonical names.
+var subjectKinds = map[string]string{
+	"user": rbac.UserKind, "group": rbac.GroupKind, "serviceaccount": rbac.ServiceAccountKind, "sa": rbac.ServiceAccountKind,
+}
+
+// parseSubject parses a subject given as KIND/NAME, where the NAME of a ServiceAccount is given as NAMESPACE:NAME.
+func parseSubject(ref string) (rbac.Subject, error) {
+	tokens := strings.SplitN(ref, "/", 2)
+	if len(tokens) != 2 || tokens[1] == "" {
+		return rbac.Subject{}, fmt.Errorf("invalid subject \"%s\", expected KIND/NAME", ref)
+	}
+	kind, ok := subjectKinds[strings.ToLower(tokens[0])]
+	if !ok {
+		return rbac.Subject{}, fmt.Errorf("unsupported subject kind \"%s\", expected one of [User Group ServiceAccount]", tokens[0])
+	}
+	subject := rbac.Subject{Kind: kind, Name: tokens[1]}
+	if kind == rbac.ServiceAccountKind {
+		nameTokens := strings.SplitN(tokens[1], ":", 2)
+		if len(nameTokens) != 2 || nameTokens[0] == "" || nameTokens[1] == "" {
+			return rbac.Subject{}, fmt.Errorf("invalid service account \"%s\", expected NAMESPACE:NAME", tokens[1])
+		}
+		subject.Namespace, subject.Name = nameTokens[0], nameTokens[1]
+	}
+	return subject, nil
+}
+
+// key identifies the grant for deduplication.
+func (g grant) key() string {
+	return strings.Join([]string{g.bindingKind, g.namespace, g.binding, g.roleRef.Kind, g.roleRef.Name,
+		strings.Join(g.rule.Verbs, ","), ruleResources(g.rule), strings.Join(g.rule.ResourceNames, ",")}, "|")
+}
+
+// ruleResources formats the resources of a rule qualified with their API groups,
+// or its NonResourceURLs if the rule applies to non-resource URLs.
+func ruleResources(rule rbac.PolicyRule) string {
+	if len(rule.NonResourceURLs) > 0 {
+		return strings.Join(rule.NonResourceURLs, ",")
+	}
+	var resources []string
+	for _, group := range rule.APIGroups {
+		for _, resource := range rule.Resources {
+			resources = append(resources, qualifiedName(resource, group))
+		}
+	}
+	sort.Strings(resources)
+	return strings.Join(resources, ",")
+}
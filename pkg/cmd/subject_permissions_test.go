@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+)
+
+func TestSubjectMatches(t *testing.T) {
+	sa := rbac.Subject{Kind: rbac.ServiceAccountKind, Name: "frontend", Namespace: "foo"}
+
+	data := []struct {
+		scenario string
+		bound    rbac.Subject
+		subject  rbac.Subject
+		matches  bool
+	}{
+		{
+			scenario: "Should match identical service account",
+			bound:    sa, subject: sa,
+			matches: true,
+		},
+		{
+			scenario: "Should not match service account in other namespace",
+			bound:    rbac.Subject{Kind: rbac.ServiceAccountKind, Name: "frontend", Namespace: "bar"}, subject: sa,
+			matches: false,
+		},
+		{
+			scenario: "Should match service account by username",
+			bound:    rbac.Subject{Kind: rbac.UserKind, Name: "system:serviceaccount:foo:frontend"}, subject: sa,
+			matches: true,
+		},
+		{
+			scenario: "Should match service account by group of its namespace",
+			bound:    rbac.Subject{Kind: rbac.GroupKind, Name: "system:serviceaccounts:foo"}, subject: sa,
+			matches: true,
+		},
+		{
+			scenario: "Should not match service account by group of other namespace",
+			bound:    rbac.Subject{Kind: rbac.GroupKind, Name: "system:serviceaccounts:bar"}, subject: sa,
+			matches: false,
+		},
+		{
+			scenario: "Should match service account by group of all service accounts",
+			bound:    rbac.Subject{Kind: rbac.GroupKind, Name: "system:serviceaccounts"}, subject: sa,
+			matches: true,
+		},
+		{
+			scenario: "Should match service account by group of authenticated users",
+			bound:    rbac.Subject{Kind: rbac.GroupKind, Name: "system:authenticated"}, subject: sa,
+			matches: true,
+		},
+		{
+			scenario: "Should match user by group of authenticated users",
+			bound:    rbac.Subject{Kind: rbac.GroupKind, Name: "system:authenticated"},
+			subject:  rbac.Subject{Kind: rbac.UserKind, Name: "alice"},
+			matches:  true,
+		},
+		{
+			scenario: "Should match group by group of authenticated users",
+			bound:    rbac.Subject{Kind: rbac.GroupKind, Name: "system:authenticated"},
+			subject:  rbac.Subject{Kind: rbac.GroupKind, Name: "auditors"},
+			matches:  true,
+		},
+		{
+			scenario: "Should not match anonymous user by group of authenticated users",
+			bound:    rbac.Subject{Kind: rbac.GroupKind, Name: "system:authenticated"},
+			subject:  rbac.Subject{Kind: rbac.UserKind, Name: "system:anonymous"},
+			matches:  false,
+		},
+		{
+			scenario: "Should not match group of unauthenticated users by group of authenticated users",
+			bound:    rbac.Subject{Kind: rbac.GroupKind, Name: "system:authenticated"},
+			subject:  rbac.Subject{Kind: rbac.GroupKind, Name: "system:unauthenticated"},
+			matches:  false,
+		},
+		{
+			scenario: "Should not match user by group of all service accounts",
+			bound:    rbac.Subject{Kind: rbac.GroupKind, Name: "system:serviceaccounts"},
+			subject:  rbac.Subject{Kind: rbac.UserKind, Name: "alice"},
+			matches:  false,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			assert.Equal(t, tt.matches, subjectMatches(tt.bound, tt.subject))
+		})
+	}
+}
+
+func TestRBACSnapshot_grantsFor(t *testing.T) {
+	// given
+	viewPods := rbac.PolicyRule{APIGroups: []string{""}, Verbs: []string{"get", "list"}, Resources: []string{"pods"}}
+	healthz := rbac.PolicyRule{Verbs: []string{"get"}, NonResourceURLs: []string{"/healthz"}}
+	sa := rbac.Subject{Kind: rbac.ServiceAccountKind, Name: "frontend", Namespace: "foo"}
+
+	client := fake.NewSimpleClientset(
+		&rbac.Role{ObjectMeta: meta.ObjectMeta{Name: "view-pods", Namespace: "foo"}, Rules: []rbac.PolicyRule{viewPods}},
+		&rbac.ClusterRole{ObjectMeta: meta.ObjectMeta{Name: "healthz"}, Rules: []rbac.PolicyRule{healthz}},
+		&rbac.RoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "frontend-can-view-pods", Namespace: "foo"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "view-pods"},
+			Subjects:   []rbac.Subject{sa, {Kind: rbac.UserKind, Name: "system:serviceaccount:foo:frontend"}},
+		},
+		&rbac.RoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "frontend-can-do-nothing", Namespace: "foo"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "missing"},
+			Subjects:   []rbac.Subject{sa},
+		},
+		&rbac.ClusterRoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "service-accounts-can-check-health"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "healthz"},
+			Subjects:   []rbac.Subject{{Kind: rbac.GroupKind, Name: "system:serviceaccounts"}},
+		},
+		&rbac.ClusterRoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: "alice-can-check-health"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "healthz"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "alice"}},
+		},
+	)
+
+	snapshot, err := loadRBACSnapshot(client.RbacV1(), "foo")
+	require.NoError(t, err)
+
+	// when
+	grants, err := snapshot.grantsFor(sa)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []grant{
+		{bindingKind: "RoleBinding", binding: "frontend-can-view-pods", namespace: "foo", roleRef: rbac.RoleRef{Kind: "Role", Name: "view-pods"}, rule: viewPods},
+		{bindingKind: "ClusterRoleBinding", binding: "service-accounts-can-check-health", roleRef: rbac.RoleRef{Kind: "ClusterRole", Name: "healthz"}, rule: healthz},
+	}, grants)
+}
+
+func TestRuleResources(t *testing.T) {
+	data := []struct {
+		scenario string
+		rule     rbac.PolicyRule
+		expected string
+	}{
+		{
+			scenario: "Should qualify resources with API groups",
+			rule:     rbac.PolicyRule{APIGroups: []string{"", "apps"}, Resources: []string{"pods", "deployments"}},
+			expected: "deployments,deployments.apps,pods,pods.apps",
+		},
+		{
+			scenario: "Should list non-resource URLs",
+			rule:     rbac.PolicyRule{NonResourceURLs: []string{"/healthz", "/version"}},
+			expected: "/healthz,/version",
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ruleResources(tt.rule))
+		})
+	}
+}
+
+func TestParseSubject(t *testing.T) {
+	data := []struct {
+		ref     string
+		subject rbac.Subject
+		err     error
+	}{
+		{ref: "User/jane", subject: rbac.Subject{Kind: rbac.UserKind, Name: "jane"}},
+		{ref: "group/system:masters", subject: rbac.Subject{Kind: rbac.GroupKind, Name: "system:masters"}},
+		{ref: "ServiceAccount/ci:deployer", subject: rbac.Subject{Kind: rbac.ServiceAccountKind, Name: "deployer", Namespace: "ci"}},
+		{ref: "sa/ci:deployer", subject: rbac.Subject{Kind: rbac.ServiceAccountKind, Name: "deployer", Namespace: "ci"}},
+		{ref: "jane", err: errors.New("invalid subject \"jane\", expected KIND/NAME")},
+		{ref: "User/", err: errors.New("invalid subject \"User/\", expected KIND/NAME")},
+		{ref: "Robot/r2d2", err: errors.New("unsupported subject kind \"Robot\", expected one of [User Group ServiceAccount]")},
+		{ref: "ServiceAccount/deployer", err: errors.New("invalid service account \"deployer\", expected NAMESPACE:NAME")},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.ref, func(t *testing.T) {
+			subject, err := parseSubject(tt.ref)
+
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.subject, subject)
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	rbac "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// subjectsMatrixRow is a row of the grid of the verbs granted on a resource, which holds the verbs granted to a subject
+// in a scope, which is the namespace of RoleBindings or cluster-wide for ClusterRoleBindings.
+type subjectsMatrixRow struct {
+	subject  rbac.Subject
+	scope    string
+	coverage []verbCoverage
+}
+
+// runSubjects prints the grid of the verbs supported by the resource granted to each subject of the bindings in the
+// namespace, or in all namespaces. The RBAC objects are listed once and the rules granted to each subject are matched
+// in memory, the same way as by the coverage report.
+func (o *matrixWhoCan) runSubjects() error {
+	groupResource, verbs, err := o.resourceResolver.SupportedVerbs(o.resource)
+	if err != nil {
+		return fmt.Errorf("resolving resource: %v", err)
+	}
+
+	snapshot, err := loadRBACSnapshot(o.client.RbacV1(), o.namespace)
+	if err != nil {
+		return err
+	}
+	var rows []subjectsMatrixRow
+	for _, subject := range boundSubjects(snapshot) {
+		grants, err := snapshot.grantsFor(subject)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, subjectsMatrixRowsOf(subject, grants, groupResource, verbs)...)
+	}
+
+	printSubjectsMatrix(o.Out, groupResource, verbs, rows)
+	return nil
+}
+
+// boundSubjects returns the distinct subjects of the RoleBindings and ClusterRoleBindings of the snapshot,
+// ordered by kind, namespace and name.
+func boundSubjects(snapshot *rbacSnapshot) []rbac.Subject {
+	seen := make(map[rbac.Subject]bool)
+	var subjects []rbac.Subject
+	add := func(bound []rbac.Subject) {
+		for _, s := range bound {
+			s = rbac.Subject{Kind: s.Kind, Name: s.Name, Namespace: s.Namespace}
+			if s.Kind != rbac.ServiceAccountKind {
+				s.Namespace = ""
+			}
+			if !seen[s] {
+				seen[s] = true
+				subjects = append(subjects, s)
+			}
+		}
+	}
+	for _, rb := range snapshot.roleBindings {
+		add(rb.Subjects)
+	}
+	for _, crb := range snapshot.clusterRoleBindings {
+		add(crb.Subjects)
+	}
+	sort.Slice(subjects, func(i, j int) bool {
+		a, b := subjects[i], subjects[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return subjects
+}
+
+// subjectsMatrixRowsOf returns a row per scope in which the given grants of the subject grant any of the verbs
+// on the resource. The cluster-wide row comes first, followed by the rows of the namespaces in order.
+func subjectsMatrixRowsOf(subject rbac.Subject, grants []grant, groupResource schema.GroupResource, verbs []string) []subjectsMatrixRow {
+	byScope := make(map[string][]grant)
+	for _, g := range grants {
+		byScope[g.namespace] = append(byScope[g.namespace], g)
+	}
+	var scopes []string
+	for scope := range byScope {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+
+	var rows []subjectsMatrixRow
+	for _, scope := range scopes {
+		coverage := coverageOfVerbs(byScope[scope], groupResource, verbs)
+		granted := false
+		for _, c := range coverage {
+			granted = granted || len(c.bindings) > 0 || len(c.partialBindings) > 0
+		}
+		if !granted {
+			continue
+		}
+		if scope == "" {
+			scope = clusterWide
+		}
+		rows = append(rows, subjectsMatrixRow{subject: subject, scope: scope, coverage: coverage})
+	}
+	return rows
+}
+
+// printSubjectsMatrix prints the grid with a row per subject and scope and a column per verb, where a granted verb is
+// marked with an x, a verb granted on named objects only with a tilde, and a verb which is not granted with a dash.
+func printSubjectsMatrix(out io.Writer, groupResource schema.GroupResource, verbs []string, rows []subjectsMatrixRow) {
+	if len(rows) == 0 {
+		_, _ = fmt.Fprintf(out, "No subjects are granted any verb on %s\n", groupResource)
+		return
+	}
+	_, _ = fmt.Fprintf(out, "Verbs granted on %s:\n\n", groupResource)
+
+	wr := new(tabwriter.Writer)
+	wr.Init(out, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintf(wr, "SUBJECT\tTYPE\tSA-NAMESPACE\tSCOPE\t%s\n", strings.Join(verbs, "\t"))
+	for _, r := range rows {
+		cells := make([]string, len(r.coverage))
+		for i, c := range r.coverage {
+			switch {
+			case len(c.bindings) > 0:
+				cells[i] = "x"
+			case len(c.partialBindings) > 0:
+				cells[i] = "~"
+			default:
+				cells[i] = "-"
+			}
+		}
+		_, _ = fmt.Fprintf(wr, "%s\t%s\t%s\t%s\t%s\n", r.subject.Name, r.subject.Kind, r.subject.Namespace, r.scope, strings.Join(cells, "\t"))
+	}
+	_ = wr.Flush()
+}
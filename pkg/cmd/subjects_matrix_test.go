@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+)
+
+func TestMatrixWhoCan_runSubjects(t *testing.T) {
+	roles := []runtime.Object{
+		&rbac.Role{ObjectMeta: meta.ObjectMeta{Name: "secret-reader", Namespace: "foo"},
+			Rules: []rbac.PolicyRule{{APIGroups: []string{""}, Verbs: []string{"get", "list", "watch"}, Resources: []string{"secrets"}}}},
+		&rbac.Role{ObjectMeta: meta.ObjectMeta{Name: "tls-rotator", Namespace: "foo"},
+			Rules: []rbac.PolicyRule{
+				{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"secrets"}},
+				{APIGroups: []string{""}, Verbs: []string{"update"}, Resources: []string{"secrets"}, ResourceNames: []string{"tls"}},
+			}},
+		&rbac.Role{ObjectMeta: meta.ObjectMeta{Name: "pod-reader", Namespace: "foo"},
+			Rules: []rbac.PolicyRule{{APIGroups: []string{""}, Verbs: []string{"get"}, Resources: []string{"pods"}}}},
+		&rbac.ClusterRole{ObjectMeta: meta.ObjectMeta{Name: "secret-editor"},
+			Rules: []rbac.PolicyRule{{APIGroups: []string{""}, Verbs: []string{"*"}, Resources: []string{"secrets"}}}},
+	}
+
+	data := []struct {
+		scenario string
+		bindings []runtime.Object
+		output   string
+	}{
+		{
+			scenario: "Should print verbs granted to each subject per scope",
+			bindings: []runtime.Object{
+				&rbac.RoleBinding{ObjectMeta: meta.ObjectMeta{Name: "jane-reads-secrets", Namespace: "foo"},
+					RoleRef: rbac.RoleRef{Kind: "Role", Name: "secret-reader"}, Subjects: []rbac.Subject{{Kind: rbac.UserKind, Name: "jane"}}},
+				&rbac.RoleBinding{ObjectMeta: meta.ObjectMeta{Name: "deployer-rotates-tls", Namespace: "foo"},
+					RoleRef: rbac.RoleRef{Kind: "Role", Name: "tls-rotator"}, Subjects: []rbac.Subject{{Kind: rbac.ServiceAccountKind, Name: "deployer", Namespace: "ci"}}},
+				&rbac.RoleBinding{ObjectMeta: meta.ObjectMeta{Name: "bob-reads-pods", Namespace: "foo"},
+					RoleRef: rbac.RoleRef{Kind: "Role", Name: "pod-reader"}, Subjects: []rbac.Subject{{Kind: rbac.UserKind, Name: "bob"}}},
+				&rbac.ClusterRoleBinding{ObjectMeta: meta.ObjectMeta{Name: "ops-edits-secrets"},
+					RoleRef: rbac.RoleRef{Kind: "ClusterRole", Name: "secret-editor"}, Subjects: []rbac.Subject{{Kind: rbac.GroupKind, Name: "ops"}}},
+			},
+			output: `Verbs granted on secrets:
+
+SUBJECT   TYPE            SA-NAMESPACE  SCOPE         get  list  watch  create  update  delete
+ops       Group                         cluster-wide  x    x     x      x       x       x
+deployer  ServiceAccount  ci            foo           x    -     -      -       ~       -
+jane      User                          foo           x    x     x      -       -       -
+`,
+		},
+		{
+			scenario: "Should print no subjects when no verb is granted",
+			bindings: []runtime.Object{
+				&rbac.RoleBinding{ObjectMeta: meta.ObjectMeta{Name: "bob-reads-pods", Namespace: "foo"},
+					RoleRef: rbac.RoleRef{Kind: "Role", Name: "pod-reader"}, Subjects: []rbac.Subject{{Kind: rbac.UserKind, Name: "bob"}}},
+			},
+			output: "No subjects are granted any verb on secrets\n",
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			client := fake.NewSimpleClientset(append(append([]runtime.Object{}, roles...), tt.bindings...)...)
+			resourceResolver := new(resourceResolverMock)
+			resourceResolver.On("SupportedVerbs", "secrets").Return(schema.GroupResource{Resource: "secrets"},
+				[]string{"get", "list", "watch", "create", "update", "delete"}, nil)
+			streams, _, out, _ := clioptions.NewTestIOStreams()
+			o := &matrixWhoCan{resource: "secrets", subjects: true, namespace: "foo",
+				client: client, resourceResolver: resourceResolver, IOStreams: streams}
+
+			// when
+			err := o.runSubjects()
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.output, out.String())
+		})
+	}
+}
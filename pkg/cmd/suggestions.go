@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	apismeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxSuggestions limits the number of similar resource names suggested for a resource that was not found.
+const maxSuggestions = 3
+
+// resourceNotFoundError is returned when the server doesn't have the requested resource type.
+// It carries the names of similar resources which the user might have meant.
+type resourceNotFoundError struct {
+	resource    string
+	suggestions []string
+	// servedAs holds the fully-qualified names of resources with the same name in other API group versions.
+	servedAs []string
+}
+
+func (e *resourceNotFoundError) Error() string {
+	msg := fmt.Sprintf("the server doesn't have a resource type \"%s\"", e.resource)
+	if len(e.servedAs) > 0 {
+		msg = fmt.Sprintf("%s, but a resource of the same name is served as %s", msg, strings.Join(e.servedAs, ", "))
+	}
+	return msg
+}
+
+// withSuggestions appends the suggested resource names to the given error if it is a resourceNotFoundError.
+func withSuggestions(err error) error {
+	if nf, ok := err.(*resourceNotFoundError); ok && len(nf.suggestions) > 0 {
+		return fmt.Errorf("%v, did you mean: %s?", err, strings.Join(nf.suggestions, ", "))
+	}
+	return err
+}
+
+// suggestResources returns the indexed resource names which are closest to the given name by edit distance.
+// Only names with the given prefix are considered. Names of sub-resources are considered only if the prefix
+// is a resource, such as `pods/`.
+func suggestResources(index map[string]apismeta.APIResource, name, prefix string) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
+
+	maxDistance := maxSuggestionDistance(name)
+
+	var candidates []candidate
+	for key := range index {
+		if !strings.HasPrefix(key, prefix) || (prefix == "" && strings.Contains(key, "/")) {
+			continue
+		}
+		if d := editDistance(name, key); d <= maxDistance {
+			candidates = append(candidates, candidate{name: key, distance: d})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].name < candidates[j].name
+	})
+
+	var suggestions []string
+	for i := 0; i < len(candidates) && i < maxSuggestions; i++ {
+		suggestions = append(suggestions, candidates[i].name)
+	}
+	return suggestions
+}
+
+// suggestVerb returns the verb closest to the given one by edit distance among the supported verbs,
+// or "" if none of them is close enough.
+func suggestVerb(verb string, supported []string) string {
+	suggestion := ""
+	best := maxSuggestionDistance(verb) + 1
+	for _, v := range supported {
+		if d := editDistance(verb, v); d < best || (d == best && v < suggestion) {
+			suggestion, best = v, d
+		}
+	}
+	return suggestion
+}
+
+// maxSuggestionDistance returns the maximum edit distance of a suggestion for the given name,
+// which allows roughly one typo for every three characters.
+func maxSuggestionDistance(name string) int {
+	maxDistance := len(name) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+	return maxDistance
+}
+
+// editDistance returns the Levenshtein distance between the given strings.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+// isTerminal returns `true` if the given stream is attached to a terminal.
+func isTerminal(stream interface{}) bool {
+	f, ok := stream.(*os.File)
+	if !ok {
+		return false
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	apismeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+func TestSuggestResources(t *testing.T) {
+	index := map[string]apismeta.APIResource{
+		"pods":                   {Name: "pods"},
+		"po":                     {Name: "pods"},
+		"pods/log":               {Name: "pods/log"},
+		"pods/exec":              {Name: "pods/exec"},
+		"deployments":            {Name: "deployments"},
+		"deployments.apps":       {Name: "deployments"},
+		"deploy":                 {Name: "deployments"},
+		"daemonsets":             {Name: "daemonsets"},
+		"daemonsets.apps":        {Name: "daemonsets"},
+		"ds":                     {Name: "daemonsets"},
+		"deployments/scale":      {Name: "deployments/scale"},
+		"deployments.apps/scale": {Name: "deployments/scale"},
+	}
+
+	data := []struct {
+		scenario    string
+		name        string
+		prefix      string
+		suggestions []string
+	}{
+		{
+			scenario:    "Should suggest resource with transposed letters",
+			name:        "deploymnets",
+			suggestions: []string{"deployments"},
+		},
+		{
+			scenario:    "Should suggest resources ordered by distance",
+			name:        "daemonset",
+			suggestions: []string{"daemonsets"},
+		},
+		{
+			scenario:    "Should suggest sub-resources of the resource",
+			name:        "pods/lgo",
+			prefix:      "pods/",
+			suggestions: []string{"pods/log"},
+		},
+		{
+			scenario: "Should not suggest dissimilar resources",
+			name:     "secrets",
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			assert.Equal(t, tt.suggestions, suggestResources(index, tt.name, tt.prefix))
+		})
+	}
+}
+
+func TestSuggestVerb(t *testing.T) {
+	verbs := []string{"get", "list", "approve", "sign"}
+
+	assert.Equal(t, "approve", suggestVerb("aprove", verbs))
+	assert.Equal(t, "approve", suggestVerb("approv", verbs))
+	assert.Equal(t, "get", suggestVerb("gte", verbs))
+	assert.Equal(t, "", suggestVerb("escalate", verbs))
+	assert.Equal(t, "", suggestVerb("get", nil))
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("pods", "pods"))
+	assert.Equal(t, 1, editDistance("pod", "pods"))
+	assert.Equal(t, 2, editDistance("deploymnets", "deployments"))
+	assert.Equal(t, 4, editDistance("", "pods"))
+}
+
+func TestWithSuggestions(t *testing.T) {
+	assert.Equal(t, errors.New("the server doesn't have a resource type \"deploymnets\", did you mean: deployments, deploy?"),
+		withSuggestions(&resourceNotFoundError{resource: "deploymnets", suggestions: []string{"deployments", "deploy"}}))
+	assert.Equal(t, &resourceNotFoundError{resource: "secrtes"}, withSuggestions(&resourceNotFoundError{resource: "secrtes"}))
+	assert.Equal(t, errors.New("failed"), withSuggestions(errors.New("failed")))
+}
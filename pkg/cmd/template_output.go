@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+// templateOutputFormats lists the formats of the --output flag which render the result with a template,
+// given either after the format or by the --template flag, like for kubectl.
+var templateOutputFormats = []string{"jsonpath=TEMPLATE", "jsonpath-file=FILE", "go-template=TEMPLATE", "go-template-file=FILE"}
+
+// reportTemplateOutputFormat is the output format selected by --report-template.
+const reportTemplateOutputFormat = "go-template-file"
+
+// applyReportTemplate selects the go-template-file output format with the --report-template file, if given.
+func (w *whoCan) applyReportTemplate() error {
+	if w.reportTemplate == "" {
+		return nil
+	}
+	if w.outputFormat != "" || w.template != "" {
+		return errors.New("--report-template cannot be used with --output or --template")
+	}
+	w.outputFormat, w.template = reportTemplateOutputFormat, w.reportTemplate
+	return nil
+}
+
+// templatePrinter returns the printer of the template output format selected by --output, such as jsonpath=TEMPLATE
+// or go-template=TEMPLATE, as built by the print flags of kubectl. It returns false if the format is not a template format.
+func (w *whoCan) templatePrinter() (printers.ResourcePrinter, bool, error) {
+	flags := &clioptions.KubeTemplatePrintFlags{
+		JSONPathPrintFlags:   &clioptions.JSONPathPrintFlags{TemplateArgument: &w.template, AllowMissingKeys: &w.allowMissingTemplateKeys},
+		GoTemplatePrintFlags: &clioptions.GoTemplatePrintFlags{TemplateArgument: &w.template, AllowMissingKeys: &w.allowMissingTemplateKeys},
+	}
+	printer, err := flags.ToPrinter(w.outputFormat)
+	if clioptions.IsNoCompatiblePrinterError(err) {
+		return nil, false, nil
+	}
+	return printer, true, err
+}
+
+// printTemplate renders the same document as the json output format with the given template printer,
+// such that the template refers to its fields, e.g. {.roleBindings[*].subjects[*].name} or {{.query.verb}}.
+func (w *whoCan) printTemplate(out io.Writer, printer printers.ResourcePrinter, result Result) error {
+	doc, err := result.convert(w.outputVersion)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("marshalling result: %v", err)
+	}
+	obj := &unstructured.Unstructured{}
+	if err := json.Unmarshal(data, &obj.Object); err != nil {
+		return fmt.Errorf("unmarshalling result: %v", err)
+	}
+
+	return printer.PrintObj(obj, out)
+}
@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"os"
+	"testing"
+)
+
+func TestWhoCan_printResult_Template(t *testing.T) {
+	roleBindings := []rbac.RoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Alice-can-read-secrets", Namespace: "default"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "secret-reader"},
+			Subjects:   []rbac.Subject{{Name: "Alice", Kind: "User"}, {Name: "ops", Kind: "Group"}},
+		},
+	}
+	clusterRoleBindings := []rbac.ClusterRoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Bob-can-read-all"},
+			RoleRef:    rbac.RoleRef{Kind: "ClusterRole", Name: "view"},
+			Subjects:   []rbac.Subject{{Name: "Bob", Kind: "User"}},
+		},
+	}
+
+	data := []struct {
+		scenario         string
+		outputFormat     string
+		template         string
+		allowMissingKeys bool
+
+		output string
+		err    string
+	}{
+		{
+			scenario:     "Should print fields selected by template given with output format",
+			outputFormat: "jsonpath={.roleBindings[*].subjects[*].name}",
+			output:       "Alice ops",
+		},
+		{
+			scenario:     "Should print fields selected by template given with --template",
+			outputFormat: "jsonpath",
+			template:     `{range .clusterRoleBindings[*]}{.name}{"\t"}{.roleRef.name}{"\n"}{end}`,
+			output:       "Bob-can-read-all\tview\n",
+		},
+		{
+			scenario:     "Should print result rendered by Go template",
+			outputFormat: `go-template={{.query.verb}} {{.query.resource}}:{{range .roleBindings}}{{range .subjects}} {{.kind}}/{{.name}}{{end}}{{end}}`,
+			output:       "get secrets: User/Alice Group/ops",
+		},
+		{
+			scenario:     "Should print result rendered by Go template given with --template",
+			outputFormat: "go-template",
+			template:     `{{len .clusterRoleBindings}}`,
+			output:       "1",
+		},
+		{
+			scenario:         "Should print nothing for missing keys when allowed",
+			outputFormat:     "jsonpath={.query.resourceName}",
+			allowMissingKeys: true,
+			output:           "",
+		},
+		{
+			scenario:     "Should return error for missing keys when not allowed",
+			outputFormat: "jsonpath={.query.resourceName}",
+			err:          "resourceName is not found",
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			streams, _, out, _ := clioptions.NewTestIOStreams()
+			wc := whoCan{
+				verb:                     "get",
+				resource:                 "secrets",
+				namespacedResource:       true,
+				namespace:                "default",
+				outputFormat:             tt.outputFormat,
+				outputVersion:            outputVersionV1,
+				template:                 tt.template,
+				allowMissingTemplateKeys: tt.allowMissingKeys,
+				IOStreams:                streams,
+			}
+
+			// when
+			err := wc.printResult(wc.newResult(roleBindings, clusterRoleBindings, nil))
+
+			// then
+			if tt.err != "" {
+				assert.Contains(t, err.Error(), tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.output, out.String())
+			assert.Equal(t, tt.err == "", wc.resultPrinted)
+		})
+	}
+}
+
+func TestWhoCan_applyReportTemplate(t *testing.T) {
+	data := []struct {
+		scenario       string
+		reportTemplate string
+		outputFormat   string
+		template       string
+
+		expectedOutputFormat string
+		expectedTemplate     string
+		expectedErr          error
+	}{
+		{
+			scenario: "Should keep output format without report template",
+		},
+		{
+			scenario:             "Should select go-template-file output with report template",
+			reportTemplate:       "report.tmpl",
+			expectedOutputFormat: "go-template-file",
+			expectedTemplate:     "report.tmpl",
+		},
+		{
+			scenario:             "Should return error when report template is given with output format",
+			reportTemplate:       "report.tmpl",
+			outputFormat:         outputJSON,
+			expectedOutputFormat: outputJSON,
+			expectedErr:          errors.New("--report-template cannot be used with --output or --template"),
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			wc := whoCan{reportTemplate: tt.reportTemplate, outputFormat: tt.outputFormat, template: tt.template}
+
+			// when
+			err := wc.applyReportTemplate()
+
+			// then
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedOutputFormat, wc.outputFormat)
+			assert.Equal(t, tt.expectedTemplate, wc.template)
+		})
+	}
+}
+
+func TestWhoCan_printResult_ReportTemplate(t *testing.T) {
+	// given
+	f, err := ioutil.TempFile("", "report")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(`Who can {{.query.verb}} {{.query.resource}}
+{{range .roleBindings}}{{$b := .}}{{range .subjects}}- {{.kind}} {{.name}} through {{$b.name}}
+{{end}}{{end}}{{range .warnings}}Warning: {{.}}
+{{end}}`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	roleBindings := []rbac.RoleBinding{
+		{
+			ObjectMeta: meta.ObjectMeta{Name: "Alice-can-read-secrets", Namespace: "default"},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: "secret-reader"},
+			Subjects:   []rbac.Subject{{Name: "Alice", Kind: "User"}},
+		},
+	}
+	streams, _, out, _ := clioptions.NewTestIOStreams()
+	wc := whoCan{
+		verb:                     "get",
+		resource:                 "secrets",
+		namespacedResource:       true,
+		namespace:                "default",
+		outputVersion:            outputVersionV1,
+		reportTemplate:           f.Name(),
+		allowMissingTemplateKeys: true,
+		IOStreams:                streams,
+	}
+	require.NoError(t, wc.applyReportTemplate())
+
+	// when
+	err = wc.printResult(wc.newResult(roleBindings, nil, []string{"list roles"}))
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "Who can get secrets\n- User Alice through Alice-can-read-secrets\nWarning: list roles\n", out.String())
+}
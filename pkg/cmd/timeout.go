@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+// requestTimeouts bounds each request to the API server by the --request-timeout of a single request, and by the
+// deadline given by the --timeout of the whole operation, whichever is reached first. A request which times out
+// fails on its own, so that a scan which tolerates failing requests, such as the access checks, carries on with
+// the requests left within the deadline.
+//
+// The clients are created before the flags are parsed, hence the request timeout is read from the flag when
+// a request is sent, rather than being set as the Timeout of the REST config.
+type requestTimeouts struct {
+	requestTimeout *string
+
+	mu       sync.RWMutex
+	deadline time.Time
+	clock    clock.Clock
+}
+
+func newRequestTimeouts(requestTimeout *string, clock clock.Clock) *requestTimeouts {
+	return &requestTimeouts{
+		requestTimeout: requestTimeout,
+		clock:          clock,
+	}
+}
+
+// start validates the --request-timeout and starts the given timeout of the whole operation. A zero timeout means
+// that the operation has no deadline.
+func (t *requestTimeouts) start(timeout time.Duration) error {
+	if t == nil {
+		return nil
+	}
+	if _, err := t.perRequest(); err != nil {
+		return err
+	}
+	if timeout < 0 {
+		return fmt.Errorf("--timeout must not be negative, got %v", timeout)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.deadline = time.Time{}
+	if timeout > 0 {
+		t.deadline = t.clock.Now().Add(timeout)
+	}
+	return nil
+}
+
+// perRequest parses the --request-timeout, which like for kubectl is either a duration or a number of seconds.
+func (t *requestTimeouts) perRequest() (time.Duration, error) {
+	if t.requestTimeout == nil || *t.requestTimeout == "" {
+		return 0, nil
+	}
+	if seconds, err := strconv.Atoi(*t.requestTimeout); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	timeout, err := time.ParseDuration(*t.requestTimeout)
+	if err != nil || timeout < 0 {
+		return 0, fmt.Errorf("invalid --request-timeout \"%s\", expected a non-negative duration such as 1s, 2m or 3h", *t.requestTimeout)
+	}
+	return timeout, nil
+}
+
+// timeout returns the time left for a request sent now, or zero if the request is not bounded.
+func (t *requestTimeouts) timeout() time.Duration {
+	timeout, _ := t.perRequest()
+	t.mu.RLock()
+	deadline := t.deadline
+	t.mu.RUnlock()
+	if deadline.IsZero() {
+		return timeout
+	}
+	left := deadline.Sub(t.clock.Now())
+	if left <= 0 {
+		// The deadline has passed, hence the request is cancelled right away.
+		left = time.Nanosecond
+	}
+	if timeout == 0 || left < timeout {
+		return left
+	}
+	return timeout
+}
+
+// wrap returns a RoundTripper which bounds the requests sent with the given RoundTripper.
+// It is meant to be set as the WrapTransport of the REST config used to build the clients.
+func (t *requestTimeouts) wrap(rt http.RoundTripper) http.RoundTripper {
+	return &timeoutRoundTripper{delegate: rt, timeouts: t}
+}
+
+type timeoutRoundTripper struct {
+	delegate http.RoundTripper
+	timeouts *requestTimeouts
+}
+
+func (rt *timeoutRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	timeout := rt.timeouts.timeout()
+	if timeout == 0 {
+		return rt.delegate.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	resp, err := rt.delegate.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// The response body is still read after the round trip, hence the request is cancelled when it is closed.
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
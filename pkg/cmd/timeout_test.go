@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRequestTimeouts_SlowRequest(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/slow") {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"kind": "Namespace", "apiVersion": "v1", "metadata": {"name": "fast"}}`))
+	}))
+	defer server.Close()
+
+	requestTimeout := "100ms"
+	timeouts := newRequestTimeouts(&requestTimeout, clock.RealClock{})
+	require.NoError(t, timeouts.start(time.Minute))
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL, WrapTransport: timeouts.wrap})
+	require.NoError(t, err)
+
+	// when
+	_, slowErr := client.CoreV1().Namespaces().Get("slow", meta.GetOptions{})
+	fast, fastErr := client.CoreV1().Namespaces().Get("fast", meta.GetOptions{})
+
+	// then
+	assert.Error(t, slowErr)
+	assert.NoError(t, fastErr)
+	assert.Equal(t, "fast", fast.Name)
+}
+
+func TestNewDiscoveryClient(t *testing.T) {
+	// given
+	var slow, requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if atomic.LoadInt32(&slow) == 1 {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api":
+			_, _ = w.Write([]byte(`{"kind": "APIVersions", "versions": ["v1"]}`))
+		case "/apis":
+			_, _ = w.Write([]byte(`{"kind": "APIGroupList", "groups": []}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cacheDir, err := ioutil.TempDir("", "discovery")
+	require.NoError(t, err)
+	defer os.RemoveAll(cacheDir)
+	defer func(parentDir string) { discoveryCacheParentDir = parentDir }(discoveryCacheParentDir)
+	discoveryCacheParentDir = filepath.Join(cacheDir, "discovery")
+	httpCacheDir := filepath.Join(cacheDir, "http-cache")
+	configFlags := &clioptions.ConfigFlags{CacheDir: &httpCacheDir}
+
+	requestTimeout := "100ms"
+	timeouts := newRequestTimeouts(&requestTimeout, clock.RealClock{})
+	require.NoError(t, timeouts.start(time.Minute))
+	config := &rest.Config{Host: server.URL, WrapTransport: timeouts.wrap}
+
+	t.Run("Should bound discovery requests by request timeout", func(t *testing.T) {
+		// given
+		atomic.StoreInt32(&slow, 1)
+		defer atomic.StoreInt32(&slow, 0)
+		client, err := newDiscoveryClient(configFlags, config)
+		require.NoError(t, err)
+
+		// when
+		_, err = client.ServerGroups()
+
+		// then
+		assert.Error(t, err)
+	})
+
+	t.Run("Should reuse API resources cached on disk by previous runs", func(t *testing.T) {
+		// given
+		client, err := newDiscoveryClient(configFlags, config)
+		require.NoError(t, err)
+		_, err = client.ServerGroups()
+		require.NoError(t, err)
+		requestsBefore := atomic.LoadInt32(&requests)
+
+		// when
+		cachedClient, err := newDiscoveryClient(configFlags, config)
+		require.NoError(t, err)
+		groups, err := cachedClient.ServerGroups()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "v1", groups.Groups[0].PreferredVersion.Version)
+		assert.Equal(t, requestsBefore, atomic.LoadInt32(&requests))
+		assert.False(t, cachedClient.Fresh())
+		assert.FileExists(t, filepath.Join(discoveryCacheParentDir, discoveryCacheHostDir(server.URL), "servergroups.json"))
+	})
+}
+
+func TestRequestTimeouts_timeout(t *testing.T) {
+	now := time.Date(2019, 6, 12, 12, 30, 0, 0, time.UTC)
+
+	data := []struct {
+		scenario       string
+		requestTimeout string
+		timeout        time.Duration
+		elapsed        time.Duration
+
+		expectedTimeout time.Duration
+		expectedErr     error
+	}{
+		{
+			scenario:        "Should not bound requests without timeouts",
+			requestTimeout:  "0",
+			expectedTimeout: 0,
+		},
+		{
+			scenario:        "Should bound requests by request timeout given in seconds",
+			requestTimeout:  "5",
+			expectedTimeout: 5 * time.Second,
+		},
+		{
+			scenario:        "Should bound requests by request timeout within overall timeout",
+			requestTimeout:  "5s",
+			timeout:         time.Minute,
+			elapsed:         30 * time.Second,
+			expectedTimeout: 5 * time.Second,
+		},
+		{
+			scenario:        "Should bound requests by time left of overall timeout",
+			requestTimeout:  "5s",
+			timeout:         time.Minute,
+			elapsed:         58 * time.Second,
+			expectedTimeout: 2 * time.Second,
+		},
+		{
+			scenario:        "Should bound requests by time left of overall timeout without request timeout",
+			requestTimeout:  "0",
+			timeout:         time.Minute,
+			elapsed:         20 * time.Second,
+			expectedTimeout: 40 * time.Second,
+		},
+		{
+			scenario:        "Should cancel requests after overall timeout",
+			requestTimeout:  "5s",
+			timeout:         time.Minute,
+			elapsed:         2 * time.Minute,
+			expectedTimeout: time.Nanosecond,
+		},
+		{
+			scenario:       "Should return error when request timeout is invalid",
+			requestTimeout: "soon",
+			expectedErr:    errors.New("invalid --request-timeout \"soon\", expected a non-negative duration such as 1s, 2m or 3h"),
+		},
+		{
+			scenario:       "Should return error when overall timeout is negative",
+			requestTimeout: "0",
+			timeout:        -time.Second,
+			expectedErr:    errors.New("--timeout must not be negative, got -1s"),
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			fakeClock := clock.NewFakeClock(now)
+			timeouts := newRequestTimeouts(&tt.requestTimeout, fakeClock)
+
+			// when
+			err := timeouts.start(tt.timeout)
+			fakeClock.Step(tt.elapsed)
+
+			// then
+			assert.Equal(t, tt.expectedErr, err)
+			if err == nil {
+				assert.Equal(t, tt.expectedTimeout, timeouts.timeout())
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	rbac "k8s.io/api/rbac/v1"
+)
+
+// parseVerbs splits a VERB given as a comma-separated list of verbs, such as `get,list,watch`.
+func parseVerbs(verb string) ([]string, error) {
+	verbs := strings.Split(verb, ",")
+	for _, v := range verbs {
+		if v == "" {
+			return nil, fmt.Errorf("invalid verb \"%s\", expected a verb or a comma-separated list of verbs such as get,list,watch", verb)
+		}
+	}
+	return verbs, nil
+}
+
+// getBindingsForVerbs matches the bindings for each of the verbs separately and aggregates them.
+//
+// By default every subject granted at least one of the verbs is kept. With --require-all-verbs only the subjects
+// granted every verb, possibly through different bindings, are kept.
+func (w *whoCan) getBindingsForVerbs() ([]rbac.RoleBinding, []rbac.ClusterRoleBinding, error) {
+	defer func(verb string) { w.verb = verb }(w.verb)
+
+	return w.aggregateBindings(len(w.verbs), func(i int) {
+		w.verb = w.verbs[i]
+	}, w.requireAllVerbs)
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+
+	rbac "k8s.io/api/rbac/v1"
+)
+
+func TestParseVerbs(t *testing.T) {
+	data := []struct {
+		scenario string
+		verb     string
+
+		expectedVerbs []string
+		expectedErr   error
+	}{
+		{
+			scenario:      "Should parse single verb",
+			verb:          "get",
+			expectedVerbs: []string{"get"},
+		},
+		{
+			scenario:      "Should parse comma-separated verbs",
+			verb:          "get,list,watch",
+			expectedVerbs: []string{"get", "list", "watch"},
+		},
+		{
+			scenario:    "Should return error when a verb is empty",
+			verb:        "get,,watch",
+			expectedErr: errors.New("invalid verb \"get,,watch\", expected a verb or a comma-separated list of verbs such as get,list,watch"),
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// when
+			verbs, err := parseVerbs(tt.verb)
+
+			// then
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedVerbs, verbs)
+		})
+	}
+}
+
+func TestWhoCan_getBindingsForVerbs(t *testing.T) {
+	const namespace = "foo"
+
+	newRole := func(name string, verbs ...string) *rbac.Role {
+		return &rbac.Role{
+			ObjectMeta: meta.ObjectMeta{Name: name, Namespace: namespace},
+			Rules: []rbac.PolicyRule{
+				{APIGroups: []string{""}, Verbs: verbs, Resources: []string{"secrets"}},
+			},
+		}
+	}
+	newRoleBinding := func(name, role string, subjects ...string) *rbac.RoleBinding {
+		rb := &rbac.RoleBinding{
+			ObjectMeta: meta.ObjectMeta{Name: name, Namespace: namespace},
+			RoleRef:    rbac.RoleRef{Kind: "Role", Name: role},
+		}
+		for _, s := range subjects {
+			rb.Subjects = append(rb.Subjects, rbac.Subject{Kind: rbac.UserKind, Name: s})
+		}
+		return rb
+	}
+	subjectsOf := func(rbs []rbac.RoleBinding) map[string][]string {
+		subjects := make(map[string][]string)
+		for _, rb := range rbs {
+			for _, s := range rb.Subjects {
+				subjects[rb.Name] = append(subjects[rb.Name], s.Name)
+			}
+		}
+		return subjects
+	}
+
+	objects := []runtime.Object{
+		newRole("reader", "get", "list"),
+		newRole("watcher", "watch"),
+		newRole("viewer", "get", "list", "watch"),
+		newRole("getter", "get"),
+		newRole("deleter", "delete"),
+		newRoleBinding("rb-reader", "reader", "Alice", "Dave"),
+		newRoleBinding("rb-watcher", "watcher", "Alice"),
+		newRoleBinding("rb-viewer", "viewer", "Bob"),
+		newRoleBinding("rb-getter", "getter", "Carol"),
+		newRoleBinding("rb-deleter", "deleter", "Eve"),
+	}
+
+	data := []struct {
+		scenario        string
+		requireAllVerbs bool
+		expected        map[string][]string
+	}{
+		{
+			scenario: "Should return subjects who are granted any of the verbs",
+			expected: map[string][]string{
+				"rb-reader":  {"Alice", "Dave"},
+				"rb-viewer":  {"Bob"},
+				"rb-getter":  {"Carol"},
+				"rb-watcher": {"Alice"},
+			},
+		},
+		{
+			scenario:        "Should return subjects who are granted all the verbs",
+			requireAllVerbs: true,
+			expected: map[string][]string{
+				"rb-reader":  {"Alice"},
+				"rb-viewer":  {"Bob"},
+				"rb-watcher": {"Alice"},
+			},
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			client := fake.NewSimpleClientset(objects...)
+			wc := whoCan{
+				verb:            "get,list,watch",
+				verbs:           []string{"get", "list", "watch"},
+				requireAllVerbs: tt.requireAllVerbs,
+				resource:        "secrets",
+				namespace:       namespace,
+				clientRBAC:      client.RbacV1(),
+				rules:           make(map[role][]rbac.PolicyRule),
+				wildcards:       make(map[role]bool),
+			}
+
+			// when
+			roleBindings, clusterRoleBindings, err := wc.getBindingsForVerbs()
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, subjectsOf(roleBindings))
+			assert.Empty(t, clusterRoleBindings)
+			assert.Equal(t, "get,list,watch", wc.verb)
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	whatRolesUsage = `whatroles --subject KIND/NAME`
+	whatRolesLong  = `Shows the Roles and ClusterRoles to which a subject is bound, without listing the permissions they grant.
+
+A role is listed in the namespace of each RoleBinding which binds the subject, either directly or through a group which
+the subject implicitly belongs to. ClusterRoles bound by ClusterRoleBindings are listed as 'cluster-wide'.
+Like for checking an action, the RoleBindings of the current namespace are considered unless --namespace or
+--all-namespaces is given.
+
+KIND is one of 'User', 'Group' or 'ServiceAccount'. The NAME of a ServiceAccount is given as NAMESPACE:NAME.`
+	whatRolesExample = `  # List the roles to which the user "jane" is bound
+  kubectl who-can whatroles --subject User/jane
+
+  # List the roles to which the service account "deployer" of the namespace "ci" is bound
+  kubectl who-can whatroles --subject ServiceAccount/ci:deployer
+
+  # List the roles to which the user "jane" is bound in all namespaces
+  kubectl who-can whatroles --subject User/jane --all-namespaces`
+)
+
+type whatRolesWhoCan struct {
+	subject       string
+	namespace     string
+	allNamespaces bool
+
+	configFlags  *clioptions.ConfigFlags
+	clientConfig clientcmd.ClientConfig
+	client       kubernetes.Interface
+
+	clioptions.IOStreams
+}
+
+// boundRole is a Role or a ClusterRole bound to a subject in the namespace given by scope, or cluster-wide.
+type boundRole struct {
+	scope string
+	role
+}
+
+func NewCmdWhatRoles(client kubernetes.Interface, configFlags *clioptions.ConfigFlags, streams clioptions.IOStreams) *cobra.Command {
+	o := &whatRolesWhoCan{
+		configFlags:  configFlags,
+		clientConfig: configFlags.ToRawKubeConfigLoader(),
+		client:       client,
+		IOStreams:    streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:          whatRolesUsage,
+		Short:        "Show the roles to which a subject is bound",
+		Long:         whatRolesLong,
+		Example:      whatRolesExample,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if o.subject == "" {
+				return errors.New("--subject is required")
+			}
+			if err := o.complete(); err != nil {
+				return err
+			}
+			return o.run()
+		},
+	}
+
+	cmd.Flags().StringVar(&o.subject, "subject", "", "Subject given as KIND/NAME, such as User/jane or ServiceAccount/ci:deployer")
+	cmd.Flags().BoolVarP(&o.allNamespaces, "all-namespaces", "A", false,
+		"If true, consider the RoleBindings in all namespaces.")
+
+	return cmd
+}
+
+// complete resolves the namespace whose RoleBindings are considered the same way as when checking an action,
+// that is from the --all-namespaces flag, the --namespace flag, or the current context, in that order.
+func (o *whatRolesWhoCan) complete() (err error) {
+	o.namespace, err = resolveNamespace(o.configFlags, o.clientConfig, o.allNamespaces)
+	return
+}
+
+func (o *whatRolesWhoCan) run() error {
+	subject, err := parseSubject(o.subject)
+	if err != nil {
+		return err
+	}
+
+	rbl, err := o.client.RbacV1().RoleBindings(o.namespace).List(meta.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("listing RoleBindings: %v", err)
+	}
+	crbl, err := o.client.RbacV1().ClusterRoleBindings().List(meta.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("listing ClusterRoleBindings: %v", err)
+	}
+
+	bound := rolesOf(subject, rbl.Items, crbl.Items)
+	if len(bound) == 0 {
+		_, _ = fmt.Fprintf(o.Out, "No RoleBindings or ClusterRoleBindings found for %s\n", o.subject)
+		return nil
+	}
+
+	wr := new(tabwriter.Writer)
+	wr.Init(o.Out, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintln(wr, "NAMESPACE\tKIND\tROLE")
+	for _, r := range bound {
+		kind := "Role"
+		if r.isClusterRole {
+			kind = "ClusterRole"
+		}
+		_, _ = fmt.Fprintf(wr, "%s\t%s\t%s\n", r.scope, kind, r.name)
+	}
+	return wr.Flush()
+}
+
+// rolesOf returns the distinct roles bound to the given subject. The roles bound by RoleBindings are sorted by
+// namespace, followed by the ClusterRoles bound by ClusterRoleBindings. Within a namespace, ClusterRoles are
+// listed before Roles, each sorted by name.
+func rolesOf(subject rbac.Subject, roleBindings []rbac.RoleBinding, clusterRoleBindings []rbac.ClusterRoleBinding) []boundRole {
+	seen := make(map[boundRole]struct{})
+	var bound []boundRole
+	add := func(scope string, roleRef rbac.RoleRef, namespace string) {
+		r := boundRole{scope: scope, role: newRoleFromRef(&roleRef, namespace)}
+		if _, ok := seen[r]; ok {
+			return
+		}
+		seen[r] = struct{}{}
+		bound = append(bound, r)
+	}
+
+	for _, rb := range roleBindings {
+		if bindsSubject(rb.Subjects, subject) {
+			add(rb.Namespace, rb.RoleRef, rb.Namespace)
+		}
+	}
+	for _, crb := range clusterRoleBindings {
+		if bindsSubject(crb.Subjects, subject) {
+			add(clusterWide, crb.RoleRef, "")
+		}
+	}
+
+	sort.Slice(bound, func(i, j int) bool {
+		a, b := bound[i], bound[j]
+		if a.scope != b.scope {
+			if a.scope == clusterWide || b.scope == clusterWide {
+				return b.scope == clusterWide
+			}
+			return a.scope < b.scope
+		}
+		if a.isClusterRole != b.isClusterRole {
+			return a.isClusterRole
+		}
+		return a.name < b.name
+	})
+	return bound
+}
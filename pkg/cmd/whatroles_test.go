@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clioptions "k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+)
+
+func TestRolesOf(t *testing.T) {
+	jane := rbac.Subject{Kind: rbac.UserKind, Name: "jane"}
+	deployer := rbac.Subject{Kind: rbac.ServiceAccountKind, Name: "deployer", Namespace: "ci"}
+
+	roleBindings := []rbac.RoleBinding{
+		{ObjectMeta: meta.ObjectMeta{Name: "jane-pods", Namespace: "foo"},
+			RoleRef: rbac.RoleRef{Kind: "Role", Name: "pod-reader"}, Subjects: []rbac.Subject{jane}},
+		{ObjectMeta: meta.ObjectMeta{Name: "jane-edit", Namespace: "foo"},
+			RoleRef: rbac.RoleRef{Kind: "ClusterRole", Name: "edit"}, Subjects: []rbac.Subject{jane}},
+		{ObjectMeta: meta.ObjectMeta{Name: "jane-edit-again", Namespace: "foo"},
+			RoleRef: rbac.RoleRef{Kind: "ClusterRole", Name: "edit"}, Subjects: []rbac.Subject{jane}},
+		{ObjectMeta: meta.ObjectMeta{Name: "jane-view", Namespace: "bar"},
+			RoleRef: rbac.RoleRef{Kind: "ClusterRole", Name: "view"}, Subjects: []rbac.Subject{jane}},
+		{ObjectMeta: meta.ObjectMeta{Name: "ci-deploy", Namespace: "prod"},
+			RoleRef:  rbac.RoleRef{Kind: "Role", Name: "deployer"},
+			Subjects: []rbac.Subject{{Kind: rbac.GroupKind, Name: "system:serviceaccounts:ci"}}},
+	}
+	clusterRoleBindings := []rbac.ClusterRoleBinding{
+		{ObjectMeta: meta.ObjectMeta{Name: "jane-view"},
+			RoleRef: rbac.RoleRef{Kind: "ClusterRole", Name: "view"}, Subjects: []rbac.Subject{jane}},
+	}
+
+	data := []struct {
+		scenario string
+		subject  rbac.Subject
+		roles    []boundRole
+	}{
+		{
+			scenario: "Should return distinct roles bound by RoleBindings and ClusterRoleBindings",
+			subject:  jane,
+			roles: []boundRole{
+				{scope: "bar", role: role{name: "view", isClusterRole: true}},
+				{scope: "foo", role: role{name: "edit", isClusterRole: true}},
+				{scope: "foo", role: role{name: "pod-reader", namespace: "foo"}},
+				{scope: "cluster-wide", role: role{name: "view", isClusterRole: true}},
+			},
+		},
+		{
+			scenario: "Should return roles bound to groups of service account",
+			subject:  deployer,
+			roles: []boundRole{
+				{scope: "prod", role: role{name: "deployer", namespace: "prod"}},
+			},
+		},
+		{
+			scenario: "Should return no roles for unbound subject",
+			subject:  rbac.Subject{Kind: rbac.GroupKind, Name: "devs"},
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			assert.Equal(t, tt.roles, rolesOf(tt.subject, roleBindings, clusterRoleBindings))
+		})
+	}
+}
+
+func TestWhatRolesWhoCan_run(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&rbac.RoleBinding{ObjectMeta: meta.ObjectMeta{Name: "jane-edit", Namespace: "foo"},
+			RoleRef: rbac.RoleRef{Kind: "ClusterRole", Name: "edit"}, Subjects: []rbac.Subject{{Kind: rbac.UserKind, Name: "jane"}}},
+		&rbac.RoleBinding{ObjectMeta: meta.ObjectMeta{Name: "jane-pods", Namespace: "foo"},
+			RoleRef: rbac.RoleRef{Kind: "Role", Name: "pod-reader"}, Subjects: []rbac.Subject{{Kind: rbac.UserKind, Name: "jane"}}},
+		&rbac.ClusterRoleBinding{ObjectMeta: meta.ObjectMeta{Name: "jane-view"},
+			RoleRef: rbac.RoleRef{Kind: "ClusterRole", Name: "view"}, Subjects: []rbac.Subject{{Kind: rbac.UserKind, Name: "jane"}}},
+	)
+
+	data := []struct {
+		scenario string
+		subject  string
+		output   string
+		err      string
+	}{
+		{
+			scenario: "Should print roles of subject",
+			subject:  "User/jane",
+			output: `NAMESPACE     KIND         ROLE
+foo           ClusterRole  edit
+foo           Role         pod-reader
+cluster-wide  ClusterRole  view
+`,
+		},
+		{
+			scenario: "Should print message when subject is not bound",
+			subject:  "User/bob",
+			output:   "No RoleBindings or ClusterRoleBindings found for User/bob\n",
+		},
+		{
+			scenario: "Should return error when subject is invalid",
+			subject:  "bob",
+			err:      "invalid subject \"bob\", expected KIND/NAME",
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			streams, _, out, _ := clioptions.NewTestIOStreams()
+			o := &whatRolesWhoCan{subject: tt.subject, client: client, IOStreams: streams}
+
+			// when
+			err := o.run()
+
+			// then
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.output, out.String())
+		})
+	}
+}
+
+func TestWhatRolesWhoCan_complete(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&rbac.RoleBinding{ObjectMeta: meta.ObjectMeta{Name: "jane-edit", Namespace: "foo"},
+			RoleRef: rbac.RoleRef{Kind: "ClusterRole", Name: "edit"}, Subjects: []rbac.Subject{{Kind: rbac.UserKind, Name: "jane"}}},
+		&rbac.RoleBinding{ObjectMeta: meta.ObjectMeta{Name: "jane-pods", Namespace: "bar"},
+			RoleRef: rbac.RoleRef{Kind: "Role", Name: "pod-reader"}, Subjects: []rbac.Subject{{Kind: rbac.UserKind, Name: "jane"}}},
+		&rbac.ClusterRoleBinding{ObjectMeta: meta.ObjectMeta{Name: "jane-view"},
+			RoleRef: rbac.RoleRef{Kind: "ClusterRole", Name: "view"}, Subjects: []rbac.Subject{{Kind: rbac.UserKind, Name: "jane"}}},
+	)
+
+	data := []struct {
+		scenario         string
+		currentNamespace string
+		namespace        string
+		allNamespaces    bool
+
+		expectedNamespace string
+		output            string
+	}{
+		{
+			scenario:          "Should consider RoleBindings of namespace of current context",
+			currentNamespace:  "foo",
+			expectedNamespace: "foo",
+			output: `NAMESPACE     KIND         ROLE
+foo           ClusterRole  edit
+cluster-wide  ClusterRole  view
+`,
+		},
+		{
+			scenario:          "Should consider RoleBindings of namespace given by flag",
+			namespace:         "bar",
+			expectedNamespace: "bar",
+			output: `NAMESPACE     KIND         ROLE
+bar           Role         pod-reader
+cluster-wide  ClusterRole  view
+`,
+		},
+		{
+			scenario:          "Should consider RoleBindings of all namespaces",
+			namespace:         "bar",
+			allNamespaces:     true,
+			expectedNamespace: meta.NamespaceAll,
+			output: `NAMESPACE     KIND         ROLE
+bar           Role         pod-reader
+foo           ClusterRole  edit
+cluster-wide  ClusterRole  view
+`,
+		},
+	}
+
+	for _, tt := range data {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// given
+			clientConfig := new(clientConfigMock)
+			clientConfig.On("Namespace").Return(tt.currentNamespace, false, nil)
+			streams, _, out, _ := clioptions.NewTestIOStreams()
+			o := &whatRolesWhoCan{
+				subject:       "User/jane",
+				allNamespaces: tt.allNamespaces,
+				configFlags:   &clioptions.ConfigFlags{Namespace: &tt.namespace},
+				clientConfig:  clientConfig,
+				client:        client,
+				IOStreams:     streams,
+			}
+
+			// when
+			err := o.complete()
+			require.NoError(t, err)
+			err = o.run()
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedNamespace, o.namespace)
+			assert.Equal(t, tt.output, out.String())
+		})
+	}
+}